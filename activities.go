@@ -0,0 +1,176 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const ListActivities = "https://www.googleapis.com/youtube/v3/activities?part=snippet,contentDetails&channelId=%s&maxResults=50&key=%s%s"
+
+// Activity types reported in Activity.Snippet.Type.
+const (
+	ActivityUpload         = "upload"
+	ActivityPlaylistItem   = "playlistItem"
+	ActivityLike           = "like"
+	ActivityFavorite       = "favorite"
+	ActivitySubscription   = "subscription"
+	ActivityRecommendation = "recommendation"
+	ActivityBulletin       = "bulletin"
+	ActivityChannelItem    = "channelItem"
+	ActivitySocial         = "social"
+	ActivityPromotedItem   = "promotedItem"
+)
+
+// DefaultMaxActivities is the number of activities fetched when ActivityOptions.MaxResults is not set.
+const DefaultMaxActivities = 50
+
+// ActivityOptions controls how GetActivities pages through a channel's activity feed.
+type ActivityOptions struct {
+	// PublishedAfter and PublishedBefore limit the feed to a time range.
+	PublishedAfter  time.Time
+	PublishedBefore time.Time
+	// MaxResults caps the number of activities returned across all pages.
+	MaxResults int
+	// PageToken resumes the feed where a previous call stopped.
+	PageToken string
+}
+
+// ActivityResults contains the activities retrieved for a channel, newest first.
+type ActivityResults struct {
+	Items         []*Activity `bson:"items,omitempty" json:"items,omitempty"`
+	NextPageToken string      `bson:"nextPageToken,omitempty" json:"nextPageToken,omitempty"`
+}
+
+// Activity is a single event of a channel's activity feed. The ContentDetails field matching
+// Snippet.Type is set; the others are nil.
+type Activity struct {
+	Id      string `bson:"id,omitempty" json:"id,omitempty"`
+	Snippet *struct {
+		PublishedAt time.Time  `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+		ChannelId   string     `bson:"channelId,omitempty" json:"channelId,omitempty"`
+		Title       string     `bson:"title,omitempty" json:"title,omitempty"`
+		Description string     `bson:"description,omitempty" json:"description,omitempty"`
+		Thumbnails  Thumbnails `bson:"thumbnails,omitempty" json:"thumbnails,omitempty"`
+		Type        string     `bson:"type,omitempty" json:"type,omitempty"`
+	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
+	ContentDetails *struct {
+		Upload *struct {
+			VideoId string `bson:"videoId,omitempty" json:"videoId,omitempty"`
+		} `bson:"upload,omitempty" json:"upload,omitempty"`
+		PlaylistItem *struct {
+			ResourceId     *ResourceId `bson:"resourceId,omitempty" json:"resourceId,omitempty"`
+			PlaylistId     string      `bson:"playlistId,omitempty" json:"playlistId,omitempty"`
+			PlaylistItemId string      `bson:"playlistItemId,omitempty" json:"playlistItemId,omitempty"`
+		} `bson:"playlistItem,omitempty" json:"playlistItem,omitempty"`
+		Like *struct {
+			ResourceId *ResourceId `bson:"resourceId,omitempty" json:"resourceId,omitempty"`
+		} `bson:"like,omitempty" json:"like,omitempty"`
+		Subscription *struct {
+			ResourceId *ResourceId `bson:"resourceId,omitempty" json:"resourceId,omitempty"`
+		} `bson:"subscription,omitempty" json:"subscription,omitempty"`
+	} `bson:"contentDetails,omitempty" json:"contentDetails,omitempty"`
+}
+
+// Type returns the activity type, one of the Activity* constants.
+func (a *Activity) Type() string {
+	if a.Snippet == nil {
+		return ""
+	}
+	return a.Snippet.Type
+}
+
+// VideoId returns the video an upload, playlist addition, or like refers to, or "" for other activities.
+func (a *Activity) VideoId() string {
+	d := a.ContentDetails
+	if d == nil {
+		return ""
+	}
+	switch {
+	case d.Upload != nil:
+		return d.Upload.VideoId
+	case d.PlaylistItem != nil && d.PlaylistItem.ResourceId != nil:
+		return d.PlaylistItem.ResourceId.VideoId
+	case d.Like != nil && d.Like.ResourceId != nil:
+		return d.Like.ResourceId.VideoId
+	}
+	return ""
+}
+
+// OfType returns the activities whose type is one of types, keeping their order.
+func (r *ActivityResults) OfType(types ...string) []*Activity {
+	var matched []*Activity
+	for _, a := range r.Items {
+		for _, t := range types {
+			if a.Type() == t {
+				matched = append(matched, a)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// UploadedVideoIds returns the IDs of the videos uploaded in the feed, newest first.
+// The list can be passed straight to GetVideos.
+func (r *ActivityResults) UploadedVideoIds() []string {
+	var ids []string
+	for _, a := range r.OfType(ActivityUpload) {
+		if id := a.VideoId(); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// GetActivities retrieves a channel's recent activity, paging until opts.MaxResults activities are collected.
+// A page costs a single quota unit, which makes polling the feed a cheap way to detect new uploads.
+// Results are not cached, since the feed is typically polled for changes.
+func (yt *YoutubeApi) GetActivities(channelId string, opts ActivityOptions) (*ActivityResults, error) {
+	return yt.GetActivitiesContext(context.Background(), channelId, opts)
+}
+
+// GetActivitiesContext is like GetActivities but uses ctx for every page request.
+func (yt *YoutubeApi) GetActivitiesContext(ctx context.Context, channelId string, opts ActivityOptions) (*ActivityResults, error) {
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = DefaultMaxActivities
+	}
+
+	filter := ""
+	if !opts.PublishedAfter.IsZero() {
+		filter += "&publishedAfter=" + url.QueryEscape(opts.PublishedAfter.UTC().Format(time.RFC3339))
+	}
+	if !opts.PublishedBefore.IsZero() {
+		filter += "&publishedBefore=" + url.QueryEscape(opts.PublishedBefore.UTC().Format(time.RFC3339))
+	}
+
+	results := &ActivityResults{}
+	nextPage := opts.PageToken
+	for len(results.Items) < opts.MaxResults {
+		nextPageStr := filter
+		if nextPage != "" {
+			nextPageStr += fmt.Sprintf("&pageToken=%v", nextPage)
+		}
+		body, err := yt.httpGetRequest(ctx, fmt.Sprintf(ListActivities, url.QueryEscape(channelId), yt.apiKey, nextPageStr))
+		if err != nil {
+			return nil, err
+		}
+		page := &ActivityResults{}
+		if err := json.Unmarshal(body, page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal activities: %w", err)
+		}
+		results.Items = append(results.Items, page.Items...)
+
+		nextPage = page.NextPageToken
+		if nextPage == "" {
+			break
+		}
+	}
+	if len(results.Items) > opts.MaxResults {
+		results.Items = results.Items[:opts.MaxResults]
+	}
+	results.NextPageToken = nextPage
+	return results, nil
+}
@@ -0,0 +1,300 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: alaitube.proto
+
+package alaitubepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	YoutubeService_Search_FullMethodName       = "/alaitube.v1.YoutubeService/Search"
+	YoutubeService_SearchStream_FullMethodName = "/alaitube.v1.YoutubeService/SearchStream"
+	YoutubeService_GetVideos_FullMethodName    = "/alaitube.v1.YoutubeService/GetVideos"
+	YoutubeService_GetChannel_FullMethodName   = "/alaitube.v1.YoutubeService/GetChannel"
+	YoutubeService_GetUploads_FullMethodName   = "/alaitube.v1.YoutubeService/GetUploads"
+)
+
+// YoutubeServiceClient is the client API for YoutubeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// YoutubeService exposes a YoutubeApi client over gRPC. Results come from the client's cache when
+// they are fresh, so every caller shares its quota.
+type YoutubeServiceClient interface {
+	// Search returns the videos matching a query, with their details.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// SearchStream is like Search but sends every video as soon as its details are fetched,
+	// page after page.
+	SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Video], error)
+	// GetVideos returns the details of up to 50 videos.
+	GetVideos(ctx context.Context, in *GetVideosRequest, opts ...grpc.CallOption) (*GetVideosResponse, error)
+	// GetChannel returns a channel.
+	GetChannel(ctx context.Context, in *GetChannelRequest, opts ...grpc.CallOption) (*Channel, error)
+	// GetUploads streams the uploads of a channel, newest first, fetching one page of 50 videos at
+	// a time as the stream is consumed.
+	GetUploads(ctx context.Context, in *GetUploadsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Video], error)
+}
+
+type youtubeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewYoutubeServiceClient(cc grpc.ClientConnInterface) YoutubeServiceClient {
+	return &youtubeServiceClient{cc}
+}
+
+func (c *youtubeServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, YoutubeService_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *youtubeServiceClient) SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Video], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &YoutubeService_ServiceDesc.Streams[0], YoutubeService_SearchStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SearchRequest, Video]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type YoutubeService_SearchStreamClient = grpc.ServerStreamingClient[Video]
+
+func (c *youtubeServiceClient) GetVideos(ctx context.Context, in *GetVideosRequest, opts ...grpc.CallOption) (*GetVideosResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVideosResponse)
+	err := c.cc.Invoke(ctx, YoutubeService_GetVideos_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *youtubeServiceClient) GetChannel(ctx context.Context, in *GetChannelRequest, opts ...grpc.CallOption) (*Channel, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Channel)
+	err := c.cc.Invoke(ctx, YoutubeService_GetChannel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *youtubeServiceClient) GetUploads(ctx context.Context, in *GetUploadsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Video], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &YoutubeService_ServiceDesc.Streams[1], YoutubeService_GetUploads_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetUploadsRequest, Video]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type YoutubeService_GetUploadsClient = grpc.ServerStreamingClient[Video]
+
+// YoutubeServiceServer is the server API for YoutubeService service.
+// All implementations must embed UnimplementedYoutubeServiceServer
+// for forward compatibility.
+//
+// YoutubeService exposes a YoutubeApi client over gRPC. Results come from the client's cache when
+// they are fresh, so every caller shares its quota.
+type YoutubeServiceServer interface {
+	// Search returns the videos matching a query, with their details.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// SearchStream is like Search but sends every video as soon as its details are fetched,
+	// page after page.
+	SearchStream(*SearchRequest, grpc.ServerStreamingServer[Video]) error
+	// GetVideos returns the details of up to 50 videos.
+	GetVideos(context.Context, *GetVideosRequest) (*GetVideosResponse, error)
+	// GetChannel returns a channel.
+	GetChannel(context.Context, *GetChannelRequest) (*Channel, error)
+	// GetUploads streams the uploads of a channel, newest first, fetching one page of 50 videos at
+	// a time as the stream is consumed.
+	GetUploads(*GetUploadsRequest, grpc.ServerStreamingServer[Video]) error
+	mustEmbedUnimplementedYoutubeServiceServer()
+}
+
+// UnimplementedYoutubeServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedYoutubeServiceServer struct{}
+
+func (UnimplementedYoutubeServiceServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedYoutubeServiceServer) SearchStream(*SearchRequest, grpc.ServerStreamingServer[Video]) error {
+	return status.Errorf(codes.Unimplemented, "method SearchStream not implemented")
+}
+func (UnimplementedYoutubeServiceServer) GetVideos(context.Context, *GetVideosRequest) (*GetVideosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVideos not implemented")
+}
+func (UnimplementedYoutubeServiceServer) GetChannel(context.Context, *GetChannelRequest) (*Channel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChannel not implemented")
+}
+func (UnimplementedYoutubeServiceServer) GetUploads(*GetUploadsRequest, grpc.ServerStreamingServer[Video]) error {
+	return status.Errorf(codes.Unimplemented, "method GetUploads not implemented")
+}
+func (UnimplementedYoutubeServiceServer) mustEmbedUnimplementedYoutubeServiceServer() {}
+func (UnimplementedYoutubeServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeYoutubeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to YoutubeServiceServer will
+// result in compilation errors.
+type UnsafeYoutubeServiceServer interface {
+	mustEmbedUnimplementedYoutubeServiceServer()
+}
+
+func RegisterYoutubeServiceServer(s grpc.ServiceRegistrar, srv YoutubeServiceServer) {
+	// If the following call pancis, it indicates UnimplementedYoutubeServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&YoutubeService_ServiceDesc, srv)
+}
+
+func _YoutubeService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YoutubeServiceServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: YoutubeService_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YoutubeServiceServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _YoutubeService_SearchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(YoutubeServiceServer).SearchStream(m, &grpc.GenericServerStream[SearchRequest, Video]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type YoutubeService_SearchStreamServer = grpc.ServerStreamingServer[Video]
+
+func _YoutubeService_GetVideos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVideosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YoutubeServiceServer).GetVideos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: YoutubeService_GetVideos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YoutubeServiceServer).GetVideos(ctx, req.(*GetVideosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _YoutubeService_GetChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YoutubeServiceServer).GetChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: YoutubeService_GetChannel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YoutubeServiceServer).GetChannel(ctx, req.(*GetChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _YoutubeService_GetUploads_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetUploadsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(YoutubeServiceServer).GetUploads(m, &grpc.GenericServerStream[GetUploadsRequest, Video]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type YoutubeService_GetUploadsServer = grpc.ServerStreamingServer[Video]
+
+// YoutubeService_ServiceDesc is the grpc.ServiceDesc for YoutubeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var YoutubeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "alaitube.v1.YoutubeService",
+	HandlerType: (*YoutubeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Search",
+			Handler:    _YoutubeService_Search_Handler,
+		},
+		{
+			MethodName: "GetVideos",
+			Handler:    _YoutubeService_GetVideos_Handler,
+		},
+		{
+			MethodName: "GetChannel",
+			Handler:    _YoutubeService_GetChannel_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SearchStream",
+			Handler:       _YoutubeService_SearchStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetUploads",
+			Handler:       _YoutubeService_GetUploads_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "alaitube.proto",
+}
@@ -0,0 +1,5 @@
+// Package alaitubepb holds the protobuf messages and gRPC service definition of the alaitube API,
+// generated from alaitube.proto. The service is implemented by the grpcserver package.
+package alaitubepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative alaitube.proto
@@ -0,0 +1,339 @@
+package alaitube
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultStopwords are the English words dropped from tags when stopword removal is enabled.
+var DefaultStopwords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "by", "for", "from", "how", "in", "is", "it",
+	"of", "on", "or", "that", "the", "this", "to", "was", "what", "with", "you", "your",
+}
+
+// TagAnalyzer computes tag statistics over video results: frequencies, co-occurring pairs,
+// view-weighted rankings, and suggestions for a query. Tags are normalized before being
+// compared, so "Golang", "golang " and "GOLANG" count as the same tag by default.
+type TagAnalyzer struct {
+	foldCase  bool
+	stem      bool
+	hashtags  bool
+	stopwords map[string]bool
+}
+
+// TagAnalyzerOption configures a TagAnalyzer created with NewTagAnalyzer.
+type TagAnalyzerOption func(*TagAnalyzer)
+
+// WithCaseFolding sets whether tags differing only by case are merged. It is enabled by default.
+func WithCaseFolding(fold bool) TagAnalyzerOption {
+	return func(a *TagAnalyzer) {
+		a.foldCase = fold
+	}
+}
+
+// WithStemming merges tags whose words only differ by common English suffixes, such as
+// "tutorial" and "tutorials".
+func WithStemming(stem bool) TagAnalyzerOption {
+	return func(a *TagAnalyzer) {
+		a.stem = stem
+	}
+}
+
+// WithHashtags counts the hashtags of each video's title and description as tags of the video,
+// since many creators put their keywords in hashtags rather than tags.
+func WithHashtags(include bool) TagAnalyzerOption {
+	return func(a *TagAnalyzer) {
+		a.hashtags = include
+	}
+}
+
+// WithStopwords drops the given words from tags, discarding tags made only of stopwords.
+// Without arguments, DefaultStopwords are used.
+func WithStopwords(words ...string) TagAnalyzerOption {
+	return func(a *TagAnalyzer) {
+		if len(words) == 0 {
+			words = DefaultStopwords
+		}
+		a.stopwords = make(map[string]bool, len(words))
+		for _, w := range words {
+			a.stopwords[strings.ToLower(w)] = true
+		}
+	}
+}
+
+// NewTagAnalyzer creates a TagAnalyzer with case folding enabled and no stemming or stopword removal.
+func NewTagAnalyzer(opts ...TagAnalyzerOption) *TagAnalyzer {
+	a := &TagAnalyzer{foldCase: true}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Normalize returns the key a tag is counted under, or "" when nothing is left of the tag.
+func (a *TagAnalyzer) Normalize(tag string) string {
+	words := strings.Fields(tag)
+	kept := words[:0]
+	for _, w := range words {
+		if a.foldCase {
+			w = strings.ToLower(w)
+		}
+		if a.stopwords[strings.ToLower(w)] {
+			continue
+		}
+		if a.stem {
+			w = stemWord(w)
+		}
+		kept = append(kept, w)
+	}
+	return strings.Join(kept, " ")
+}
+
+// stemWord strips a common English suffix from w. It is a deliberately light stemmer that
+// only merges plural and verb forms, leaving short words alone.
+func stemWord(w string) string {
+	suffixes := []struct{ suffix, replace string }{
+		{"ies", "y"}, {"sses", "ss"}, {"ing", ""}, {"ed", ""}, {"es", ""}, {"s", ""},
+	}
+	for _, s := range suffixes {
+		if strings.HasSuffix(w, s.suffix) && len(w)-len(s.suffix) >= 3 {
+			if s.suffix == "s" && strings.HasSuffix(w, "ss") {
+				return w
+			}
+			return w[:len(w)-len(s.suffix)] + s.replace
+		}
+	}
+	return w
+}
+
+// TagStat holds the statistics of a single normalized tag.
+type TagStat struct {
+	// Tag is the most common original spelling of the tag.
+	Tag string
+	// Key is the normalized form the tag was counted under.
+	Key string
+	// Count is the number of videos using the tag.
+	Count int
+	// Views is the total view count of the videos using the tag.
+	Views int64
+	// Score weighs each video using the tag by the logarithm of its views, so a single viral
+	// video doesn't drown out tags used consistently across popular videos.
+	Score float64
+}
+
+// TagPair counts the videos two tags appear on together.
+type TagPair struct {
+	A, B  string
+	Count int
+}
+
+// TagReport is the result of TagAnalyzer.Analyze.
+type TagReport struct {
+	stats    map[string]*TagStat
+	pairs    map[[2]string]int
+	videos   [][]string
+	analyzer *TagAnalyzer
+}
+
+// Analyze computes the tag statistics of the videos in results.
+func (a *TagAnalyzer) Analyze(results *VideoResults) *TagReport {
+	report := &TagReport{
+		stats:    make(map[string]*TagStat),
+		pairs:    make(map[[2]string]int),
+		analyzer: a,
+	}
+	spellings := make(map[string]map[string]int)
+	if results == nil {
+		return report
+	}
+
+	for _, v := range results.Items {
+		if v.Snippet == nil {
+			continue
+		}
+		var views int64
+		if v.Statistics != nil {
+			views = int64(v.Statistics.ViewCount)
+		}
+
+		tags := v.Snippet.Tags
+		if a.hashtags {
+			tags = append(append([]string(nil), tags...), v.Hashtags()...)
+		}
+
+		seen := make(map[string]bool)
+		var keys []string
+		for _, tag := range tags {
+			key := a.Normalize(tag)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+
+			stat, ok := report.stats[key]
+			if !ok {
+				stat = &TagStat{Key: key}
+				report.stats[key] = stat
+				spellings[key] = make(map[string]int)
+			}
+			stat.Count++
+			stat.Views += views
+			stat.Score += math.Log1p(float64(views))
+			spellings[key][strings.TrimSpace(tag)]++
+		}
+
+		sort.Strings(keys)
+		for i := range keys {
+			for j := i + 1; j < len(keys); j++ {
+				report.pairs[[2]string{keys[i], keys[j]}]++
+			}
+		}
+		report.videos = append(report.videos, keys)
+	}
+
+	for key, stat := range report.stats {
+		stat.Tag = mostCommon(spellings[key])
+	}
+	return report
+}
+
+// mostCommon returns the most frequent string of counts, breaking ties alphabetically.
+func mostCommon(counts map[string]int) string {
+	best, bestCount := "", 0
+	for s, n := range counts {
+		if n > bestCount || (n == bestCount && s < best) {
+			best, bestCount = s, n
+		}
+	}
+	return best
+}
+
+// Len returns the number of distinct normalized tags.
+func (r *TagReport) Len() int {
+	return len(r.stats)
+}
+
+// Stat returns the statistics of tag, or nil when no video uses it.
+func (r *TagReport) Stat(tag string) *TagStat {
+	return r.stats[r.analyzer.Normalize(tag)]
+}
+
+// Frequencies maps every normalized tag to the number of videos using it.
+func (r *TagReport) Frequencies() map[string]int {
+	freq := make(map[string]int, len(r.stats))
+	for key, stat := range r.stats {
+		freq[key] = stat.Count
+	}
+	return freq
+}
+
+// TopByCount returns the n most used tags. An n of zero or less returns every tag.
+func (r *TagReport) TopByCount(n int) []TagStat {
+	return r.top(n, func(a, b *TagStat) bool { return a.Count > b.Count })
+}
+
+// TopByViews returns the n tags with the highest total views.
+func (r *TagReport) TopByViews(n int) []TagStat {
+	return r.top(n, func(a, b *TagStat) bool { return a.Views > b.Views })
+}
+
+// TopByScore returns the n tags with the highest view-weighted score.
+func (r *TagReport) TopByScore(n int) []TagStat {
+	return r.top(n, func(a, b *TagStat) bool { return a.Score > b.Score })
+}
+
+// top sorts the tags with less, falling back to the key so the order is deterministic.
+func (r *TagReport) top(n int, less func(a, b *TagStat) bool) []TagStat {
+	stats := make([]*TagStat, 0, len(r.stats))
+	for _, stat := range r.stats {
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if less(stats[i], stats[j]) {
+			return true
+		}
+		if less(stats[j], stats[i]) {
+			return false
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	if n > 0 && len(stats) > n {
+		stats = stats[:n]
+	}
+	ranked := make([]TagStat, len(stats))
+	for i, stat := range stats {
+		ranked[i] = *stat
+	}
+	return ranked
+}
+
+// CoOccurrences returns the n tag pairs found together on the most videos.
+// Pairs hold normalized tags. An n of zero or less returns every pair.
+func (r *TagReport) CoOccurrences(n int) []TagPair {
+	pairs := make([]TagPair, 0, len(r.pairs))
+	for p, count := range r.pairs {
+		pairs = append(pairs, TagPair{A: p[0], B: p[1], Count: count})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Count != pairs[j].Count {
+			return pairs[i].Count > pairs[j].Count
+		}
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+	if n > 0 && len(pairs) > n {
+		pairs = pairs[:n]
+	}
+	return pairs
+}
+
+// Suggest returns up to n tags to use alongside query. Tags that share a word with the query,
+// or appear on videos that do, are ranked by view-weighted score; the query itself is excluded.
+func (r *TagReport) Suggest(query string, n int) []TagStat {
+	queryKey := r.analyzer.Normalize(query)
+	queryWords := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(queryKey, func(c rune) bool { return !unicode.IsLetter(c) && !unicode.IsNumber(c) }) {
+		queryWords[w] = true
+	}
+	matches := func(key string) bool {
+		for _, w := range strings.Fields(key) {
+			if queryWords[w] {
+				return true
+			}
+		}
+		return false
+	}
+
+	related := make(map[string]bool)
+	for _, keys := range r.videos {
+		relevant := false
+		for _, key := range keys {
+			if matches(key) {
+				relevant = true
+				break
+			}
+		}
+		if relevant {
+			for _, key := range keys {
+				related[key] = true
+			}
+		}
+	}
+	delete(related, queryKey)
+
+	var suggestions []TagStat
+	for _, stat := range r.TopByScore(0) {
+		if related[stat.Key] {
+			suggestions = append(suggestions, stat)
+			if n > 0 && len(suggestions) == n {
+				break
+			}
+		}
+	}
+	return suggestions
+}
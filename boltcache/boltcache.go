@@ -0,0 +1,243 @@
+// Package boltcache provides an embedded, durable cache backend built on bbolt, for
+// single-binary deployments that want persistent caching without running Redis.
+//
+//	store, err := boltcache.Open("alaitube.db", boltcache.WithTTL(12*time.Hour))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer store.Close()
+//	client := alaitube.NewClient(alaitube.WithApiKey(key), alaitube.WithCache(store.Cache()))
+package boltcache
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/josephalai/alaitube"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultTTL is how long entries live when no TTL is configured for a region.
+const DefaultTTL = 24 * time.Hour
+
+// Store is an alaitube.CacheV2 backed by a bbolt database. Every region is a bucket, and
+// every value is prefixed with its expiration time so expired entries can be skipped on read
+// and removed by Expire.
+type Store struct {
+	db   *bolt.DB
+	ttl  time.Duration
+	ttls map[alaitube.CacheRegion]time.Duration
+	now  func() time.Time
+}
+
+// Option configures a Store opened with Open.
+type Option func(*Store)
+
+// WithTTL sets the entry lifetime for every region without a region TTL.
+// A zero TTL keeps entries until they are deleted or purged.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Store) {
+		s.ttl = ttl
+	}
+}
+
+// WithRegionTTL sets the entry lifetime for a single region.
+func WithRegionTTL(region alaitube.CacheRegion, ttl time.Duration) Option {
+	return func(s *Store) {
+		s.ttls[region] = ttl
+	}
+}
+
+// Open opens or creates the bbolt database at path. Only one process can hold it open at a time.
+func Open(path string, opts ...Option) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed opening bolt cache: %w", err)
+	}
+	return New(db, opts...), nil
+}
+
+// New creates a Store using an already open database, e.g. one shared with the application.
+func New(db *bolt.DB, opts ...Option) *Store {
+	s := &Store{
+		db:   db,
+		ttl:  DefaultTTL,
+		ttls: make(map[alaitube.CacheRegion]time.Duration),
+		now:  time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Cache returns an alaitube.Cache backed by the store, to pass to alaitube.WithCache.
+func (s *Store) Cache(opts ...alaitube.CacheV2AdapterOption) *alaitube.CacheV2Adapter {
+	opts = append([]alaitube.CacheV2AdapterOption{alaitube.WithAdapterName("bolt-cache")}, opts...)
+	return alaitube.NewCacheV2Adapter(s, opts...)
+}
+
+// regionTTL returns the TTL of values written to region without an explicit TTL.
+func (s *Store) regionTTL(region alaitube.CacheRegion) time.Duration {
+	if ttl, ok := s.ttls[region]; ok {
+		return ttl
+	}
+	return s.ttl
+}
+
+// expired reports whether a stored value has expired. Values start with the expiration
+// time in Unix nanoseconds, zero meaning no expiration.
+func (s *Store) expired(v []byte) bool {
+	expiresAt := int64(binary.BigEndian.Uint64(v[:8]))
+	return expiresAt != 0 && s.now().UnixNano() > expiresAt
+}
+
+// Get returns the value of key in region.
+func (s *Store) Get(ctx context.Context, region alaitube.CacheRegion, key string) ([]byte, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(region))
+		if b == nil {
+			return nil
+		}
+		v := b.Get([]byte(key))
+		if len(v) < 8 || s.expired(v) {
+			return nil
+		}
+		// v is only valid for the life of the transaction.
+		value = append([]byte(nil), v[8:]...)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return value, value != nil, nil
+}
+
+// Set stores value under key in region. A zero ttl uses the region's TTL.
+func (s *Store) Set(ctx context.Context, region alaitube.CacheRegion, key string, value []byte, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ttl == 0 {
+		ttl = s.regionTTL(region)
+	}
+	v := make([]byte, 8+len(value))
+	if ttl > 0 {
+		binary.BigEndian.PutUint64(v[:8], uint64(s.now().Add(ttl).UnixNano()))
+	}
+	copy(v[8:], value)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(region))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), v)
+	})
+}
+
+// Delete removes the entry for key in region.
+func (s *Store) Delete(ctx context.Context, region alaitube.CacheRegion, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(region))
+		if b == nil {
+			return nil
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+// PurgeRegion removes every entry of region.
+func (s *Store) PurgeRegion(ctx context.Context, region alaitube.CacheRegion) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket([]byte(region)); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		return nil
+	})
+}
+
+// PurgeAll removes every region.
+func (s *Store) PurgeAll(ctx context.Context) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		var names [][]byte
+		err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			names = append(names, append([]byte(nil), name...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Expire removes every expired entry and returns how many were removed. Expired entries are
+// never returned by Get, so calling Expire is only needed to reclaim space.
+func (s *Store) Expire(ctx context.Context) (int, error) {
+	removed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(_ []byte, b *bolt.Bucket) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var expired [][]byte
+			err := b.ForEach(func(k, v []byte) error {
+				if len(v) < 8 || s.expired(v) {
+					expired = append(expired, append([]byte(nil), k...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			// Keys are deleted after iterating, since deleting under a cursor skips items.
+			for _, k := range expired {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+				removed++
+			}
+			return nil
+		})
+	})
+	return removed, err
+}
+
+// RegionSize returns the number of entries of region and the bytes of their keys and values.
+func (s *Store) RegionSize(ctx context.Context, region alaitube.CacheRegion) (int64, int64, error) {
+	var entries, bytes int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(region))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			entries++
+			bytes += int64(len(k) + len(v))
+			return ctx.Err()
+		})
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return entries, bytes, nil
+}
+
+var _ alaitube.CacheV2 = (*Store)(nil)
+var _ alaitube.CacheV2Sizer = (*Store)(nil)
@@ -5,6 +5,22 @@ import (
 	"time"
 )
 
+// CacheRegion identifies one of the groups of entries held by a Cache.
+type CacheRegion string
+
+const (
+	RegionVideos        CacheRegion = "videos"
+	RegionChannels      CacheRegion = "channels"
+	RegionPlaylists     CacheRegion = "playlists"
+	RegionVideoDetails  CacheRegion = "video-details"
+	RegionComments      CacheRegion = "comments"
+	RegionCategories    CacheRegion = "categories"
+	RegionSubscriptions CacheRegion = "subscriptions"
+)
+
+// cacheRegions lists every region in the order backends iterate them.
+var cacheRegions = []CacheRegion{RegionVideos, RegionChannels, RegionPlaylists, RegionVideoDetails, RegionComments, RegionCategories, RegionSubscriptions}
+
 type Cache interface {
 	// Get, Set for videoCache
 	GetVideo(key string) *VideoResults
@@ -18,6 +34,23 @@ type Cache interface {
 	// Get, Set for videoDetailsCache
 	GetVideoDetail(key string) *VideoResults
 	SetVideoDetail(key string, detail *VideoResults)
+	// Get, Set for commentsCache
+	GetCommentThreads(key string) *CommentThreadResults
+	SetCommentThreads(key string, threads *CommentThreadResults)
+	// Get, Set for categoriesCache
+	GetCategories(key string) *VideoCategoryResults
+	SetCategories(key string, categories *VideoCategoryResults)
+	// Get, Set for subscriptionsCache
+	GetSubscriptions(key string) *SubscriptionResults
+	SetSubscriptions(key string, subscriptions *SubscriptionResults)
+	// Delete removes the entry for key in region, including a negative entry.
+	Delete(region CacheRegion, key string)
+	// PurgeRegion removes every entry of region.
+	PurgeRegion(region CacheRegion)
+	// PurgeAll removes every entry of every region.
+	PurgeAll()
+	// Stats returns the lookups and the size of every region.
+	Stats() CacheStats
 	GetServiceName() string
 }
 
@@ -25,4 +58,6 @@ type Redis interface {
 	Ping() *redis.StatusCmd
 	Get(string) *redis.StringCmd
 	Set(string, interface{}, time.Duration) *redis.StatusCmd
+	Del(...string) *redis.IntCmd
+	Scan(cursor uint64, match string, count int64) *redis.ScanCmd
 }
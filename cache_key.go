@@ -0,0 +1,40 @@
+package alaitube
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+)
+
+// CacheKey returns the key the results of a request are cached under: the name of the request,
+// such as "search" or "videos", followed by the SHA-256 of its parameters encoded with their
+// names sorted. Requests differing in any parameter, such as a page token or a filter, never
+// share an entry, and keys have the same short length whatever the number of IDs or the length
+// of the query, which suits Redis and memcached. Every cached request of the client is keyed
+// with it, so an entry can be deleted by building its key again:
+//
+//	yt.Cache.Delete(alaitube.RegionChannels, alaitube.CacheKey("channels", url.Values{"id": {channelId}}))
+func CacheKey(endpoint string, params url.Values) string {
+	sum := sha256.Sum256([]byte(endpoint + "?" + params.Encode()))
+	return endpoint + ":" + hex.EncodeToString(sum[:])
+}
+
+// channelCacheKey returns the cache key of a channel, localized in language when it is set.
+// It is also the key of the channel's negative entry, recorded without a language.
+func channelCacheKey(channelId, language string) string {
+	v := url.Values{"id": {channelId}}
+	if language != "" {
+		v.Set("hl", language)
+	}
+	return CacheKey("channels", v)
+}
+
+// playlistCacheKey returns the cache key of the first n videos of a playlist from pageToken.
+func playlistCacheKey(playlistId, pageToken string, n int) string {
+	v := url.Values{"playlistId": {playlistId}, "maxResults": {strconv.Itoa(n)}}
+	if pageToken != "" {
+		v.Set("pageToken", pageToken)
+	}
+	return CacheKey("playlistItems", v)
+}
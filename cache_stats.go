@@ -0,0 +1,151 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// RegionStats reports the lookups and the size of one region of a Cache. Hits and Misses count
+// the lookups made through the cache value since it was created, in this process only; lookups
+// of not-found records aren't counted. Entries and Bytes are -1 when the backend can't tell.
+type RegionStats struct {
+	Hits   int64 `bson:"hits" json:"hits"`
+	Misses int64 `bson:"misses" json:"misses"`
+	// Entries is the number of entries held, including not-found records and expired entries
+	// that weren't removed yet.
+	Entries int64 `bson:"entries" json:"entries"`
+	// Bytes estimates the memory or storage held by the entries, from the size of their JSON
+	// encoding.
+	Bytes int64 `bson:"bytes" json:"bytes"`
+}
+
+// HitRate returns the fraction of the region's lookups that were hits.
+func (s RegionStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// CacheStats reports the lookups and the size of every region of a Cache, to size its TTLs and
+// entry caps from real traffic.
+type CacheStats struct {
+	Name    string                      `bson:"name" json:"name"`
+	Regions map[CacheRegion]RegionStats `bson:"regions" json:"regions"`
+}
+
+// Total sums the statistics of every region. Entries and Bytes are -1 when any region doesn't
+// report them.
+func (s CacheStats) Total() RegionStats {
+	var total RegionStats
+	for _, r := range s.Regions {
+		total.Hits += r.Hits
+		total.Misses += r.Misses
+		total.Entries = addKnown(total.Entries, r.Entries)
+		total.Bytes = addKnown(total.Bytes, r.Bytes)
+	}
+	return total
+}
+
+// addKnown adds two sizes, either of which is -1 when unknown.
+func addKnown(a, b int64) int64 {
+	if a < 0 || b < 0 {
+		return -1
+	}
+	return a + b
+}
+
+// hitCounters counts the hits and misses of every region of a cache.
+type hitCounters map[CacheRegion]*hitCounter
+
+type hitCounter struct {
+	hits, misses atomic.Int64
+}
+
+func newHitCounters() hitCounters {
+	c := make(hitCounters, len(cacheRegions))
+	for _, region := range cacheRegions {
+		c[region] = &hitCounter{}
+	}
+	return c
+}
+
+// record counts a lookup in region.
+func (c hitCounters) record(region CacheRegion, hit bool) {
+	counter, ok := c[region]
+	if !ok {
+		return
+	}
+	if hit {
+		counter.hits.Add(1)
+	} else {
+		counter.misses.Add(1)
+	}
+}
+
+// stats returns the counts of every region, with Entries and Bytes unknown.
+func (c hitCounters) stats(name string) CacheStats {
+	stats := CacheStats{Name: name, Regions: make(map[CacheRegion]RegionStats, len(c))}
+	for region, counter := range c {
+		stats.Regions[region] = RegionStats{Hits: counter.hits.Load(), Misses: counter.misses.Load(), Entries: -1, Bytes: -1}
+	}
+	return stats
+}
+
+// jsonSize returns the size of the JSON encoding of v, or 0 when it can't be encoded.
+func jsonSize(v interface{}) int64 {
+	var w countingWriter
+	if err := json.NewEncoder(&w).Encode(v); err != nil {
+		return 0
+	}
+	return int64(w)
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
+// DefaultCacheStatsInterval is how often LogCacheStats logs when given no positive interval.
+const DefaultCacheStatsInterval = 5 * time.Minute
+
+// LogCacheStats logs the statistics of every region of the client's cache at Info level every
+// interval, until ctx is done. An interval of zero or less logs every DefaultCacheStatsInterval.
+// Run it in its own goroutine:
+//
+//	go yt.LogCacheStats(ctx, 5*time.Minute)
+func (yt *YoutubeApi) LogCacheStats(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCacheStatsInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		stats := yt.Cache.Stats()
+		for _, region := range cacheRegions {
+			r, ok := stats.Regions[region]
+			if !ok {
+				continue
+			}
+			yt.logger.Info("cache stats",
+				Field{"cache", stats.Name},
+				Field{"region", region},
+				Field{"hits", r.Hits},
+				Field{"misses", r.Misses},
+				Field{"hitRate", r.HitRate()},
+				Field{"entries", r.Entries},
+				Field{"bytes", r.Bytes},
+			)
+		}
+	}
+}
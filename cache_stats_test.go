@@ -0,0 +1,49 @@
+package alaitube_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/josephalai/alaitube"
+)
+
+func TestMemoryCacheStats(t *testing.T) {
+	c := alaitube.NewMemoryCache()
+	videos := &alaitube.VideoResults{Items: []*alaitube.Video{{Id: "a"}, {Id: "b"}}}
+
+	c.SetVideo("k1", videos)
+	c.SetVideo("k2", &alaitube.VideoResults{})
+	c.GetVideo("k1")
+	c.GetVideo("missing")
+
+	stats := c.Stats().Regions[alaitube.RegionVideos]
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 2 {
+		t.Errorf("stats = %+v, want 1 hit, 1 miss, 2 entries", stats)
+	}
+	if stats.Bytes <= 0 {
+		t.Fatalf("Bytes = %d, want the size of the entries", stats.Bytes)
+	}
+
+	// A smaller value set again replaces the size of the entry.
+	c.SetVideo("k1", &alaitube.VideoResults{})
+	replaced := c.Stats().Regions[alaitube.RegionVideos]
+	if replaced.Bytes <= 0 || replaced.Bytes >= stats.Bytes {
+		t.Errorf("Bytes after replacing = %d, want less than %d", replaced.Bytes, stats.Bytes)
+	}
+
+	c.Delete(alaitube.RegionVideos, "k1")
+	c.Delete(alaitube.RegionVideos, "k2")
+	if empty := c.Stats().Regions[alaitube.RegionVideos]; empty.Entries != 0 || empty.Bytes != 0 {
+		t.Errorf("stats after deleting = %+v, want no entries and no bytes", empty)
+	}
+}
+
+func TestLogCacheStatsNonPositiveInterval(t *testing.T) {
+	yt := alaitube.NewClient(alaitube.WithApiKey("test"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, interval := range []time.Duration{0, -time.Second} {
+		yt.LogCacheStats(ctx, interval)
+	}
+}
@@ -0,0 +1,412 @@
+package alaitube
+
+import (
+	"context"
+	"github.com/go-redis/redis"
+	"time"
+)
+
+// DefaultCacheTimeout bounds each backend call made by a CacheV2Adapter.
+const DefaultCacheTimeout = 2 * time.Second
+
+// CacheV2 is a context-aware cache backend that reports failures instead of silently missing.
+// Values are opaque bytes, so networked backends only need to store blobs; typed access is
+// provided on top by CacheV2Adapter.
+type CacheV2 interface {
+	// Get returns the value of key in region and whether it was found. A backend failure is
+	// reported as an error, never as a miss.
+	Get(ctx context.Context, region CacheRegion, key string) ([]byte, bool, error)
+	// Set stores value under key in region. A zero ttl uses the backend's TTL for the region.
+	Set(ctx context.Context, region CacheRegion, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, region CacheRegion, key string) error
+	PurgeRegion(ctx context.Context, region CacheRegion) error
+	PurgeAll(ctx context.Context) error
+}
+
+// CacheV2Sizer is implemented by CacheV2 backends that can measure their regions, so the Stats
+// of a CacheV2Adapter report their size. MemoryStore, FileStore, and RedisStore implement it.
+type CacheV2Sizer interface {
+	// RegionSize returns the number of entries of region and the bytes they hold, or -1 bytes
+	// when the backend can't tell.
+	RegionSize(ctx context.Context, region CacheRegion) (entries, bytes int64, err error)
+}
+
+// CacheV2Adapter exposes a CacheV2 backend through the Cache interface used by the client.
+// Values are stored as JSON. Since Cache methods can't return errors, backend failures are
+// reported to the adapter's Logger and treated as misses.
+type CacheV2Adapter struct {
+	backend     CacheV2
+	name        string
+	timeout     time.Duration
+	negativeTTL time.Duration
+	logger      Logger
+	hits        hitCounters
+
+	videos        *Region[VideoResults]
+	channels      *Region[ChannelInfo]
+	playlists     *Region[VideoResults]
+	videoDetails  *Region[VideoResults]
+	comments      *Region[CommentThreadResults]
+	categories    *Region[VideoCategoryResults]
+	subscriptions *Region[SubscriptionResults]
+}
+
+// CacheV2AdapterOption configures a CacheV2Adapter created with NewCacheV2Adapter.
+type CacheV2AdapterOption func(*CacheV2Adapter)
+
+// WithAdapterTimeout bounds each backend call.
+func WithAdapterTimeout(timeout time.Duration) CacheV2AdapterOption {
+	return func(a *CacheV2Adapter) {
+		a.timeout = timeout
+	}
+}
+
+// WithAdapterNegativeTTL sets how long not-found results are remembered.
+func WithAdapterNegativeTTL(ttl time.Duration) CacheV2AdapterOption {
+	return func(a *CacheV2Adapter) {
+		a.negativeTTL = ttl
+	}
+}
+
+// WithAdapterLogger sets the Logger backend failures are reported to.
+// A nil logger is ignored and the default slog adapter is kept.
+func WithAdapterLogger(logger Logger) CacheV2AdapterOption {
+	return func(a *CacheV2Adapter) {
+		if logger != nil {
+			a.logger = logger
+		}
+	}
+}
+
+// WithAdapterName sets the name returned by GetServiceName.
+func WithAdapterName(name string) CacheV2AdapterOption {
+	return func(a *CacheV2Adapter) {
+		a.name = name
+	}
+}
+
+// NewCacheV2Adapter wraps backend so it can be passed to WithCache.
+func NewCacheV2Adapter(backend CacheV2, opts ...CacheV2AdapterOption) *CacheV2Adapter {
+	a := &CacheV2Adapter{
+		backend:     backend,
+		name:        "cache-v2",
+		timeout:     DefaultCacheTimeout,
+		negativeTTL: DefaultNegativeTTL,
+		logger:      NewSlogLogger(nil),
+		hits:        newHitCounters(),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.videos = NewRegion[VideoResults](backend, RegionVideos)
+	a.channels = NewRegion[ChannelInfo](backend, RegionChannels)
+	a.playlists = NewRegion[VideoResults](backend, RegionPlaylists)
+	a.videoDetails = NewRegion[VideoResults](backend, RegionVideoDetails)
+	a.comments = NewRegion[CommentThreadResults](backend, RegionComments)
+	a.categories = NewRegion[VideoCategoryResults](backend, RegionCategories)
+	a.subscriptions = NewRegion[SubscriptionResults](backend, RegionSubscriptions)
+	return a
+}
+
+// context returns the context of a single backend call.
+func (a *CacheV2Adapter) context() (context.Context, context.CancelFunc) {
+	if a.timeout <= 0 {
+		return context.Background(), func() {}
+	}
+	return context.WithTimeout(context.Background(), a.timeout)
+}
+
+// getValue loads the entry for key from region, reporting failures and misses as nil.
+func getValue[T any](a *CacheV2Adapter, region *Region[T], key string) *T {
+	ctx, cancel := a.context()
+	defer cancel()
+	v, ok, err := region.Get(ctx, key)
+	a.hits.record(region.Name(), err == nil && ok)
+	if err != nil {
+		a.logger.Warn("cache get failed", Field{"region", region.Name()}, Field{"error", err})
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+// setValue stores v under key in region. Nil values are not stored.
+func setValue[T any](a *CacheV2Adapter, region *Region[T], key string, v *T) {
+	if v == nil {
+		return
+	}
+	ctx, cancel := a.context()
+	defer cancel()
+	if err := region.Set(ctx, key, *v); err != nil {
+		a.logger.Warn("cache set failed", Field{"region", region.Name()}, Field{"error", err})
+	}
+}
+
+// v2NotFoundKey is the key of the negative record of key, stored in the same region.
+func v2NotFoundKey(key string) string {
+	return "!notfound:" + key
+}
+
+// SetNotFound records key as missing in region for the negative TTL.
+func (a *CacheV2Adapter) SetNotFound(region CacheRegion, key string) {
+	if a.negativeTTL <= 0 {
+		return
+	}
+	ctx, cancel := a.context()
+	defer cancel()
+	if err := a.backend.Set(ctx, region, v2NotFoundKey(key), []byte("1"), a.negativeTTL); err != nil {
+		a.logger.Warn("cache set failed", Field{"region", region}, Field{"error", err})
+	}
+}
+
+// IsNotFound reports whether key is recorded as missing in region.
+func (a *CacheV2Adapter) IsNotFound(region CacheRegion, key string) bool {
+	ctx, cancel := a.context()
+	defer cancel()
+	_, ok, err := a.backend.Get(ctx, region, v2NotFoundKey(key))
+	if err != nil {
+		a.logger.Warn("cache get failed", Field{"region", region}, Field{"error", err})
+	}
+	return ok
+}
+
+// Delete removes the entry for key in region, together with its not-found record.
+func (a *CacheV2Adapter) Delete(region CacheRegion, key string) {
+	ctx, cancel := a.context()
+	defer cancel()
+	for _, k := range []string{key, v2NotFoundKey(key)} {
+		if err := a.backend.Delete(ctx, region, k); err != nil {
+			a.logger.Warn("cache delete failed", Field{"region", region}, Field{"error", err})
+		}
+	}
+}
+
+// PurgeRegion removes every entry of region.
+func (a *CacheV2Adapter) PurgeRegion(region CacheRegion) {
+	ctx, cancel := a.context()
+	defer cancel()
+	if err := a.backend.PurgeRegion(ctx, region); err != nil {
+		a.logger.Warn("cache purge failed", Field{"region", region}, Field{"error", err})
+	}
+}
+
+// PurgeAll removes every entry of every region.
+func (a *CacheV2Adapter) PurgeAll() {
+	ctx, cancel := a.context()
+	defer cancel()
+	if err := a.backend.PurgeAll(ctx); err != nil {
+		a.logger.Warn("cache purge failed", Field{"error", err})
+	}
+}
+
+// GetVideo retrieves a video from Cache.
+func (a *CacheV2Adapter) GetVideo(key string) *VideoResults {
+	return getValue(a, a.videos, key)
+}
+
+// SetVideo stores a video to Cache.
+func (a *CacheV2Adapter) SetVideo(key string, video *VideoResults) {
+	setValue(a, a.videos, key, video)
+}
+
+// GetChannel retrieves a channel from Cache.
+func (a *CacheV2Adapter) GetChannel(key string) *ChannelInfo {
+	return getValue(a, a.channels, key)
+}
+
+// SetChannel stores a channel to Cache.
+func (a *CacheV2Adapter) SetChannel(key string, channel *ChannelInfo) {
+	setValue(a, a.channels, key, channel)
+}
+
+// GetPlaylist retrieves a playlist from Cache.
+func (a *CacheV2Adapter) GetPlaylist(key string) *VideoResults {
+	return getValue(a, a.playlists, key)
+}
+
+// SetPlaylist stores a playlist to Cache.
+func (a *CacheV2Adapter) SetPlaylist(key string, playlist *VideoResults) {
+	setValue(a, a.playlists, key, playlist)
+}
+
+// GetVideoDetail retrieves a VideoDetail from Cache.
+func (a *CacheV2Adapter) GetVideoDetail(key string) *VideoResults {
+	return getValue(a, a.videoDetails, key)
+}
+
+// SetVideoDetail stores a VideoDetail to Cache.
+func (a *CacheV2Adapter) SetVideoDetail(key string, video *VideoResults) {
+	setValue(a, a.videoDetails, key, video)
+}
+
+// GetCommentThreads retrieves comment threads from Cache.
+func (a *CacheV2Adapter) GetCommentThreads(key string) *CommentThreadResults {
+	return getValue(a, a.comments, key)
+}
+
+// SetCommentThreads stores comment threads to Cache.
+func (a *CacheV2Adapter) SetCommentThreads(key string, threads *CommentThreadResults) {
+	setValue(a, a.comments, key, threads)
+}
+
+// GetCategories retrieves video categories from Cache.
+func (a *CacheV2Adapter) GetCategories(key string) *VideoCategoryResults {
+	return getValue(a, a.categories, key)
+}
+
+// SetCategories stores video categories to Cache.
+func (a *CacheV2Adapter) SetCategories(key string, categories *VideoCategoryResults) {
+	setValue(a, a.categories, key, categories)
+}
+
+// GetSubscriptions retrieves subscriptions from Cache.
+func (a *CacheV2Adapter) GetSubscriptions(key string) *SubscriptionResults {
+	return getValue(a, a.subscriptions, key)
+}
+
+// SetSubscriptions stores subscriptions to Cache.
+func (a *CacheV2Adapter) SetSubscriptions(key string, subscriptions *SubscriptionResults) {
+	setValue(a, a.subscriptions, key, subscriptions)
+}
+
+func (a *CacheV2Adapter) GetServiceName() string {
+	return a.name
+}
+
+// Stats returns the lookups made through a and, when the backend implements CacheV2Sizer, the
+// size of every region. Entries and Bytes include the not-found records.
+func (a *CacheV2Adapter) Stats() CacheStats {
+	stats := a.hits.stats(a.GetServiceName())
+	sizer, ok := a.backend.(CacheV2Sizer)
+	if !ok {
+		return stats
+	}
+	for region, r := range stats.Regions {
+		ctx, cancel := a.context()
+		entries, bytes, err := sizer.RegionSize(ctx, region)
+		cancel()
+		if err != nil {
+			a.logger.Warn("cache size failed", Field{"region", region}, Field{"error", err})
+			continue
+		}
+		r.Entries, r.Bytes = entries, bytes
+		stats.Regions[region] = r
+	}
+	return stats
+}
+
+// RedisStore is a CacheV2 backed by Redis. Unlike RedisCache, it reports Redis failures to the caller.
+// Keys have the same layout as RedisCache: "<prefix><region>:<key>".
+type RedisStore struct {
+	client Redis
+	prefix string
+	ttls   map[CacheRegion]time.Duration
+}
+
+// NewRedisStore creates a RedisStore. It accepts the same WithKeyPrefix, WithRedisTTL, and
+// WithRedisRegionTTL options as NewRedisCache; other options are ignored.
+func NewRedisStore(client Redis, opts ...RedisCacheOption) *RedisStore {
+	c := NewRedisCache(client, opts...)
+	return &RedisStore{client: client, prefix: c.prefix, ttls: c.ttls}
+}
+
+func (s *RedisStore) key(region CacheRegion, key string) string {
+	return s.prefix + string(region) + ":" + key
+}
+
+// Get returns the value of key in region. The context is not used by the Redis v6 client and
+// is only checked before the call.
+func (s *RedisStore) Get(ctx context.Context, region CacheRegion, key string) ([]byte, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	data, err := s.client.Get(s.key(region, key)).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Set stores value under key in region.
+func (s *RedisStore) Set(ctx context.Context, region CacheRegion, key string, value []byte, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ttl == 0 {
+		ttl = s.ttls[region]
+	}
+	return s.client.Set(s.key(region, key), value, ttl).Err()
+}
+
+// Delete removes the entry for key in region.
+func (s *RedisStore) Delete(ctx context.Context, region CacheRegion, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.client.Del(s.key(region, key)).Err()
+}
+
+// PurgeRegion removes every entry of region.
+func (s *RedisStore) PurgeRegion(ctx context.Context, region CacheRegion) error {
+	return s.deleteMatching(ctx, s.key(region, "*"))
+}
+
+// PurgeAll removes every entry written with the store's key prefix, or the entries of every
+// region when the prefix is empty, rather than the whole database.
+func (s *RedisStore) PurgeAll(ctx context.Context) error {
+	if s.prefix == "" {
+		for _, region := range cacheRegions {
+			if err := s.PurgeRegion(ctx, region); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return s.deleteMatching(ctx, s.prefix+"*")
+}
+
+// RegionSize counts the entries of region with SCAN, which takes a while on large databases.
+// Bytes is unknown.
+func (s *RedisStore) RegionSize(ctx context.Context, region CacheRegion) (int64, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+	entries, err := countMatching(s.client, s.key(region, "*"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return entries, -1, nil
+}
+
+// deleteMatching deletes every key matching the SCAN pattern, stopping when ctx ends.
+func (s *RedisStore) deleteMatching(ctx context.Context, pattern string) error {
+	var cursor uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		keys, next, err := s.client.Scan(cursor, pattern, 500).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := s.client.Del(keys...).Err(); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+var _ Cache = (*CacheV2Adapter)(nil)
+var _ NegativeCache = (*CacheV2Adapter)(nil)
+var _ CacheV2 = (*RedisStore)(nil)
+var _ CacheV2Sizer = (*RedisStore)(nil)
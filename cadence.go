@@ -0,0 +1,148 @@
+package alaitube
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// DefaultCadenceVideos is the number of recent uploads GetUploadCadence analyzes by default.
+const DefaultCadenceVideos = 100
+
+// breakGapFactor is how many times longer than the median gap a gap must be to count as a break.
+const breakGapFactor = 2
+
+// UploadGap is the time between two consecutive uploads.
+type UploadGap struct {
+	// After is the publish time of the upload the gap follows, and Before that of the next one.
+	After    time.Time     `bson:"after" json:"after"`
+	Before   time.Time     `bson:"before" json:"before"`
+	Duration time.Duration `bson:"duration" json:"duration"`
+}
+
+// UploadCadence describes when and how often a channel publishes, as computed by AnalyzeCadence.
+// Days and hours are those of the location passed to AnalyzeCadence.
+type UploadCadence struct {
+	ChannelId string    `bson:"channelId,omitempty" json:"channelId,omitempty"`
+	Uploads   int       `bson:"uploads" json:"uploads"`
+	First     time.Time `bson:"first" json:"first"`
+	Last      time.Time `bson:"last" json:"last"`
+	// PerWeek and PerMonth are the average number of uploads over the analyzed period.
+	PerWeek  float64 `bson:"perWeek" json:"perWeek"`
+	PerMonth float64 `bson:"perMonth" json:"perMonth"`
+	// ByWeekday and ByHour count the uploads published on each day of the week, indexed by
+	// time.Weekday, and at each hour of the day.
+	ByWeekday   [7]int        `bson:"byWeekday" json:"byWeekday"`
+	ByHour      [24]int       `bson:"byHour" json:"byHour"`
+	BusiestDay  time.Weekday  `bson:"busiestDay" json:"busiestDay"`
+	BusiestHour int           `bson:"busiestHour" json:"busiestHour"`
+	AverageGap  time.Duration `bson:"averageGap" json:"averageGap"`
+	MedianGap   time.Duration `bson:"medianGap" json:"medianGap"`
+	LongestGap  UploadGap     `bson:"longestGap" json:"longestGap"`
+	// Breaks are the gaps more than twice as long as the median gap, oldest first.
+	Breaks []UploadGap `bson:"breaks,omitempty" json:"breaks,omitempty"`
+	// SinceLastUpload is the time elapsed between the last upload and the analysis.
+	SinceLastUpload time.Duration `bson:"sinceLastUpload" json:"sinceLastUpload"`
+}
+
+// AnalyzeCadence computes the upload cadence of the videos in results, which are usually the
+// uploads of a single channel. Days and hours are counted in loc, or UTC when loc is nil. Videos
+// without a publish time are ignored; rates and gaps need at least two uploads.
+func AnalyzeCadence(results *VideoResults, loc *time.Location) *UploadCadence {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var published []time.Time
+	for _, v := range results.Items {
+		if t := v.PublishedAt(); !t.IsZero() {
+			published = append(published, t.In(loc))
+		}
+	}
+	sort.Slice(published, func(i, j int) bool { return published[i].Before(published[j]) })
+
+	c := &UploadCadence{Uploads: len(published)}
+	if len(published) == 0 {
+		return c
+	}
+	c.First, c.Last = published[0], published[len(published)-1]
+	c.SinceLastUpload = time.Since(c.Last)
+	for _, t := range published {
+		c.ByWeekday[t.Weekday()]++
+		c.ByHour[t.Hour()]++
+	}
+	for day, n := range c.ByWeekday {
+		if n > c.ByWeekday[c.BusiestDay] {
+			c.BusiestDay = time.Weekday(day)
+		}
+	}
+	for hour, n := range c.ByHour {
+		if n > c.ByHour[c.BusiestHour] {
+			c.BusiestHour = hour
+		}
+	}
+	if len(published) < 2 {
+		return c
+	}
+
+	gaps := make([]UploadGap, len(published)-1)
+	for i := range gaps {
+		gaps[i] = UploadGap{After: published[i], Before: published[i+1], Duration: published[i+1].Sub(published[i])}
+		if gaps[i].Duration > c.LongestGap.Duration {
+			c.LongestGap = gaps[i]
+		}
+	}
+	span := c.Last.Sub(c.First)
+	c.AverageGap = span / time.Duration(len(gaps))
+	durations := make([]time.Duration, len(gaps))
+	for i, g := range gaps {
+		durations[i] = g.Duration
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	c.MedianGap = durations[len(durations)/2]
+	if len(durations)%2 == 0 {
+		c.MedianGap = (durations[len(durations)/2-1] + durations[len(durations)/2]) / 2
+	}
+	for _, g := range gaps {
+		if g.Duration > breakGapFactor*c.MedianGap {
+			c.Breaks = append(c.Breaks, g)
+		}
+	}
+	if weeks := span.Hours() / (24 * 7); weeks > 0 {
+		c.PerWeek = float64(len(gaps)) / weeks
+		c.PerMonth = c.PerWeek * 365.25 / 12 / 7
+	}
+	return c
+}
+
+// GetUploadCadence analyzes the upload cadence of a channel over its maxVideos most recent
+// uploads, DefaultCadenceVideos when maxVideos is zero or less. Days and hours are counted in UTC;
+// call AnalyzeCadence on the uploads for another time zone.
+func (yt *YoutubeApi) GetUploadCadence(channelId string, maxVideos int) (*UploadCadence, error) {
+	return yt.GetUploadCadenceContext(context.Background(), channelId, maxVideos)
+}
+
+// GetUploadCadenceContext is like GetUploadCadence but uses ctx for every request.
+func (yt *YoutubeApi) GetUploadCadenceContext(ctx context.Context, channelId string, maxVideos int) (_ *UploadCadence, err error) {
+	ctx, span := yt.startOperation(ctx, "GetUploadCadence")
+	defer func() { endSpan(span, err) }()
+
+	if maxVideos <= 0 {
+		maxVideos = DefaultCadenceVideos
+	}
+	uploads := &VideoResults{}
+	pager := yt.ChannelUploads(channelId)
+	for pager.HasNext() && len(uploads.Items) < maxVideos {
+		page, err := pager.NextContext(ctx)
+		if errors.Is(err, ErrNoMorePages) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		uploads.Items = append(uploads.Items, page.Items...)
+	}
+	cadence := AnalyzeCadence(uploads.Limit(maxVideos), time.UTC)
+	cadence.ChannelId = channelId
+	return cadence, nil
+}
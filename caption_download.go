@@ -0,0 +1,164 @@
+package alaitube
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DownloadCaptionTrack = "https://www.googleapis.com/youtube/v3/captions/%s?tfmt=%s&key=%s"
+
+// Caption formats accepted by DownloadCaption.
+const (
+	CaptionFormatSRT = "srt"
+	CaptionFormatVTT = "vtt"
+)
+
+// Caption is a caption track downloaded in a subtitle format.
+type Caption struct {
+	TrackId string `bson:"trackId,omitempty" json:"trackId,omitempty"`
+	// Format is CaptionFormatSRT or CaptionFormatVTT.
+	Format string `bson:"format,omitempty" json:"format,omitempty"`
+	// Data is the subtitle file, ready to be written to disk.
+	Data     []byte               `bson:"data,omitempty" json:"data,omitempty"`
+	Segments []*TranscriptSegment `bson:"segments,omitempty" json:"segments,omitempty"`
+}
+
+// DownloadCaption downloads a caption track, as returned by ListCaptionTracks, as SRT or WebVTT.
+// The captions.download endpoint is used when the client has OAuth2 credentials, which only
+// works for tracks of the authenticated user's videos. Otherwise, or when YouTube refuses the
+// download, the track is fetched from YouTube's public timed text endpoint, which also serves
+// auto-generated captions, and converted to the requested format.
+func (yt *YoutubeApi) DownloadCaption(track *CaptionTrack, format string) (*Caption, error) {
+	return yt.DownloadCaptionContext(context.Background(), track, format)
+}
+
+// DownloadCaptionContext is like DownloadCaption but uses ctx for the underlying requests.
+func (yt *YoutubeApi) DownloadCaptionContext(ctx context.Context, track *CaptionTrack, format string) (_ *Caption, err error) {
+	ctx, span := yt.startOperation(ctx, "DownloadCaption")
+	defer func() { endSpan(span, err) }()
+
+	if format != CaptionFormatSRT && format != CaptionFormatVTT {
+		return nil, fmt.Errorf("unsupported caption format %q", format)
+	}
+
+	if yt.tokens != nil {
+		body, err := yt.sendRequest(ctx, http.MethodGet, fmt.Sprintf(DownloadCaptionTrack, url.PathEscape(track.Id), format, yt.apiKey), nil, authUser)
+		if err == nil {
+			return &Caption{TrackId: track.Id, Format: format, Data: body, Segments: parseSubtitles(body)}, nil
+		}
+		if !errors.Is(err, ErrForbidden) && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	if track.Snippet == nil {
+		return nil, fmt.Errorf("caption track %s is missing its snippet", track.Id)
+	}
+	extra := ""
+	if track.IsAutoGenerated() {
+		extra += "&kind=asr"
+	}
+	if track.Snippet.Name != "" {
+		extra += "&name=" + url.QueryEscape(track.Snippet.Name)
+	}
+	body, err := yt.httpGetRequest(ctx, fmt.Sprintf(GetTimedText, url.QueryEscape(track.Snippet.VideoId), url.QueryEscape(track.Snippet.Language), extra))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("empty caption track %s: %w", track.Id, ErrNotFound)
+	}
+	segments, err := parseTimedText(body)
+	if err != nil {
+		return nil, err
+	}
+	data := FormatSRT(segments)
+	if format == CaptionFormatVTT {
+		data = FormatVTT(segments)
+	}
+	return &Caption{TrackId: track.Id, Format: format, Data: data, Segments: segments}, nil
+}
+
+// FormatSRT renders segments as a SubRip (SRT) file.
+func FormatSRT(segments []*TranscriptSegment) []byte {
+	var b bytes.Buffer
+	for i, s := range segments {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, subtitleTimestamp(s.Start, ','), subtitleTimestamp(s.Start+s.Duration, ','), s.Text)
+	}
+	return b.Bytes()
+}
+
+// FormatVTT renders segments as a WebVTT file.
+func FormatVTT(segments []*TranscriptSegment) []byte {
+	var b bytes.Buffer
+	b.WriteString("WEBVTT\n\n")
+	for _, s := range segments {
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n", subtitleTimestamp(s.Start, '.'), subtitleTimestamp(s.Start+s.Duration, '.'), s.Text)
+	}
+	return b.Bytes()
+}
+
+// subtitleTimestamp formats d as hh:mm:ss followed by sep and milliseconds.
+func subtitleTimestamp(d time.Duration, sep byte) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// cueTiming matches the timing line of an SRT or WebVTT cue. WebVTT may omit the hours.
+var cueTiming = regexp.MustCompile(`^((?:\d+:)?\d{2}:\d{2}[,.]\d{3})\s+-->\s+((?:\d+:)?\d{2}:\d{2}[,.]\d{3})`)
+
+// cueTag matches the voice, class, and timestamp tags of WebVTT cue text.
+var cueTag = regexp.MustCompile(`<[^>]*>`)
+
+// parseSubtitles parses the cues of an SRT or WebVTT file into segments, ignoring everything else.
+func parseSubtitles(data []byte) []*TranscriptSegment {
+	var segments []*TranscriptSegment
+	var current *TranscriptSegment
+	var text []string
+	flush := func() {
+		if current != nil {
+			current.Text = strings.Join(text, " ")
+			segments = append(segments, current)
+		}
+		current, text = nil, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch m := cueTiming.FindStringSubmatch(line); {
+		case m != nil:
+			flush()
+			start, end := parseCueTimestamp(m[1]), parseCueTimestamp(m[2])
+			current = &TranscriptSegment{Start: start, Duration: end - start}
+		case line == "":
+			flush()
+		case current != nil:
+			text = append(text, strings.TrimSpace(html.UnescapeString(cueTag.ReplaceAllString(line, ""))))
+		}
+	}
+	flush()
+	return segments
+}
+
+// parseCueTimestamp converts a cue timestamp such as "01:02:03,456" or "02:03.456" into a time.Duration.
+func parseCueTimestamp(s string) time.Duration {
+	s = strings.Replace(s, ",", ".", 1)
+	fields := strings.Split(s, ":")
+	minutes := 0
+	for _, f := range fields[:len(fields)-1] {
+		n, _ := strconv.Atoi(f)
+		minutes = minutes*60 + n
+	}
+	return time.Duration(minutes)*time.Minute + parseSeconds(fields[len(fields)-1])
+}
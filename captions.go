@@ -0,0 +1,202 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ListCaptions = "https://www.googleapis.com/youtube/v3/captions?part=snippet&videoId=%s&key=%s"
+const GetTimedText = "https://www.youtube.com/api/timedtext?v=%s&lang=%s%s"
+
+// Caption track kinds reported in CaptionTrack.Snippet.TrackKind.
+const (
+	TrackKindStandard = "standard"
+	TrackKindASR      = "asr"
+	TrackKindForced   = "forced"
+)
+
+// CaptionTracks contains the caption tracks available for a video.
+type CaptionTracks struct {
+	Items []*CaptionTrack `bson:"items,omitempty" json:"items,omitempty"`
+}
+
+// CaptionTrack describes a single caption track of a video.
+type CaptionTrack struct {
+	Id      string `bson:"id,omitempty" json:"id,omitempty"`
+	Snippet *struct {
+		VideoId        string    `bson:"videoId,omitempty" json:"videoId,omitempty"`
+		LastUpdated    time.Time `bson:"lastUpdated,omitempty" json:"lastUpdated,omitempty"`
+		TrackKind      string    `bson:"trackKind,omitempty" json:"trackKind,omitempty"`
+		Language       string    `bson:"language,omitempty" json:"language,omitempty"`
+		Name           string    `bson:"name,omitempty" json:"name,omitempty"`
+		AudioTrackType string    `bson:"audioTrackType,omitempty" json:"audioTrackType,omitempty"`
+		IsDraft        bool      `bson:"isDraft,omitempty" json:"isDraft,omitempty"`
+		IsAutoSynced   bool      `bson:"isAutoSynced,omitempty" json:"isAutoSynced,omitempty"`
+	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
+}
+
+// IsAutoGenerated reports whether the track was produced by YouTube's speech recognition.
+func (t *CaptionTrack) IsAutoGenerated() bool {
+	return t.Snippet != nil && t.Snippet.TrackKind == TrackKindASR
+}
+
+// TranscriptOptions selects which caption track GetTranscript fetches.
+type TranscriptOptions struct {
+	// Language is the BCP-47 language code of the track, e.g. "en". When empty the
+	// first uploaded track is used, falling back to the auto-generated one.
+	Language string
+	// AutoGenerated prefers the auto-generated track over uploaded ones.
+	AutoGenerated bool
+}
+
+// Transcript is the timed text of a single caption track.
+type Transcript struct {
+	VideoId       string               `bson:"videoId,omitempty" json:"videoId,omitempty"`
+	Language      string               `bson:"language,omitempty" json:"language,omitempty"`
+	AutoGenerated bool                 `bson:"autoGenerated,omitempty" json:"autoGenerated,omitempty"`
+	Segments      []*TranscriptSegment `bson:"segments,omitempty" json:"segments,omitempty"`
+}
+
+// TranscriptSegment is one timed line of a transcript.
+type TranscriptSegment struct {
+	Start    time.Duration `bson:"start" json:"start"`
+	Duration time.Duration `bson:"duration" json:"duration"`
+	Text     string        `bson:"text,omitempty" json:"text,omitempty"`
+}
+
+// Text joins all segments of the transcript into a single space-separated string.
+func (t *Transcript) Text() string {
+	lines := make([]string, 0, len(t.Segments))
+	for _, s := range t.Segments {
+		lines = append(lines, s.Text)
+	}
+	return strings.Join(lines, " ")
+}
+
+// ListCaptionTracks retrieves the caption tracks available for a video.
+func (yt *YoutubeApi) ListCaptionTracks(videoId string) (*CaptionTracks, error) {
+	return yt.ListCaptionTracksContext(context.Background(), videoId)
+}
+
+// ListCaptionTracksContext is like ListCaptionTracks but uses ctx for the underlying API request.
+func (yt *YoutubeApi) ListCaptionTracksContext(ctx context.Context, videoId string) (*CaptionTracks, error) {
+	body, err := yt.httpGetRequest(ctx, fmt.Sprintf(ListCaptions, videoId, yt.apiKey))
+	if err != nil {
+		return nil, err
+	}
+	res := &CaptionTracks{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal caption tracks: %w", err)
+	}
+	return res, nil
+}
+
+// GetTranscript fetches the transcript of a public video as timed segments.
+// The track is chosen from ListCaptionTracks according to opts and downloaded through
+// YouTube's timed text endpoint, which does not require OAuth.
+func (yt *YoutubeApi) GetTranscript(videoId string, opts TranscriptOptions) (*Transcript, error) {
+	return yt.GetTranscriptContext(context.Background(), videoId, opts)
+}
+
+// GetTranscriptContext is like GetTranscript but uses ctx for the underlying requests.
+func (yt *YoutubeApi) GetTranscriptContext(ctx context.Context, videoId string, opts TranscriptOptions) (*Transcript, error) {
+	tracks, err := yt.ListCaptionTracksContext(ctx, videoId)
+	if err != nil {
+		return nil, err
+	}
+	track := selectCaptionTrack(tracks.Items, opts)
+	if track == nil {
+		return nil, fmt.Errorf("no caption track for video %s, language %q: %w", videoId, opts.Language, ErrNotFound)
+	}
+
+	extra := ""
+	if track.IsAutoGenerated() {
+		extra += "&kind=asr"
+	}
+	if track.Snippet.Name != "" {
+		extra += "&name=" + url.QueryEscape(track.Snippet.Name)
+	}
+	body, err := yt.httpGetRequest(ctx, fmt.Sprintf(GetTimedText, videoId, url.QueryEscape(track.Snippet.Language), extra))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("empty transcript for video %s: %w", videoId, ErrNotFound)
+	}
+	segments, err := parseTimedText(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transcript{
+		VideoId:       videoId,
+		Language:      track.Snippet.Language,
+		AutoGenerated: track.IsAutoGenerated(),
+		Segments:      segments,
+	}, nil
+}
+
+// selectCaptionTrack picks the track matching opts, preferring uploaded tracks
+// unless auto-generated ones were requested.
+func selectCaptionTrack(tracks []*CaptionTrack, opts TranscriptOptions) *CaptionTrack {
+	var preferred, fallback *CaptionTrack
+	for _, t := range tracks {
+		if t.Snippet == nil || t.Snippet.IsDraft {
+			continue
+		}
+		if opts.Language != "" && !strings.EqualFold(t.Snippet.Language, opts.Language) {
+			continue
+		}
+		if t.IsAutoGenerated() == opts.AutoGenerated {
+			if preferred == nil {
+				preferred = t
+			}
+		} else if fallback == nil {
+			fallback = t
+		}
+	}
+	if preferred != nil {
+		return preferred
+	}
+	return fallback
+}
+
+// parseTimedText parses the XML returned by the timed text endpoint into segments.
+func parseTimedText(body []byte) ([]*TranscriptSegment, error) {
+	doc := struct {
+		Texts []struct {
+			Start string `xml:"start,attr"`
+			Dur   string `xml:"dur,attr"`
+			Text  string `xml:",chardata"`
+		} `xml:"text"`
+	}{}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse timed text: %w", err)
+	}
+
+	segments := make([]*TranscriptSegment, 0, len(doc.Texts))
+	for _, t := range doc.Texts {
+		segments = append(segments, &TranscriptSegment{
+			Start:    parseSeconds(t.Start),
+			Duration: parseSeconds(t.Dur),
+			Text:     strings.TrimSpace(html.UnescapeString(t.Text)),
+		})
+	}
+	return segments, nil
+}
+
+// parseSeconds converts a decimal number of seconds such as "12.34" into a time.Duration.
+func parseSeconds(s string) time.Duration {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(f * float64(time.Second))
+}
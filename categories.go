@@ -0,0 +1,127 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+)
+
+const ListVideoCategories = "https://www.googleapis.com/youtube/v3/videoCategories?part=snippet&regionCode=%s&key=%s"
+
+// DefaultCategoryRegion is the region used to resolve category names when none is given.
+const DefaultCategoryRegion = "US"
+
+// VideoCategoryResults contains the video categories available in a region.
+type VideoCategoryResults struct {
+	Items []*VideoCategory `bson:"items,omitempty" json:"items,omitempty"`
+}
+
+// VideoCategory is a category a video can be uploaded under, e.g. "Music" or "Gaming".
+type VideoCategory struct {
+	Id      string `bson:"id,omitempty" json:"id,omitempty"`
+	Snippet *struct {
+		Title      string `bson:"title,omitempty" json:"title,omitempty"`
+		Assignable bool   `bson:"assignable,omitempty" json:"assignable,omitempty"`
+		ChannelId  string `bson:"channelId,omitempty" json:"channelId,omitempty"`
+	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
+}
+
+// Names maps category IDs to their titles.
+func (r *VideoCategoryResults) Names() map[string]string {
+	names := make(map[string]string, len(r.Items))
+	for _, c := range r.Items {
+		if c.Snippet != nil {
+			names[c.Id] = c.Snippet.Title
+		}
+	}
+	return names
+}
+
+// GetVideoCategories retrieves the video categories of a region, e.g. "US". Results are cached per region.
+func (yt *YoutubeApi) GetVideoCategories(regionCode string) (*VideoCategoryResults, error) {
+	return yt.GetVideoCategoriesContext(context.Background(), regionCode)
+}
+
+// GetVideoCategoriesContext is like GetVideoCategories but uses ctx for the underlying API request.
+func (yt *YoutubeApi) GetVideoCategoriesContext(ctx context.Context, regionCode string) (*VideoCategoryResults, error) {
+	if regionCode == "" {
+		regionCode = DefaultCategoryRegion
+	}
+	cacheKey := CacheKey("videoCategories", url.Values{"regionCode": {regionCode}})
+	if v := yt.Cache.GetCategories(cacheKey); v != nil {
+		return v, nil
+	}
+
+	body, err := yt.httpGetRequest(ctx, fmt.Sprintf(ListVideoCategories, url.QueryEscape(regionCode), yt.apiKey))
+	if err != nil {
+		return nil, err
+	}
+	res := &VideoCategoryResults{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal video categories: %w", err)
+	}
+
+	yt.Cache.SetCategories(cacheKey, res)
+
+	return res, nil
+}
+
+// GroupByCategoryName groups videos by the name of their category in the given region.
+// Videos whose category is unknown are grouped under their raw category ID.
+func (yt *YoutubeApi) GroupByCategoryName(ctx context.Context, results *VideoResults, regionCode string) (map[string][]*Video, error) {
+	categories, err := yt.GetVideoCategoriesContext(ctx, regionCode)
+	if err != nil {
+		return nil, err
+	}
+	names := categories.Names()
+	groups := make(map[string][]*Video)
+	for id, videos := range results.GroupByCategory() {
+		name, ok := names[id]
+		if !ok {
+			name = id
+		}
+		groups[name] = append(groups[name], videos...)
+	}
+	return groups, nil
+}
+
+// GroupByCategory groups videos by category ID. Videos without a category are grouped under "".
+func (r *VideoResults) GroupByCategory() map[string][]*Video {
+	groups := make(map[string][]*Video)
+	for _, v := range r.Items {
+		id := ""
+		if v.Snippet != nil {
+			id = v.Snippet.CategoryId
+		}
+		groups[id] = append(groups[id], v)
+	}
+	return groups
+}
+
+// GroupByTopic groups videos by topic, named after the Wikipedia article of each topic category,
+// e.g. "Video_game_culture". A video appears once under every topic it has.
+func (r *VideoResults) GroupByTopic() map[string][]*Video {
+	groups := make(map[string][]*Video)
+	for _, v := range r.Items {
+		for _, topic := range v.Topics() {
+			groups[topic] = append(groups[topic], v)
+		}
+	}
+	return groups
+}
+
+// Topics returns the names of the video's topic categories, taken from their Wikipedia URLs.
+func (v *Video) Topics() []string {
+	if v.TopicDetails == nil {
+		return nil
+	}
+	topics := make([]string, 0, len(v.TopicDetails.TopicCategories))
+	for _, category := range v.TopicDetails.TopicCategories {
+		if u, err := url.Parse(category); err == nil && u.Path != "" {
+			topics = append(topics, path.Base(u.Path))
+		}
+	}
+	return topics
+}
@@ -0,0 +1,82 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+const ListChannelSections = "https://www.googleapis.com/youtube/v3/channelSections?part=snippet,contentDetails&channelId=%s&key=%s"
+
+// Channel section types reported in ChannelSection.Snippet.Type.
+const (
+	SectionSinglePlaylist     = "singlePlaylist"
+	SectionMultiplePlaylists  = "multiplePlaylists"
+	SectionMultipleChannels   = "multipleChannels"
+	SectionRecentUploads      = "recentUploads"
+	SectionPopularUploads     = "popularUploads"
+	SectionAllPlaylists       = "allPlaylists"
+	SectionCompletedEvents    = "completedEvents"
+	SectionLiveEvents         = "liveEvents"
+	SectionUpcomingEvents     = "upcomingEvents"
+	SectionSubscriptions      = "subscriptions"
+	SectionChannelDescription = "channelDescription"
+)
+
+// ChannelSectionResults contains the sections of a channel's home page.
+type ChannelSectionResults struct {
+	Items []*ChannelSection `bson:"items,omitempty" json:"items,omitempty"`
+}
+
+// ChannelSection is a shelf of a channel's home page, such as its popular uploads or a featured
+// playlist. Shelves listing playlists or channels carry their IDs in ContentDetails.
+type ChannelSection struct {
+	Id      string `bson:"id,omitempty" json:"id,omitempty"`
+	Snippet *struct {
+		Type      string `bson:"type,omitempty" json:"type,omitempty"`
+		ChannelId string `bson:"channelId,omitempty" json:"channelId,omitempty"`
+		// Title is only set for sections of type SectionMultiplePlaylists and SectionMultipleChannels.
+		Title    string `bson:"title,omitempty" json:"title,omitempty"`
+		Position int    `bson:"position" json:"position"`
+	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
+	ContentDetails *struct {
+		Playlists []string `bson:"playlists,omitempty" json:"playlists,omitempty"`
+		Channels  []string `bson:"channels,omitempty" json:"channels,omitempty"`
+	} `bson:"contentDetails,omitempty" json:"contentDetails,omitempty"`
+}
+
+// GetChannelSections retrieves the sections of a channel's home page, ordered by position.
+// Results are not cached.
+func (yt *YoutubeApi) GetChannelSections(channelId string) (*ChannelSectionResults, error) {
+	return yt.GetChannelSectionsContext(context.Background(), channelId)
+}
+
+// GetChannelSectionsContext is like GetChannelSections but uses ctx for the underlying API request.
+func (yt *YoutubeApi) GetChannelSectionsContext(ctx context.Context, channelId string) (_ *ChannelSectionResults, err error) {
+	ctx, span := yt.startOperation(ctx, "GetChannelSections")
+	defer func() { endSpan(span, err) }()
+
+	body, err := yt.sendRequest(ctx, http.MethodGet, fmt.Sprintf(ListChannelSections, url.QueryEscape(channelId), yt.apiKey), nil, authAuto)
+	if err != nil {
+		return nil, err
+	}
+	results := &ChannelSectionResults{}
+	if err := json.Unmarshal(body, results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal channel sections: %w", err)
+	}
+	sort.SliceStable(results.Items, func(i, j int) bool {
+		return sectionPosition(results.Items[i]) < sectionPosition(results.Items[j])
+	})
+	return results, nil
+}
+
+// sectionPosition returns the position of section, or 0 when its snippet wasn't fetched.
+func sectionPosition(section *ChannelSection) int {
+	if section.Snippet == nil {
+		return 0
+	}
+	return section.Snippet.Position
+}
@@ -0,0 +1,83 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+const GetLocalizedChannels = "https://www.googleapis.com/youtube/v3/channels/?part=snippet,contentDetails,statistics,localizations&id=%v&hl=%s&maxResults=50&key=%v"
+
+// GetChannels retrieves several channels, returning them keyed by channel ID.
+// Channels are looked up in the cache one by one and the missing ones are requested in
+// batches of 50, so enriching a page of search results costs at most one request.
+// IDs YouTube doesn't know are absent from the result and remembered as not found.
+func (yt *YoutubeApi) GetChannels(channelIds []string) (map[string]*Item, error) {
+	return yt.GetChannelsContext(context.Background(), channelIds)
+}
+
+// GetChannelsContext is like GetChannels but uses ctx for every batch request.
+func (yt *YoutubeApi) GetChannelsContext(ctx context.Context, channelIds []string) (map[string]*Item, error) {
+	return yt.getChannels(ctx, "GetChannels", channelIds, "")
+}
+
+// GetChannelsLocalized is like GetChannels but also fetches the localizations part, in
+// Item.Localizations, and the snippet localized in language, a BCP-47 code such as "fr", in
+// Item.Snippet.Localized. Results are cached separately for every language.
+func (yt *YoutubeApi) GetChannelsLocalized(ctx context.Context, channelIds []string, language string) (map[string]*Item, error) {
+	return yt.getChannels(ctx, "GetChannelsLocalized", channelIds, language)
+}
+
+// getChannels implements GetChannels, fetching the localized channels when language is set.
+func (yt *YoutubeApi) getChannels(ctx context.Context, operation string, channelIds []string, language string) (_ map[string]*Item, err error) {
+	channelIds = uniqueIds(channelIds)
+	ctx, span := yt.startOperation(ctx, operation, AttrBatchSize.Int(len(channelIds)))
+	defer func() { endSpan(span, err) }()
+
+	channels := make(map[string]*Item, len(channelIds))
+	var missing []string
+	for _, id := range channelIds {
+		// Localized channels are cached under their own keys, so they don't replace the plain ones.
+		if v := yt.Cache.GetChannel(channelCacheKey(id, language)); v != nil && len(v.Items) > 0 {
+			channels[id] = v.Items[0]
+			continue
+		}
+		if yt.cachedNotFound(RegionChannels, channelCacheKey(id, ""), id) != nil {
+			continue
+		}
+		missing = append(missing, id)
+	}
+	span.SetAttributes(AttrCacheHit.Bool(len(missing) == 0))
+
+	for page, batch := range batchIteration(missing) {
+		batchCtx := withSpanAttributes(ctx, AttrPage.Int(page))
+		apiUrl := fmt.Sprintf(GetChannelVideos, batch, yt.apiKey)
+		if language != "" {
+			apiUrl = fmt.Sprintf(GetLocalizedChannels, batch, url.QueryEscape(language), yt.apiKey)
+		}
+		body, err := yt.httpGetRequest(batchCtx, apiUrl)
+		if err != nil {
+			return channels, err
+		}
+		res := &ChannelInfo{}
+		if err := json.Unmarshal(body, res); err != nil {
+			return channels, fmt.Errorf("failed to unmarshal channels: %w", err)
+		}
+		for _, item := range res.Items {
+			if item == nil {
+				continue
+			}
+			channels[item.Id] = item
+			yt.Cache.SetChannel(channelCacheKey(item.Id, language), &ChannelInfo{Items: []*Item{item}})
+		}
+		yt.persistChannels(ctx, res)
+	}
+
+	for _, id := range missing {
+		if _, ok := channels[id]; !ok {
+			yt.rememberNotFound(RegionChannels, channelCacheKey(id, ""), ErrNotFound)
+		}
+	}
+	return channels, nil
+}
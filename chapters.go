@@ -0,0 +1,92 @@
+package alaitube
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinChapters is the number of timestamps a description needs for YouTube to show chapters.
+const MinChapters = 3
+
+// chapterLine matches a description line holding a timestamp, such as "00:00 Intro",
+// "1:02:03 - Wrap-up", or "Intro (0:00)". The timestamp is in the second group.
+var chapterLine = regexp.MustCompile(`^(.*?)[\[(]?\b((?:\d{1,2}:)?\d{1,2}:\d{2})\b[\])]?(.*)$`)
+
+// Chapter is a section of a video, as listed in its description.
+type Chapter struct {
+	Title string        `bson:"title,omitempty" json:"title,omitempty"`
+	Start time.Duration `bson:"start" json:"start"`
+	// End is the start of the next chapter. It is zero for the last chapter unless the video's
+	// duration is known.
+	End time.Duration `bson:"end,omitempty" json:"end,omitempty"`
+}
+
+// ParseChapters extracts the chapter list of a video description. Like YouTube, it only accepts a
+// list of at least MinChapters timestamps in ascending order starting at 0:00, and returns nil
+// otherwise. Lines without a timestamp are ignored, as are timestamps appearing before 0:00,
+// such as in an intro paragraph.
+func ParseChapters(description string) []Chapter {
+	var chapters []Chapter
+	for _, line := range strings.Split(description, "\n") {
+		m := chapterLine.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		start, ok := parseTimestamp(m[2])
+		if !ok {
+			continue
+		}
+		if len(chapters) == 0 && start != 0 {
+			continue
+		}
+		if len(chapters) > 0 && start <= chapters[len(chapters)-1].Start {
+			break
+		}
+		title := strings.Trim(strings.TrimSpace(m[1]+" "+m[3]), " -–—:|•")
+		chapters = append(chapters, Chapter{Title: strings.TrimSpace(title), Start: start})
+	}
+	if len(chapters) < MinChapters {
+		return nil
+	}
+	for i := range chapters[:len(chapters)-1] {
+		chapters[i].End = chapters[i+1].Start
+	}
+	return chapters
+}
+
+// parseTimestamp converts a timestamp such as "1:02:03" or "02:03" into a time.Duration.
+func parseTimestamp(s string) (time.Duration, bool) {
+	var d time.Duration
+	fields := strings.Split(s, ":")
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || (i > 0 && n >= 60) {
+			return 0, false
+		}
+		d = d*60 + time.Duration(n)
+	}
+	return d * time.Second, true
+}
+
+// EnrichWithChapters parses the chapters of every video description of results into Video.Chapters.
+// The last chapter of each video ends with the video when its duration is known. Like
+// EnrichWithChannelStats, it replaces the videos with chapters by enriched copies.
+func (r *VideoResults) EnrichWithChapters() {
+	for i, v := range r.Items {
+		if v == nil || v.Snippet == nil {
+			continue
+		}
+		chapters := ParseChapters(v.Snippet.Description)
+		if chapters == nil {
+			continue
+		}
+		if n := len(chapters); v.Duration() > chapters[n-1].Start {
+			chapters[n-1].End = v.Duration()
+		}
+		enriched := *v
+		enriched.Chapters = chapters
+		r.Items[i] = &enriched
+	}
+}
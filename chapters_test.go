@@ -0,0 +1,87 @@
+package alaitube_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/josephalai/alaitube"
+)
+
+func TestParseChapters(t *testing.T) {
+	min := func(m, s int) time.Duration { return time.Duration(m)*time.Minute + time.Duration(s)*time.Second }
+	tests := []struct {
+		name        string
+		description string
+		want        []alaitube.Chapter
+	}{
+		{
+			name:        "minutes and seconds",
+			description: "My video\n\n0:00 Intro\n1:30 Setup\n10:05 Demo\n\nThanks for watching!",
+			want: []alaitube.Chapter{
+				{Title: "Intro", Start: 0, End: min(1, 30)},
+				{Title: "Setup", Start: min(1, 30), End: min(10, 5)},
+				{Title: "Demo", Start: min(10, 5)},
+			},
+		},
+		{
+			name:        "hours, minutes, and seconds",
+			description: "00:00 Intro\n45:00 - Middle\n1:02:03 – Wrap-up",
+			want: []alaitube.Chapter{
+				{Title: "Intro", Start: 0, End: min(45, 0)},
+				{Title: "Middle", Start: min(45, 0), End: time.Hour + min(2, 3)},
+				{Title: "Wrap-up", Start: time.Hour + min(2, 3)},
+			},
+		},
+		{
+			name:        "timestamps after the titles",
+			description: "Intro (0:00)\nSetup [2:00]\nDemo 3:15",
+			want: []alaitube.Chapter{
+				{Title: "Intro", Start: 0, End: min(2, 0)},
+				{Title: "Setup", Start: min(2, 0), End: min(3, 15)},
+				{Title: "Demo", Start: min(3, 15)},
+			},
+		},
+		{
+			name:        "timestamp before the list ignored",
+			description: "Skip to 5:00 for the demo.\n0:00 Intro\n1:00 Setup\n5:00 Demo",
+			want: []alaitube.Chapter{
+				{Title: "Intro", Start: 0, End: min(1, 0)},
+				{Title: "Setup", Start: min(1, 0), End: min(5, 0)},
+				{Title: "Demo", Start: min(5, 0)},
+			},
+		},
+		{name: "first chapter after 0:00", description: "0:30 Intro\n1:00 Setup\n2:00 Demo"},
+		{name: "too few chapters", description: "0:00 Intro\n1:00 Demo"},
+		{name: "out of order", description: "0:00 Intro\n2:00 Setup\n1:00 Demo\n3:00 Outro"},
+		{name: "invalid seconds", description: "0:00 Intro\n1:75 Setup\n2:00 Demo"},
+		{name: "no timestamps", description: "Just a video about cats.\nSubscribe for more!"},
+		{name: "empty", description: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alaitube.ParseChapters(tt.description); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseChapters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnrichWithChapters(t *testing.T) {
+	var v alaitube.Video
+	data := `{"id":"a","snippet":{"description":"0:00 Intro\n1:00 Setup\n2:00 Demo"},"contentDetails":{"duration":"PT3M"}}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+	results := &alaitube.VideoResults{Items: []*alaitube.Video{&v}}
+	results.EnrichWithChapters()
+
+	chapters := results.Items[0].Chapters
+	if len(chapters) != 3 || chapters[2].End != 3*time.Minute {
+		t.Errorf("chapters = %+v, want 3 chapters, the last ending at 3m", chapters)
+	}
+	if v.Chapters != nil {
+		t.Errorf("original video was modified")
+	}
+}
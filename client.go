@@ -0,0 +1,68 @@
+package alaitube
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds every request made by a client that was not given its own http.Client.
+const DefaultTimeout = 30 * time.Second
+
+// Option configures a YoutubeApi client created with NewClient.
+type Option func(*YoutubeApi)
+
+// WithApiKey sets the YouTube Data API key used to authenticate requests.
+func WithApiKey(apiKey string) Option {
+	return func(yt *YoutubeApi) {
+		yt.apiKey = apiKey
+	}
+}
+
+// WithCache sets the Cache used to store API results.
+// A nil cache is ignored and the default MemoryCache is kept.
+func WithCache(cache Cache) Option {
+	return func(yt *YoutubeApi) {
+		if cache != nil {
+			yt.Cache = cache
+		}
+	}
+}
+
+// WithHttpClient sets the http.Client used for every API request,
+// allowing callers to configure timeouts, proxies, or an instrumented transport.
+// A nil client is ignored and the default client is kept.
+func WithHttpClient(client *http.Client) Option {
+	return func(yt *YoutubeApi) {
+		if client != nil {
+			yt.httpClient = client
+		}
+	}
+}
+
+// NewClient creates an independent YoutubeApi client configured by the given options.
+// Unlike GetInstance, every call returns a new client with its own API key and cache,
+// so several keys can be used side by side in the same process.
+// When no cache is provided, a fresh MemoryCache is used, and when no http.Client
+// is provided, requests use a client with DefaultTimeout.
+func NewClient(opts ...Option) *YoutubeApi {
+	yt := &YoutubeApi{
+		httpClient:  &http.Client{Timeout: DefaultTimeout},
+		retry:       DefaultRetryPolicy,
+		Cache:       NewMemoryCache(),
+		logger:      NewSlogLogger(nil),
+		tracer:      defaultTracer,
+		videoParts:  PartsStandard,
+		compression: true,
+		userAgent:   DefaultUserAgent,
+		languages:   BasicLanguageDetector{},
+	}
+	for _, opt := range opts {
+		opt(yt)
+	}
+	yt.applyDryRun()
+	yt.applyMiddlewares()
+	if yt.observer != nil {
+		yt.Cache = observedCache{Cache: yt.Cache, observer: yt.observer}
+	}
+	return yt
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/go-redis/redis"
+	"github.com/josephalai/alaitube"
+	"github.com/josephalai/alaitube/boltcache"
+	"github.com/josephalai/alaitube/memcached"
+)
+
+// cacheUsage documents the values accepted by the -cache flag.
+const cacheUsage = `cache backend: "memory", "file:DIR", "bolt:PATH", "redis://HOST:PORT/DB", or "memcached:HOST:PORT"`
+
+// nopCloser is returned for caches that hold no resources.
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// openCache creates the cache described by spec, along with a closer releasing its resources.
+func openCache(spec string) (alaitube.Cache, io.Closer, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "", "memory":
+		return alaitube.NewMemoryCache(), nopCloser{}, nil
+	case "file":
+		if arg == "" {
+			return nil, nil, fmt.Errorf("file cache requires a directory, e.g. file:/tmp/alai-youtube")
+		}
+		c, err := alaitube.NewFileCache(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, nopCloser{}, nil
+	case "bolt":
+		if arg == "" {
+			return nil, nil, fmt.Errorf("bolt cache requires a path, e.g. bolt:alai-youtube.db")
+		}
+		store, err := boltcache.Open(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store.Cache(), store, nil
+	case "redis", "rediss":
+		opts, err := redis.ParseURL(spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid redis cache url: %w", err)
+		}
+		client := redis.NewClient(opts)
+		return alaitube.NewRedisCache(client), client, nil
+	case "memcached":
+		if arg == "" {
+			return nil, nil, fmt.Errorf("memcached cache requires a server, e.g. memcached:localhost:11211")
+		}
+		return memcached.New(memcache.New(strings.Split(arg, ",")...)).Cache(), nopCloser{}, nil
+	}
+	return nil, nil, fmt.Errorf("unknown cache backend %q", kind)
+}
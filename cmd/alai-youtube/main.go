@@ -0,0 +1,413 @@
+// Command alai-youtube searches YouTube, analyzes tags, and exports videos and channels from the
+// command line with the alaitube client.
+//
+// Usage:
+//
+//	alai-youtube <command> [flags] <arguments>
+//
+// The commands are:
+//
+//	search    search videos matching a query
+//	tags      rank the tags of the videos matching a query
+//	channel   show a channel, or its latest uploads with -uploads
+//	playlist  list the videos of a playlist
+//	export    write the videos of a search, channel, or playlist to a file
+//	mcp       serve the client's tools to an LLM agent over stdio
+//
+// The API key is read from the -key flag or the YOUTUBE_API_KEY environment variable, and the
+// cache backend from the -cache flag or ALAI_YOUTUBE_CACHE, e.g. "file:$HOME/.cache/alai-youtube"
+// to reuse results across runs. Results are printed as a table, JSON, or CSV with -output.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/josephalai/alaitube"
+	"github.com/josephalai/alaitube/mcp"
+	"github.com/josephalai/alaitube/parquetexport"
+	"github.com/josephalai/alaitube/xlsxexport"
+)
+
+// Environment variables read for the defaults of the common flags.
+const (
+	envApiKey = "YOUTUBE_API_KEY"
+	envCache  = "ALAI_YOUTUBE_CACHE"
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	name  string
+	args  string
+	short string
+	run   func(ctx context.Context, env *env, fs *flag.FlagSet, args []string) error
+	flags func(fs *flag.FlagSet)
+	// serves is set for commands running until interrupted, which have no -timeout by default.
+	serves bool
+}
+
+var commands = []*command{searchCommand, tagsCommand, channelCommand, playlistCommand, exportCommand, mcpCommand}
+
+// errUsage reports invalid arguments, after the command's usage has been printed.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	log := func(format string, args ...interface{}) {
+		fmt.Fprintf(os.Stderr, "alai-youtube: "+format+"\n", args...)
+	}
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	var cmd *command
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			cmd = c
+		}
+	}
+	if cmd == nil {
+		log("unknown command %q", os.Args[1])
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := runCommand(ctx, cmd, os.Args[2:], os.Stdout); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		log("%v", err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the list of commands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: alai-youtube <command> [flags] <arguments>")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", c.name, c.short)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "alai-youtube <command> -h" for the flags of a command.`)
+}
+
+// env holds the state shared by the commands: the client, built from the common flags, and the
+// writer results are printed to.
+type env struct {
+	yt     *alaitube.YoutubeApi
+	output string
+	stdout io.Writer
+}
+
+// runCommand parses the flags of cmd and runs it with a client configured by them.
+func runCommand(ctx context.Context, cmd *command, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	// The environment is read after parsing, so -h doesn't print the API key as a default.
+	key := fs.String("key", "", "YouTube Data API key (default $"+envApiKey+")")
+	cacheSpec := fs.String("cache", "", cacheUsage+` (default $`+envCache+` or "memory")`)
+	defaultTimeout := 2 * time.Minute
+	if cmd.serves {
+		defaultTimeout = 0
+	}
+	timeout := fs.Duration("timeout", defaultTimeout, "overall time limit of the command, 0 for none")
+	output := fs.String("output", outputTable, "output format: table, json, or csv")
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: alai-youtube %s [flags] %s\n\n%s.\n\nFlags:\n", cmd.name, cmd.args, strings.ToUpper(cmd.short[:1])+cmd.short[1:])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+	if *key == "" {
+		*key = os.Getenv(envApiKey)
+	}
+	if *cacheSpec == "" {
+		*cacheSpec = os.Getenv(envCache)
+	}
+	if *key == "" {
+		return fmt.Errorf("no API key: set -key or $%s", envApiKey)
+	}
+
+	cache, closer, err := openCache(*cacheSpec)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	e := &env{
+		yt:     alaitube.NewClient(alaitube.WithApiKey(*key), alaitube.WithCache(cache)),
+		output: *output,
+		stdout: stdout,
+	}
+	return cmd.run(ctx, e, fs, fs.Args())
+}
+
+// argument returns the single positional argument of a command, or all of them joined with
+// spaces when joined is set, so queries don't need quoting.
+func argument(fs *flag.FlagSet, args []string, joined bool) (string, error) {
+	if len(args) == 0 || (!joined && len(args) > 1) {
+		fs.Usage()
+		return "", errUsage
+	}
+	return strings.Join(args, " "), nil
+}
+
+// searchFlags are the flags of the commands running a search.
+type searchFlags struct {
+	pages    int
+	order    string
+	region   string
+	language string
+	limit    int
+}
+
+func (f *searchFlags) register(fs *flag.FlagSet) {
+	fs.IntVar(&f.pages, "pages", 1, "search result pages to request, 50 videos and 100 quota units each")
+	fs.StringVar(&f.order, "order", alaitube.OrderRelevance, "search order: relevance, date, viewCount, rating, or title")
+	fs.StringVar(&f.region, "region", "", "ISO 3166-1 alpha-2 region code to search in")
+	fs.StringVar(&f.language, "language", "", "ISO 639-1 language code the results are most relevant to")
+	fs.IntVar(&f.limit, "limit", 0, "maximum number of videos, 0 for all")
+}
+
+func (f *searchFlags) options() alaitube.SearchOptions {
+	return alaitube.SearchOptions{Order: f.order, RegionCode: f.region, RelevanceLanguage: f.language, Limit: f.limit}
+}
+
+var searchCommand = func() *command {
+	var sf searchFlags
+	return &command{
+		name:  "search",
+		args:  "<query>",
+		short: "search videos matching a query",
+		flags: sf.register,
+		run: func(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+			query, err := argument(fs, args, true)
+			if err != nil {
+				return err
+			}
+			results, err := e.yt.FindTagsContext(ctx, query, sf.pages, sf.options())
+			if err != nil {
+				return err
+			}
+			return printVideos(e.stdout, e.output, results)
+		},
+	}
+}()
+
+var tagsCommand = func() *command {
+	var sf searchFlags
+	var n int
+	var stem, hashtags bool
+	return &command{
+		name:  "tags",
+		args:  "<query>",
+		short: "rank the tags of the videos matching a query",
+		flags: func(fs *flag.FlagSet) {
+			sf.register(fs)
+			fs.IntVar(&n, "n", 30, "number of tags to print, 0 for all")
+			fs.BoolVar(&stem, "stem", false, "merge tags differing only by plural and verb forms")
+			fs.BoolVar(&hashtags, "hashtags", false, "count the hashtags of titles and descriptions as tags")
+		},
+		run: func(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+			query, err := argument(fs, args, true)
+			if err != nil {
+				return err
+			}
+			results, err := e.yt.FindTagsContext(ctx, query, sf.pages, sf.options())
+			if err != nil {
+				return err
+			}
+			report := alaitube.NewTagAnalyzer(alaitube.WithStemming(stem), alaitube.WithHashtags(hashtags)).Analyze(results)
+			return printTags(e.stdout, e.output, report.TopByScore(n))
+		},
+	}
+}()
+
+var channelCommand = func() *command {
+	var uploads int
+	return &command{
+		name:  "channel",
+		args:  "<channel-id>",
+		short: "show a channel, or its latest uploads with -uploads",
+		flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&uploads, "uploads", 0, "list this many of the channel's latest uploads instead")
+		},
+		run: func(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+			channelId, err := argument(fs, args, false)
+			if err != nil {
+				return err
+			}
+			if uploads > 0 {
+				results, err := channelUploads(ctx, e.yt, channelId, uploads)
+				if err != nil {
+					return err
+				}
+				return printVideos(e.stdout, e.output, results)
+			}
+			info, err := e.yt.GetChannelInfoContext(ctx, channelId)
+			if err != nil {
+				return err
+			}
+			return printChannels(e.stdout, e.output, info)
+		},
+	}
+}()
+
+// channelUploads returns the n latest uploads of a channel.
+func channelUploads(ctx context.Context, yt *alaitube.YoutubeApi, channelId string, n int) (*alaitube.VideoResults, error) {
+	info, err := yt.GetChannelInfoContext(ctx, channelId)
+	if err != nil {
+		return nil, err
+	}
+	if len(info.Items) == 0 {
+		return nil, fmt.Errorf("channel %s: %w", channelId, alaitube.ErrNotFound)
+	}
+	return yt.GetChannelPlaylistContext(ctx, info.Items[0], n)
+}
+
+var playlistCommand = func() *command {
+	var n int
+	return &command{
+		name:  "playlist",
+		args:  "<playlist-id>",
+		short: "list the videos of a playlist",
+		flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&n, "n", 50, "maximum number of videos")
+		},
+		run: func(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+			playlistId, err := argument(fs, args, false)
+			if err != nil {
+				return err
+			}
+			results, err := e.yt.GetPlaylistVideosContext(ctx, playlistId, n)
+			if err != nil {
+				return err
+			}
+			return printVideos(e.stdout, e.output, results)
+		},
+	}
+}()
+
+// Export formats accepted by the -format flag of the export command.
+var exportFormats = []string{"csv", "ndjson", "xlsx", "parquet"}
+
+var exportCommand = func() *command {
+	var sf searchFlags
+	var format, out string
+	var n int
+	return &command{
+		name:  "export",
+		args:  "search <query> | channel <channel-id> | playlist <playlist-id>",
+		short: "write the videos of a search, channel, or playlist to a file",
+		flags: func(fs *flag.FlagSet) {
+			sf.register(fs)
+			fs.StringVar(&format, "format", "", "file format: csv, ndjson, xlsx, or parquet (default from the -o extension, or csv)")
+			fs.StringVar(&out, "o", "", "file to write, or standard output when empty")
+			fs.IntVar(&n, "n", 50, "maximum number of channel or playlist videos")
+		},
+		run: func(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+			if len(args) < 2 {
+				fs.Usage()
+				return errUsage
+			}
+			if format == "" {
+				format = strings.TrimPrefix(filepath.Ext(out), ".")
+			}
+			if format == "" {
+				format = "csv"
+			}
+			if !containsString(exportFormats, format) {
+				return fmt.Errorf("unknown export format %q, want one of %s", format, strings.Join(exportFormats, ", "))
+			}
+
+			var results *alaitube.VideoResults
+			var err error
+			switch source, target := args[0], strings.Join(args[1:], " "); source {
+			case "search":
+				results, err = e.yt.FindTagsContext(ctx, target, sf.pages, sf.options())
+			case "channel":
+				results, err = channelUploads(ctx, e.yt, target, n)
+			case "playlist":
+				results, err = e.yt.GetPlaylistVideosContext(ctx, target, n)
+			default:
+				fs.Usage()
+				return errUsage
+			}
+			if err != nil {
+				return err
+			}
+			return exportVideos(e.stdout, out, format, results)
+		},
+	}
+}()
+
+var mcpCommand = &command{
+	name:   "mcp",
+	short:  "serve the client's tools to an LLM agent over stdio",
+	serves: true,
+	run: func(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+		if len(args) > 0 {
+			fs.Usage()
+			return errUsage
+		}
+		return mcp.NewServer(e.yt).Serve(ctx, os.Stdin, e.stdout)
+	},
+}
+
+// exportVideos writes results in format to the file out, or to stdout when out is empty.
+func exportVideos(stdout io.Writer, out, format string, results *alaitube.VideoResults) (err error) {
+	w := stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = f
+	}
+	switch format {
+	case "ndjson":
+		return alaitube.NewNDJSONWriter(w).WriteResults(results)
+	case "xlsx":
+		return xlsxexport.Write(w, xlsxexport.Workbook{Videos: results})
+	case "parquet":
+		return parquetexport.WriteVideos(w, results)
+	}
+	return alaitube.ExportVideosCSV(w, results, videoColumns...)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/josephalai/alaitube"
+)
+
+// Output formats accepted by the -output flag.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+)
+
+// checkOutput returns an error when format isn't a known output format.
+func checkOutput(format string) error {
+	switch format {
+	case outputTable, outputJSON, outputCSV:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, want table, json, or csv", format)
+}
+
+// Columns of CSV output: the library's defaults followed by the URL, for pasting into spreadsheets.
+var (
+	videoColumns   = append(append([]alaitube.VideoColumn(nil), alaitube.DefaultVideoColumns...), alaitube.VideoColumnUrl)
+	channelColumns = append(append([]alaitube.ChannelColumn(nil), alaitube.DefaultChannelColumns...), alaitube.ChannelColumnUrl)
+)
+
+// maxTitleWidth bounds the titles printed in tables, so rows fit in a terminal.
+const maxTitleWidth = 60
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+// countString formats a count, or "-" when it wasn't fetched.
+func countString(ok bool, n alaitube.Count) string {
+	if !ok {
+		return "-"
+	}
+	return strconv.FormatInt(n.Int64(), 10)
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printVideos writes results to w in format.
+func printVideos(w io.Writer, format string, results *alaitube.VideoResults) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(w, results)
+	case outputCSV:
+		return alaitube.ExportVideosCSV(w, results, videoColumns...)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tCHANNEL\tVIEWS\tLIKES\tPUBLISHED")
+	for _, v := range results.Items {
+		var title, channel string
+		if v.Snippet != nil {
+			title, channel = v.Snippet.Title, v.Snippet.ChannelTitle
+		}
+		var views, likes alaitube.Count
+		if v.Statistics != nil {
+			views, likes = v.Statistics.ViewCount, v.Statistics.LikeCount
+		}
+		published := "-"
+		if t := v.PublishedAt(); !t.IsZero() {
+			published = t.Format("2006-01-02")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", v.Id, truncate(title, maxTitleWidth), truncate(channel, 30),
+			countString(v.Statistics != nil, views), countString(v.Statistics != nil, likes), published)
+	}
+	return tw.Flush()
+}
+
+// printChannels writes info to w in format.
+func printChannels(w io.Writer, format string, info *alaitube.ChannelInfo) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(w, info)
+	case outputCSV:
+		return alaitube.ExportChannelsCSV(w, info, channelColumns...)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tSUBSCRIBERS\tVIEWS\tVIDEOS\tCOUNTRY")
+	for _, c := range info.Items {
+		var title, country string
+		if c.Snippet != nil {
+			title, country = c.Snippet.Title, c.Snippet.Country
+		}
+		subscribers, views, videos := "-", "-", "-"
+		if st := c.Statistics; st != nil {
+			subscribers = countString(!st.HiddenSubscriberCount, st.SubscriberCount)
+			views, videos = countString(true, st.ViewCount), countString(true, st.VideoCount)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", c.Id, truncate(title, maxTitleWidth), subscribers, views, videos, country)
+	}
+	return tw.Flush()
+}
+
+// printTags writes tag statistics to w in format.
+func printTags(w io.Writer, format string, stats []alaitube.TagStat) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(w, stats)
+	case outputCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"tag", "videos", "views", "score"})
+		for _, s := range stats {
+			cw.Write([]string{s.Tag, strconv.Itoa(s.Count), strconv.FormatInt(s.Views, 10), strconv.FormatFloat(s.Score, 'f', 2, 64)})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TAG\tVIDEOS\tVIEWS\tSCORE")
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\n", truncate(s.Tag, maxTitleWidth), s.Count, s.Views, s.Score)
+	}
+	return tw.Flush()
+}
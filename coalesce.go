@@ -0,0 +1,17 @@
+package alaitube
+
+import "context"
+
+// coalesce runs fetch once per region and key at a time: concurrent callers missing the cache
+// for the same key share the in-flight result instead of issuing duplicate API calls.
+// The shared fetch runs with the context of the caller that started it; a waiting caller whose
+// own context ends stops waiting and gets ctx.Err().
+func (yt *YoutubeApi) coalesce(ctx context.Context, region CacheRegion, key string, fetch func() (interface{}, error)) (interface{}, error) {
+	ch := yt.flights.DoChan(string(region)+":"+key, fetch)
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
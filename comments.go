@@ -0,0 +1,313 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const ListCommentThreads = "https://www.googleapis.com/youtube/v3/commentThreads?part=%s&videoId=%s&maxResults=100&order=%s&textFormat=plainText&key=%s%s"
+const ListComments = "https://www.googleapis.com/youtube/v3/comments?part=snippet&parentId=%s&maxResults=100&textFormat=plainText&key=%s%s"
+const InsertCommentThread = "https://www.googleapis.com/youtube/v3/commentThreads?part=snippet&key=%s"
+const InsertComment = "https://www.googleapis.com/youtube/v3/comments?part=snippet&key=%s"
+const MarkCommentsAsSpam = "https://www.googleapis.com/youtube/v3/comments/markAsSpam?id=%s&key=%s"
+const SetCommentsModerationStatus = "https://www.googleapis.com/youtube/v3/comments/setModerationStatus?id=%s&moderationStatus=%s&banAuthor=%t&key=%s"
+
+// Comment thread orderings accepted by CommentThreadOptions.Order.
+const (
+	CommentOrderTime      = "time"
+	CommentOrderRelevance = "relevance"
+)
+
+// Moderation statuses accepted by SetCommentModerationStatus.
+const (
+	ModerationHeldForReview = "heldForReview"
+	ModerationPublished     = "published"
+	ModerationRejected      = "rejected"
+)
+
+// DefaultMaxCommentThreads is the number of threads fetched when CommentThreadOptions.MaxResults is not set.
+const DefaultMaxCommentThreads = 100
+
+// CommentThreadOptions controls how GetCommentThreads pages through a video's comments.
+type CommentThreadOptions struct {
+	// Order is CommentOrderTime (the default) or CommentOrderRelevance.
+	Order string
+	// MaxResults caps the number of threads returned across all pages.
+	MaxResults int
+	// IncludeReplies fetches every reply of each thread, not only the few YouTube embeds in the thread.
+	IncludeReplies bool
+}
+
+// CommentThreadResults contains the comment threads retrieved for a video.
+type CommentThreadResults struct {
+	Items         []*CommentThread `bson:"items,omitempty" json:"items,omitempty"`
+	NextPageToken string           `bson:"nextPageToken,omitempty" json:"nextPageToken,omitempty"`
+}
+
+// CommentThread is a top-level comment together with its replies.
+type CommentThread struct {
+	Id      string `bson:"id,omitempty" json:"id,omitempty"`
+	Snippet *struct {
+		ChannelId       string   `bson:"channelId,omitempty" json:"channelId,omitempty"`
+		VideoId         string   `bson:"videoId,omitempty" json:"videoId,omitempty"`
+		TopLevelComment *Comment `bson:"topLevelComment,omitempty" json:"topLevelComment,omitempty"`
+		CanReply        bool     `bson:"canReply,omitempty" json:"canReply,omitempty"`
+		TotalReplyCount int      `bson:"totalReplyCount,omitempty" json:"totalReplyCount,omitempty"`
+		IsPublic        bool     `bson:"isPublic,omitempty" json:"isPublic,omitempty"`
+	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
+	Replies *struct {
+		Comments []*Comment `bson:"comments,omitempty" json:"comments,omitempty"`
+	} `bson:"replies,omitempty" json:"replies,omitempty"`
+}
+
+// Comment is a single YouTube comment or reply.
+type Comment struct {
+	Id      string `bson:"id,omitempty" json:"id,omitempty"`
+	Snippet *struct {
+		AuthorDisplayName     string `bson:"authorDisplayName,omitempty" json:"authorDisplayName,omitempty"`
+		AuthorProfileImageUrl string `bson:"authorProfileImageUrl,omitempty" json:"authorProfileImageUrl,omitempty"`
+		AuthorChannelUrl      string `bson:"authorChannelUrl,omitempty" json:"authorChannelUrl,omitempty"`
+		AuthorChannelId       *struct {
+			Value string `bson:"value,omitempty" json:"value,omitempty"`
+		} `bson:"authorChannelId,omitempty" json:"authorChannelId,omitempty"`
+		VideoId      string    `bson:"videoId,omitempty" json:"videoId,omitempty"`
+		TextDisplay  string    `bson:"textDisplay,omitempty" json:"textDisplay,omitempty"`
+		TextOriginal string    `bson:"textOriginal,omitempty" json:"textOriginal,omitempty"`
+		ParentId     string    `bson:"parentId,omitempty" json:"parentId,omitempty"`
+		LikeCount    int       `bson:"likeCount,omitempty" json:"likeCount,omitempty"`
+		PublishedAt  time.Time `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+		UpdatedAt    time.Time `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
+}
+
+// commentListResults is the response of the comments endpoint.
+type commentListResults struct {
+	Items         []*Comment `json:"items,omitempty"`
+	NextPageToken string     `json:"nextPageToken,omitempty"`
+}
+
+// GetCommentThreads retrieves the comment threads of a video, paging until opts.MaxResults threads are collected.
+// Results are cached per video and option combination.
+func (yt *YoutubeApi) GetCommentThreads(videoId string, opts CommentThreadOptions) (*CommentThreadResults, error) {
+	return yt.GetCommentThreadsContext(context.Background(), videoId, opts)
+}
+
+// GetCommentThreadsContext is like GetCommentThreads but uses ctx for every page and reply request.
+func (yt *YoutubeApi) GetCommentThreadsContext(ctx context.Context, videoId string, opts CommentThreadOptions) (*CommentThreadResults, error) {
+	if opts.Order == "" {
+		opts.Order = CommentOrderTime
+	}
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = DefaultMaxCommentThreads
+	}
+	cacheKey := CacheKey("commentThreads", url.Values{
+		"videoId":        {videoId},
+		"order":          {opts.Order},
+		"maxResults":     {strconv.Itoa(opts.MaxResults)},
+		"includeReplies": {strconv.FormatBool(opts.IncludeReplies)},
+	})
+	if v := yt.Cache.GetCommentThreads(cacheKey); v != nil {
+		return v, nil
+	}
+
+	// Missing videos are recorded once, whatever the options they were looked up with.
+	notFoundKey := CacheKey("commentThreads", url.Values{"videoId": {videoId}})
+	if err := yt.cachedNotFound(RegionComments, notFoundKey, videoId); err != nil {
+		return nil, err
+	}
+
+	v, err := yt.coalesce(ctx, RegionComments, cacheKey, func() (interface{}, error) {
+		results, err := yt.fetchCommentThreads(ctx, videoId, opts, cacheKey)
+		yt.rememberNotFound(RegionComments, notFoundKey, err)
+		return results, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*CommentThreadResults), nil
+}
+
+// fetchCommentThreads pages through the comment threads of a video and caches the result.
+func (yt *YoutubeApi) fetchCommentThreads(ctx context.Context, videoId string, opts CommentThreadOptions, cacheKey string) (*CommentThreadResults, error) {
+	part := "snippet"
+	if opts.IncludeReplies {
+		part = "snippet,replies"
+	}
+
+	results := &CommentThreadResults{}
+	nextPage := ""
+	for len(results.Items) < opts.MaxResults {
+		nextPageStr := ""
+		if nextPage != "" {
+			nextPageStr = fmt.Sprintf("&pageToken=%v", nextPage)
+		}
+		body, err := yt.httpGetRequest(ctx, fmt.Sprintf(ListCommentThreads, part, videoId, opts.Order, yt.apiKey, nextPageStr))
+		if err != nil {
+			return nil, err
+		}
+		page := &CommentThreadResults{}
+		if err := json.Unmarshal(body, page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal comment threads: %w", err)
+		}
+		results.Items = append(results.Items, page.Items...)
+
+		nextPage = page.NextPageToken
+		if nextPage == "" {
+			break
+		}
+	}
+	if len(results.Items) > opts.MaxResults {
+		results.Items = results.Items[:opts.MaxResults]
+	}
+	results.NextPageToken = nextPage
+
+	if opts.IncludeReplies {
+		for _, thread := range results.Items {
+			if err := yt.expandReplies(ctx, thread); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	yt.Cache.SetCommentThreads(cacheKey, results)
+
+	return results, nil
+}
+
+// expandReplies replaces the partial reply list embedded in a thread with the full list from the comments endpoint.
+func (yt *YoutubeApi) expandReplies(ctx context.Context, thread *CommentThread) error {
+	if thread.Snippet == nil {
+		return nil
+	}
+	embedded := 0
+	if thread.Replies != nil {
+		embedded = len(thread.Replies.Comments)
+	}
+	if thread.Snippet.TotalReplyCount <= embedded {
+		return nil
+	}
+
+	var replies []*Comment
+	nextPage := ""
+	for {
+		nextPageStr := ""
+		if nextPage != "" {
+			nextPageStr = fmt.Sprintf("&pageToken=%v", nextPage)
+		}
+		body, err := yt.httpGetRequest(ctx, fmt.Sprintf(ListComments, thread.Id, yt.apiKey, nextPageStr))
+		if err != nil {
+			return err
+		}
+		page := &commentListResults{}
+		if err := json.Unmarshal(body, page); err != nil {
+			return fmt.Errorf("failed to unmarshal comment replies: %w", err)
+		}
+		replies = append(replies, page.Items...)
+
+		nextPage = page.NextPageToken
+		if nextPage == "" {
+			break
+		}
+	}
+
+	if thread.Replies == nil {
+		thread.Replies = &struct {
+			Comments []*Comment `bson:"comments,omitempty" json:"comments,omitempty"`
+		}{}
+	}
+	thread.Replies.Comments = replies
+	return nil
+}
+
+// PostComment posts a new top-level comment on a video as the authenticated user and returns the
+// created thread. Requires OAuth2 credentials. Cached comment threads of the video are not
+// refreshed until they expire.
+func (yt *YoutubeApi) PostComment(videoId, text string) (*CommentThread, error) {
+	return yt.PostCommentContext(context.Background(), videoId, text)
+}
+
+// PostCommentContext is like PostComment but uses ctx for the underlying API request.
+func (yt *YoutubeApi) PostCommentContext(ctx context.Context, videoId, text string) (*CommentThread, error) {
+	body := map[string]interface{}{
+		"snippet": map[string]interface{}{
+			"videoId": videoId,
+			"topLevelComment": map[string]interface{}{
+				"snippet": map[string]interface{}{
+					"textOriginal": text,
+				},
+			},
+		},
+	}
+	thread := &CommentThread{}
+	if err := yt.userJSONRequest(ctx, http.MethodPost, fmt.Sprintf(InsertCommentThread, yt.apiKey), body, thread); err != nil {
+		return nil, err
+	}
+	return thread, nil
+}
+
+// ReplyToComment replies to a top-level comment as the authenticated user. Requires OAuth2 credentials.
+func (yt *YoutubeApi) ReplyToComment(parentId, text string) (*Comment, error) {
+	return yt.ReplyToCommentContext(context.Background(), parentId, text)
+}
+
+// ReplyToCommentContext is like ReplyToComment but uses ctx for the underlying API request.
+func (yt *YoutubeApi) ReplyToCommentContext(ctx context.Context, parentId, text string) (*Comment, error) {
+	body := map[string]interface{}{
+		"snippet": map[string]interface{}{
+			"parentId":     parentId,
+			"textOriginal": text,
+		},
+	}
+	comment := &Comment{}
+	if err := yt.userJSONRequest(ctx, http.MethodPost, fmt.Sprintf(InsertComment, yt.apiKey), body, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// MarkCommentsAsSpam flags comments as spam on behalf of the authenticated user. Requires OAuth2 credentials.
+func (yt *YoutubeApi) MarkCommentsAsSpam(commentIds ...string) error {
+	return yt.MarkCommentsAsSpamContext(context.Background(), commentIds...)
+}
+
+// MarkCommentsAsSpamContext is like MarkCommentsAsSpam but uses ctx for the underlying API requests.
+func (yt *YoutubeApi) MarkCommentsAsSpamContext(ctx context.Context, commentIds ...string) error {
+	for _, batch := range batchIteration(uniqueIds(commentIds)) {
+		apiUrl := fmt.Sprintf(MarkCommentsAsSpam, url.QueryEscape(batch), yt.apiKey)
+		if err := yt.userJSONRequest(ctx, http.MethodPost, apiUrl, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetCommentModerationStatus publishes, holds for review, or rejects comments on the authenticated
+// user's channel or videos. banAuthor additionally blocks the authors of rejected comments from
+// commenting again and is only accepted with ModerationRejected. Requires OAuth2 credentials.
+func (yt *YoutubeApi) SetCommentModerationStatus(commentIds []string, status string, banAuthor bool) error {
+	return yt.SetCommentModerationStatusContext(context.Background(), commentIds, status, banAuthor)
+}
+
+// SetCommentModerationStatusContext is like SetCommentModerationStatus but uses ctx for the underlying API requests.
+func (yt *YoutubeApi) SetCommentModerationStatusContext(ctx context.Context, commentIds []string, status string, banAuthor bool) error {
+	switch status {
+	case ModerationHeldForReview, ModerationPublished, ModerationRejected:
+	default:
+		return fmt.Errorf("invalid moderation status %q", status)
+	}
+	if banAuthor && status != ModerationRejected {
+		return fmt.Errorf("banAuthor requires moderation status %q", ModerationRejected)
+	}
+	for _, batch := range batchIteration(uniqueIds(commentIds)) {
+		apiUrl := fmt.Sprintf(SetCommentsModerationStatus, url.QueryEscape(batch), status, banAuthor, yt.apiKey)
+		if err := yt.userJSONRequest(ctx, http.MethodPost, apiUrl, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package alaitube
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultUserAgent identifies the client to YouTube. Google only serves gzip-compressed
+// responses to user agents containing "gzip", which is appended while compression is enabled.
+const DefaultUserAgent = "alaitube"
+
+// WithCompression sets whether gzip-compressed responses are requested. It is enabled by default;
+// disabling it keeps response bodies readable on the wire when debugging with a proxy.
+func WithCompression(enabled bool) Option {
+	return func(yt *YoutubeApi) {
+		yt.compression = enabled
+	}
+}
+
+// WithUserAgent sets the User-Agent sent with every request. " (gzip)" is appended when
+// compression is enabled and the agent doesn't mention gzip already.
+func WithUserAgent(userAgent string) Option {
+	return func(yt *YoutubeApi) {
+		yt.userAgent = userAgent
+	}
+}
+
+// setEncodingHeaders sets the User-Agent and the accepted encoding. Setting Accept-Encoding
+// ourselves disables the transport's transparent decompression, which readBody takes over,
+// and lets disabled compression really ask for an identity response.
+func (yt *YoutubeApi) setEncodingHeaders(req *http.Request) {
+	userAgent := yt.userAgent
+	if !yt.compression {
+		req.Header.Set("Accept-Encoding", "identity")
+		req.Header.Set("User-Agent", userAgent)
+		return
+	}
+	if !strings.Contains(userAgent, "gzip") {
+		userAgent += " (gzip)"
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("User-Agent", userAgent)
+}
+
+// readBody reads the response body, decompressing it when the server sent it gzip-encoded.
+func readBody(resp *http.Response) ([]byte, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return io.ReadAll(resp.Body)
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening gzip body: %w", err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
@@ -0,0 +1,463 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultCrawlWorkers is the number of jobs a Crawler processes concurrently by default.
+const DefaultCrawlWorkers = 4
+
+// ErrCrawlBudgetExhausted is returned by Crawler.Run when the quota budget is spent before every
+// job is done. The remaining jobs resume from their saved page on the next run.
+var ErrCrawlBudgetExhausted = errors.New("crawl quota budget exhausted")
+
+// CrawlKind is the kind of resource a CrawlJob walks.
+type CrawlKind string
+
+const (
+	// CrawlQuery walks the search results of a query, like FindTags.
+	CrawlQuery CrawlKind = "query"
+	// CrawlChannel walks the uploads of a channel.
+	CrawlChannel CrawlKind = "channel"
+	// CrawlPlaylist walks the videos of a playlist.
+	CrawlPlaylist CrawlKind = "playlist"
+)
+
+// CrawlJob is a query, channel, or playlist to crawl.
+type CrawlJob struct {
+	Kind CrawlKind `json:"kind"`
+	// Target is the query, channel ID, or playlist ID.
+	Target string `json:"target"`
+	// MaxPages bounds the pages of up to 50 videos crawled. Zero crawls every page.
+	MaxPages int `json:"maxPages,omitempty"`
+}
+
+// Key identifies the job in the crawl state.
+func (j CrawlJob) Key() string {
+	return string(j.Kind) + ":" + j.Target
+}
+
+// pageCost estimates the quota units a page of the job costs: the page itself and the details of
+// its videos.
+func (j CrawlJob) pageCost() int {
+	if j.Kind == CrawlQuery {
+		return QuotaCost(http.MethodGet, "search") + QuotaCost(http.MethodGet, "videos")
+	}
+	return QuotaCost(http.MethodGet, "playlistItems") + QuotaCost(http.MethodGet, "videos")
+}
+
+// CrawlProgress is the progress of a single job.
+type CrawlProgress struct {
+	Job CrawlJob `json:"job"`
+	// Pages is the number of pages crawled, and PageToken the token of the next one.
+	Pages     int    `json:"pages"`
+	PageToken string `json:"pageToken,omitempty"`
+	Videos    int    `json:"videos"`
+	Done      bool   `json:"done"`
+	// Error is the last error of the job. Failed jobs are retried from their next page on the next run.
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CrawlState is the persisted state of a Crawler: every enqueued job, in order, with its progress.
+type CrawlState struct {
+	Jobs []*CrawlProgress `json:"jobs"`
+}
+
+// CrawlStore persists the state of a Crawler, so a crawl can resume after a crash.
+type CrawlStore interface {
+	// LoadCrawl returns the saved state, or an empty state when none was saved.
+	LoadCrawl() (*CrawlState, error)
+	SaveCrawl(state *CrawlState) error
+}
+
+// MemoryCrawlStore keeps the crawl state in memory. It is the default store and does not survive restarts.
+type MemoryCrawlStore struct {
+	data []byte
+	sync.Mutex
+}
+
+// NewMemoryCrawlStore creates an empty MemoryCrawlStore.
+func NewMemoryCrawlStore() *MemoryCrawlStore {
+	return &MemoryCrawlStore{}
+}
+
+func (s *MemoryCrawlStore) LoadCrawl() (*CrawlState, error) {
+	s.Lock()
+	defer s.Unlock()
+	state := &CrawlState{}
+	if s.data == nil {
+		return state, nil
+	}
+	// Stored encoded, so callers never share the state with the store.
+	if err := json.Unmarshal(s.data, state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal crawl state: %w", err)
+	}
+	return state, nil
+}
+
+func (s *MemoryCrawlStore) SaveCrawl(state *CrawlState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal crawl state: %w", err)
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.data = data
+	return nil
+}
+
+// FileCrawlStore keeps the crawl state in a single JSON file.
+type FileCrawlStore struct {
+	path string
+	sync.Mutex
+}
+
+// NewFileCrawlStore creates a FileCrawlStore backed by path. The file is created on the first save.
+func NewFileCrawlStore(path string) *FileCrawlStore {
+	return &FileCrawlStore{path: path}
+}
+
+func (s *FileCrawlStore) LoadCrawl() (*CrawlState, error) {
+	s.Lock()
+	defer s.Unlock()
+	state := &CrawlState{}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading crawl state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal crawl state: %w", err)
+	}
+	return state, nil
+}
+
+func (s *FileCrawlStore) SaveCrawl(state *CrawlState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal crawl state: %w", err)
+	}
+	s.Lock()
+	defer s.Unlock()
+	// Write through a temporary file so a crash never leaves a truncated state behind.
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed writing crawl state: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Crawler processes a queue of queries, channels, and playlists with a bounded pool of workers,
+// handing every page of videos to a callback. Progress is saved to a CrawlStore after each page,
+// so a crawl interrupted by a crash or a spent quota budget resumes where it stopped. Requests
+// go through the client, so its rate limits, retries, and cache apply.
+type Crawler struct {
+	yt      *YoutubeApi
+	handler func(context.Context, CrawlJob, *VideoResults) error
+	workers int
+	store   CrawlStore
+	budget  int
+	search  SearchOptions
+	webhook *WebhookSink
+
+	mu    sync.Mutex
+	state *CrawlState
+	spent int
+}
+
+// CrawlerOption configures a Crawler created with NewCrawler.
+type CrawlerOption func(*Crawler)
+
+// WithCrawlWorkers sets the number of jobs processed concurrently.
+func WithCrawlWorkers(n int) CrawlerOption {
+	return func(c *Crawler) {
+		if n > 0 {
+			c.workers = n
+		}
+	}
+}
+
+// WithCrawlStore sets the store the crawl state is persisted in.
+// A nil store is ignored and the default MemoryCrawlStore is kept.
+func WithCrawlStore(store CrawlStore) CrawlerOption {
+	return func(c *Crawler) {
+		if store != nil {
+			c.store = store
+		}
+	}
+}
+
+// WithCrawlQuotaBudget bounds the quota units a single Run may spend, estimated with QuotaCost.
+// Zero, the default, doesn't bound the crawl.
+func WithCrawlQuotaBudget(units int) CrawlerOption {
+	return func(c *Crawler) {
+		c.budget = units
+	}
+}
+
+// WithCrawlSearchOptions sets the options of the searches made for CrawlQuery jobs.
+func WithCrawlSearchOptions(opts SearchOptions) CrawlerOption {
+	return func(c *Crawler) {
+		c.search = opts
+	}
+}
+
+// WithCrawlWebhook posts a WebhookCrawlJobCompleted event to sink whenever a job is done.
+// Deliveries that fail after the sink's retries are logged and don't fail the job.
+func WithCrawlWebhook(sink *WebhookSink) CrawlerOption {
+	return func(c *Crawler) {
+		c.webhook = sink
+	}
+}
+
+// NewCrawler creates a Crawler calling handler with every page of videos crawled. When handler
+// returns an error, the job stops and the page is crawled again on the next run.
+func (yt *YoutubeApi) NewCrawler(handler func(context.Context, CrawlJob, *VideoResults) error, opts ...CrawlerOption) *Crawler {
+	c := &Crawler{
+		yt:      yt,
+		handler: handler,
+		workers: DefaultCrawlWorkers,
+		store:   NewMemoryCrawlStore(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// load reads the crawl state from the store, once. c.mu must be held.
+func (c *Crawler) load() error {
+	if c.state != nil {
+		return nil
+	}
+	state, err := c.store.LoadCrawl()
+	if err != nil {
+		return err
+	}
+	c.state = state
+	return nil
+}
+
+// Enqueue adds jobs to the queue and saves it. Jobs already in the queue, done or not, are skipped.
+func (c *Crawler) Enqueue(jobs ...CrawlJob) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return err
+	}
+	queued := make(map[string]bool, len(c.state.Jobs))
+	for _, p := range c.state.Jobs {
+		queued[p.Job.Key()] = true
+	}
+	for _, job := range jobs {
+		if queued[job.Key()] {
+			continue
+		}
+		queued[job.Key()] = true
+		c.state.Jobs = append(c.state.Jobs, &CrawlProgress{Job: job, UpdatedAt: time.Now()})
+	}
+	return c.store.SaveCrawl(c.state)
+}
+
+// Progress returns a copy of the progress of every job, in queue order.
+func (c *Crawler) Progress() ([]CrawlProgress, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	progress := make([]CrawlProgress, len(c.state.Jobs))
+	for i, p := range c.state.Jobs {
+		progress[i] = *p
+	}
+	return progress, nil
+}
+
+// Run crawls every job that isn't done, resuming each from its saved page, and returns once the
+// queue is drained. It returns ctx.Err() when ctx is cancelled, ErrCrawlBudgetExhausted when the
+// quota budget is spent, and otherwise the errors of the failed jobs joined together.
+func (c *Crawler) Run(ctx context.Context) (err error) {
+	ctx, span := c.yt.startOperation(ctx, "Crawler.Run")
+	defer func() { endSpan(span, err) }()
+
+	c.mu.Lock()
+	if err := c.load(); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	var pending []*CrawlProgress
+	for _, p := range c.state.Jobs {
+		if !p.Done {
+			pending = append(pending, p)
+		}
+	}
+	c.spent = 0
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	queue := make(chan *CrawlProgress)
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		jobErrs  []error
+		exceeded bool
+	)
+	for i := 0; i < min(c.workers, len(pending)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range queue {
+				err := c.crawl(ctx, p)
+				switch {
+				case err == nil || ctx.Err() != nil:
+				case errors.Is(err, ErrCrawlBudgetExhausted):
+					errMu.Lock()
+					exceeded = true
+					errMu.Unlock()
+					cancel()
+				default:
+					errMu.Lock()
+					jobErrs = append(jobErrs, fmt.Errorf("crawl %s: %w", p.Job.Key(), err))
+					errMu.Unlock()
+				}
+			}
+		}()
+	}
+feed:
+	for _, p := range pending {
+		select {
+		case queue <- p:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	switch {
+	case exceeded:
+		return ErrCrawlBudgetExhausted
+	case ctx.Err() != nil && len(jobErrs) == 0:
+		return ctx.Err()
+	}
+	return errors.Join(jobErrs...)
+}
+
+// crawl processes the pages of a job until it is done or fails.
+func (c *Crawler) crawl(ctx context.Context, p *CrawlProgress) error {
+	c.mu.Lock()
+	job, pages, token := p.Job, p.Pages, p.PageToken
+	c.mu.Unlock()
+
+	for job.MaxPages <= 0 || pages < job.MaxPages {
+		if err := c.spend(job.pageCost()); err != nil {
+			return err
+		}
+		results, next, err := c.crawlPage(withSpanAttributes(ctx, AttrPage.Int(pages)), job, pages, token)
+		if err == nil {
+			err = c.handler(ctx, job, results)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				c.save(p, func() { p.Error = err.Error() })
+			}
+			return err
+		}
+		pages, token = pages+1, next
+		done := next == "" || (job.MaxPages > 0 && pages >= job.MaxPages)
+		if err := c.save(p, func() {
+			p.Pages, p.PageToken, p.Done, p.Error = pages, next, done, ""
+			p.Videos += len(results.Items)
+		}); err != nil {
+			return err
+		}
+		if done {
+			c.notify(ctx, p)
+			return nil
+		}
+	}
+	if err := c.save(p, func() { p.Done = true }); err != nil {
+		return err
+	}
+	c.notify(ctx, p)
+	return nil
+}
+
+// notify posts the completion of a job to the webhook, if any.
+func (c *Crawler) notify(ctx context.Context, p *CrawlProgress) {
+	if c.webhook == nil {
+		return
+	}
+	c.mu.Lock()
+	progress := *p
+	c.mu.Unlock()
+	if err := c.webhook.Send(ctx, WebhookCrawlJobCompleted, progress); err != nil {
+		c.yt.logger.Error("crawl webhook failed", Field{"job", progress.Job.Key()}, Field{"error", err})
+	}
+}
+
+// spend charges cost to the quota budget, failing when the budget can't afford it.
+func (c *Crawler) spend(cost int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.budget > 0 && c.spent+cost > c.budget {
+		return ErrCrawlBudgetExhausted
+	}
+	c.spent += cost
+	return nil
+}
+
+// save applies update to the progress of a job and persists the state.
+func (c *Crawler) save(p *CrawlProgress, update func()) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	update()
+	p.UpdatedAt = time.Now()
+	return c.store.SaveCrawl(c.state)
+}
+
+// crawlPage fetches a single page of a job, returning its videos and the token of the next page.
+func (c *Crawler) crawlPage(ctx context.Context, job CrawlJob, page int, token string) (*VideoResults, string, error) {
+	switch job.Kind {
+	case CrawlQuery:
+		res, err := c.yt.searchPage(ctx, job.Target, token, c.search)
+		if err != nil {
+			return nil, "", err
+		}
+		vidIds := make(map[string]vidSnippetInfo)
+		ids := collectSearchResults(res, nil, vidIds)
+		results := &VideoResults{}
+		if len(ids) > 0 {
+			details, err := c.yt.GetVideosWithParts(ctx, ids, c.search.Parts)
+			if err != nil {
+				return nil, "", err
+			}
+			results.Items = filterSearchVideos(details.Items, vidIds)
+		}
+		return results, res.NextPageToken, nil
+	case CrawlChannel, CrawlPlaylist:
+		pager := &PlaylistPager{yt: c.yt, nextPage: token, page: page}
+		if job.Kind == CrawlChannel {
+			pager.channelId = job.Target
+		} else {
+			pager.playlistId = job.Target
+		}
+		results, err := pager.NextContext(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		return results, pager.nextPage, nil
+	}
+	return nil, "", fmt.Errorf("unknown crawl kind %q", job.Kind)
+}
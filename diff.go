@@ -0,0 +1,180 @@
+package alaitube
+
+import (
+	"context"
+	"time"
+)
+
+// TitleChange is a video whose title changed between two snapshots.
+type TitleChange struct {
+	VideoId  string `bson:"videoId" json:"videoId"`
+	OldTitle string `bson:"oldTitle" json:"oldTitle"`
+	NewTitle string `bson:"newTitle" json:"newTitle"`
+}
+
+// StatsDelta is the change of the counts of a video between two snapshots.
+type StatsDelta struct {
+	VideoId  string `bson:"videoId" json:"videoId"`
+	Views    int64  `bson:"views" json:"views"`
+	Likes    int64  `bson:"likes" json:"likes"`
+	Comments int64  `bson:"comments" json:"comments"`
+}
+
+// VideoDiff lists the changes between two snapshots of a list of videos, such as a playlist or a
+// channel's uploads. Added videos are in the order of the new snapshot, and the other changes in
+// the order of the old one.
+type VideoDiff struct {
+	Added    []*Video      `bson:"added,omitempty" json:"added,omitempty"`
+	Removed  []*Video      `bson:"removed,omitempty" json:"removed,omitempty"`
+	Retitled []TitleChange `bson:"retitled,omitempty" json:"retitled,omitempty"`
+	// Stats holds the videos in both snapshots whose view, like, or comment count changed.
+	Stats []StatsDelta `bson:"stats,omitempty" json:"stats,omitempty"`
+}
+
+// Empty reports whether nothing changed.
+func (d *VideoDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Retitled) == 0 && len(d.Stats) == 0
+}
+
+// DiffPlaylist compares two snapshots of a playlist, as returned by GetPlaylistVideos or
+// GetChannelPlaylist, so sync jobs can emit change events instead of full dumps. Either snapshot
+// may be nil, which counts as empty.
+func DiffPlaylist(old, new *VideoResults) *VideoDiff {
+	diff := &VideoDiff{}
+	oldVideos, newVideos := videosById(old), videosById(new)
+	if new != nil {
+		for _, v := range new.Items {
+			if _, ok := oldVideos[v.Id]; !ok {
+				diff.Added = append(diff.Added, v)
+			}
+		}
+	}
+	if old == nil {
+		return diff
+	}
+	for _, before := range old.Items {
+		after, ok := newVideos[before.Id]
+		if !ok {
+			diff.Removed = append(diff.Removed, before)
+			continue
+		}
+		if oldTitle, newTitle := videoTitle(before), videoTitle(after); oldTitle != newTitle {
+			diff.Retitled = append(diff.Retitled, TitleChange{VideoId: before.Id, OldTitle: oldTitle, NewTitle: newTitle})
+		}
+		if delta := videoStatsDelta(before, after); delta != (StatsDelta{VideoId: before.Id}) {
+			diff.Stats = append(diff.Stats, delta)
+		}
+	}
+	return diff
+}
+
+// videosById indexes the videos of results by ID.
+func videosById(results *VideoResults) map[string]*Video {
+	byId := make(map[string]*Video)
+	if results != nil {
+		for _, v := range results.Items {
+			byId[v.Id] = v
+		}
+	}
+	return byId
+}
+
+// videoTitle returns the title of v, or "" when its snippet wasn't fetched.
+func videoTitle(v *Video) string {
+	if v.Snippet == nil {
+		return ""
+	}
+	return v.Snippet.Title
+}
+
+// videoStatsDelta returns the change of the counts from before to after. Counts missing from
+// either snapshot are left unchanged.
+func videoStatsDelta(before, after *Video) StatsDelta {
+	delta := StatsDelta{VideoId: before.Id}
+	if before.Statistics == nil || after.Statistics == nil {
+		return delta
+	}
+	delta.Views = int64(after.Statistics.ViewCount - before.Statistics.ViewCount)
+	delta.Likes = int64(after.Statistics.LikeCount - before.Statistics.LikeCount)
+	delta.Comments = int64(after.Statistics.CommentCount - before.Statistics.CommentCount)
+	return delta
+}
+
+// ChannelSnapshot is the state of a channel and its recent uploads at a point in time, as taken
+// by SnapshotChannel.
+type ChannelSnapshot struct {
+	Channel *Item         `bson:"channel" json:"channel"`
+	Uploads *VideoResults `bson:"uploads" json:"uploads"`
+	At      time.Time     `bson:"at" json:"at"`
+}
+
+// ChannelDiff lists the changes between two snapshots of a channel.
+type ChannelDiff struct {
+	ChannelId   string        `bson:"channelId" json:"channelId"`
+	OldTitle    string        `bson:"oldTitle,omitempty" json:"oldTitle,omitempty"`
+	NewTitle    string        `bson:"newTitle,omitempty" json:"newTitle,omitempty"`
+	Elapsed     time.Duration `bson:"elapsed" json:"elapsed"`
+	Views       int64         `bson:"views" json:"views"`
+	Subscribers int64         `bson:"subscribers" json:"subscribers"`
+	Videos      int64         `bson:"videos" json:"videos"`
+	Uploads     *VideoDiff    `bson:"uploads" json:"uploads"`
+}
+
+// Retitled reports whether the channel's title changed.
+func (d *ChannelDiff) Retitled() bool {
+	return d.OldTitle != d.NewTitle
+}
+
+// DiffChannelSnapshots compares two snapshots of a channel. The uploads are compared like
+// DiffPlaylist does, so when the snapshots cover only the most recent uploads, old videos that
+// slid out of the newer snapshot are reported as removed.
+func DiffChannelSnapshots(old, new *ChannelSnapshot) *ChannelDiff {
+	diff := &ChannelDiff{Elapsed: new.At.Sub(old.At), Uploads: DiffPlaylist(old.Uploads, new.Uploads)}
+	if new.Channel != nil {
+		diff.ChannelId = new.Channel.Id
+		diff.NewTitle = channelTitle(new.Channel)
+	}
+	if old.Channel != nil {
+		diff.ChannelId = old.Channel.Id
+		diff.OldTitle = channelTitle(old.Channel)
+	}
+	if old.Channel != nil && new.Channel != nil && old.Channel.Statistics != nil && new.Channel.Statistics != nil {
+		before, after := old.Channel.Statistics, new.Channel.Statistics
+		diff.Views = int64(after.ViewCount - before.ViewCount)
+		diff.Subscribers = int64(after.SubscriberCount - before.SubscriberCount)
+		diff.Videos = int64(after.VideoCount - before.VideoCount)
+	}
+	return diff
+}
+
+// channelTitle returns the title of channel, or "" when its snippet wasn't fetched.
+func channelTitle(channel *Item) string {
+	if channel.Snippet == nil {
+		return ""
+	}
+	return channel.Snippet.Title
+}
+
+// SnapshotChannel takes a snapshot of a channel and its maxVideos most recent uploads, to be
+// compared with a later one by DiffChannelSnapshots. The channel and uploads are fetched through
+// the cache, so snapshots taken closer together than the cache TTL may be identical.
+func (yt *YoutubeApi) SnapshotChannel(channelId string, maxVideos int) (*ChannelSnapshot, error) {
+	return yt.SnapshotChannelContext(context.Background(), channelId, maxVideos)
+}
+
+// SnapshotChannelContext is like SnapshotChannel but uses ctx for every request.
+func (yt *YoutubeApi) SnapshotChannelContext(ctx context.Context, channelId string, maxVideos int) (_ *ChannelSnapshot, err error) {
+	ctx, span := yt.startOperation(ctx, "SnapshotChannel")
+	defer func() { endSpan(span, err) }()
+
+	cInfo, err := yt.GetChannelInfoContext(ctx, channelId)
+	if err != nil {
+		return nil, err
+	}
+	channel := cInfo.Items[0]
+	uploads, err := yt.GetChannelPlaylistContext(ctx, channel, maxVideos)
+	if err != nil {
+		return nil, err
+	}
+	return &ChannelSnapshot{Channel: channel, Uploads: uploads, At: time.Now()}, nil
+}
@@ -0,0 +1,257 @@
+package alaitube
+
+import (
+	"context"
+	"strings"
+)
+
+// Defaults of Discover.
+const (
+	DefaultDiscoveryDepth     = 2
+	DefaultDiscoveryBreadth   = 5
+	DefaultDiscoveryMaxVideos = 200
+)
+
+// discoveryQueryTags bounds how many tags are added to the title when searching for related videos.
+const discoveryQueryTags = 3
+
+// DiscoveryGraph is the graph of videos and channels reached by Discover. Videos are keyed by ID,
+// and Related is the adjacency list of the graph: the IDs of the videos found from each video, in
+// search order. Every edge points to a video in Videos.
+type DiscoveryGraph struct {
+	Videos  map[string]*Video   `bson:"videos" json:"videos"`
+	Related map[string][]string `bson:"related" json:"related"`
+	// Depth is the number of hops from the nearest seed to each video; seeds have depth 0.
+	Depth map[string]int `bson:"depth" json:"depth"`
+	// Channels maps each channel ID to the IDs of its videos in the graph.
+	Channels map[string][]string `bson:"channels" json:"channels"`
+}
+
+// newDiscoveryGraph creates an empty DiscoveryGraph.
+func newDiscoveryGraph() *DiscoveryGraph {
+	return &DiscoveryGraph{
+		Videos:   make(map[string]*Video),
+		Related:  make(map[string][]string),
+		Depth:    make(map[string]int),
+		Channels: make(map[string][]string),
+	}
+}
+
+// add records video at the given depth, unless it is already in the graph.
+func (g *DiscoveryGraph) add(video *Video, depth int) bool {
+	if _, ok := g.Videos[video.Id]; ok {
+		return false
+	}
+	g.Videos[video.Id] = video
+	g.Depth[video.Id] = depth
+	if video.Snippet != nil && video.Snippet.ChannelId != "" {
+		g.Channels[video.Snippet.ChannelId] = append(g.Channels[video.Snippet.ChannelId], video.Id)
+	}
+	return true
+}
+
+// Neighbors returns the videos found from videoId.
+func (g *DiscoveryGraph) Neighbors(videoId string) []*Video {
+	var videos []*Video
+	for _, id := range g.Related[videoId] {
+		videos = append(videos, g.Videos[id])
+	}
+	return videos
+}
+
+// ChannelLinks returns the channel-level adjacency of the graph: for each channel, how many edges
+// lead from its videos to videos of every other channel.
+func (g *DiscoveryGraph) ChannelLinks() map[string]map[string]int {
+	links := make(map[string]map[string]int)
+	for from, related := range g.Related {
+		fromChannel := videoChannelId(g.Videos[from])
+		for _, to := range related {
+			toChannel := videoChannelId(g.Videos[to])
+			if fromChannel == "" || toChannel == "" || fromChannel == toChannel {
+				continue
+			}
+			if links[fromChannel] == nil {
+				links[fromChannel] = make(map[string]int)
+			}
+			links[fromChannel][toChannel]++
+		}
+	}
+	return links
+}
+
+// videoChannelId returns the channel of video, or "" when its snippet wasn't fetched.
+func videoChannelId(video *Video) string {
+	if video == nil || video.Snippet == nil {
+		return ""
+	}
+	return video.Snippet.ChannelId
+}
+
+// discovery holds the settings of a Discover call.
+type discovery struct {
+	depth     int
+	breadth   int
+	maxVideos int
+	search    SearchOptions
+}
+
+// DiscoveryOption configures Discover.
+type DiscoveryOption func(*discovery)
+
+// WithDiscoveryDepth sets how many hops away from the seeds Discover walks.
+func WithDiscoveryDepth(depth int) DiscoveryOption {
+	return func(d *discovery) {
+		d.depth = depth
+	}
+}
+
+// WithDiscoveryBreadth sets how many related videos Discover follows from each video.
+func WithDiscoveryBreadth(breadth int) DiscoveryOption {
+	return func(d *discovery) {
+		d.breadth = breadth
+	}
+}
+
+// WithDiscoveryMaxVideos bounds the number of videos in the graph, seeds included.
+func WithDiscoveryMaxVideos(max int) DiscoveryOption {
+	return func(d *discovery) {
+		d.maxVideos = max
+	}
+}
+
+// WithDiscoverySearchOptions sets the options of the searches for related videos, such as a
+// region or a relevance language. Results are ordered by relevance unless opts.Order is set.
+func WithDiscoverySearchOptions(opts SearchOptions) DiscoveryOption {
+	return func(d *discovery) {
+		d.search = opts
+	}
+}
+
+// Discover walks the videos related to seedIds and returns the resulting graph. YouTube retired
+// the relatedToVideoId search parameter, so the videos related to a video are found by searching
+// for its title and first tags. The walk is breadth-first, follows up to DefaultDiscoveryBreadth
+// videos from each video, stops DefaultDiscoveryDepth hops from the seeds, and keeps at most
+// DefaultDiscoveryMaxVideos videos; each can be changed with a DiscoveryOption. Every video
+// searched from costs QuotaCostSearch units.
+func (yt *YoutubeApi) Discover(seedIds []string, opts ...DiscoveryOption) (*DiscoveryGraph, error) {
+	return yt.DiscoverContext(context.Background(), seedIds, opts...)
+}
+
+// DiscoverContext is like Discover but uses ctx for every request. When a request fails, the
+// graph built so far is returned along with the error.
+func (yt *YoutubeApi) DiscoverContext(ctx context.Context, seedIds []string, opts ...DiscoveryOption) (_ *DiscoveryGraph, err error) {
+	ctx, span := yt.startOperation(ctx, "Discover")
+	defer func() { endSpan(span, err) }()
+
+	d := discovery{
+		depth:     DefaultDiscoveryDepth,
+		breadth:   DefaultDiscoveryBreadth,
+		maxVideos: DefaultDiscoveryMaxVideos,
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	if d.search.Order == "" {
+		d.search.Order = OrderRelevance
+	}
+
+	graph := newDiscoveryGraph()
+	seeds, err := yt.GetVideosWithParts(ctx, uniqueIds(seedIds), d.search.Parts)
+	if err != nil {
+		return graph, err
+	}
+	var frontier []string
+	for _, video := range seeds.Items {
+		if len(graph.Videos) >= d.maxVideos {
+			break
+		}
+		if graph.add(video, 0) {
+			frontier = append(frontier, video.Id)
+		}
+	}
+
+	for depth := 1; depth <= d.depth && len(frontier) > 0; depth++ {
+		var next []string
+		for _, id := range frontier {
+			if len(graph.Videos) >= d.maxVideos {
+				return graph, nil
+			}
+			found, err := yt.relatedVideos(ctx, graph.Videos[id], d)
+			if err != nil {
+				return graph, err
+			}
+			var fresh []string
+			for _, relatedId := range found {
+				if _, ok := graph.Videos[relatedId]; !ok {
+					fresh = append(fresh, relatedId)
+				}
+			}
+			if len(fresh) > 0 {
+				details, err := yt.GetVideosWithParts(ctx, fresh, d.search.Parts)
+				if err != nil {
+					return graph, err
+				}
+				for _, video := range details.Items {
+					if len(graph.Videos) >= d.maxVideos {
+						break
+					}
+					if graph.add(video, depth) {
+						next = append(next, video.Id)
+					}
+				}
+			}
+			for _, relatedId := range found {
+				if _, ok := graph.Videos[relatedId]; ok {
+					graph.Related[id] = append(graph.Related[id], relatedId)
+				}
+			}
+		}
+		frontier = next
+	}
+	return graph, nil
+}
+
+// relatedVideos returns the IDs of up to d.breadth videos related to video, excluding video itself.
+func (yt *YoutubeApi) relatedVideos(ctx context.Context, video *Video, d discovery) ([]string, error) {
+	query := discoveryQuery(video)
+	if query == "" {
+		return nil, nil
+	}
+	res, err := yt.SearchContext(ctx, query, 1, d.search)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, item := range res.Items {
+		if len(ids) >= d.breadth {
+			break
+		}
+		if item.Id == nil || item.Id.VideoId == "" || item.Id.VideoId == video.Id {
+			continue
+		}
+		ids = append(ids, item.Id.VideoId)
+	}
+	return ids, nil
+}
+
+// discoveryQuery builds the search query of the videos related to video from its title and the
+// first tags not already in the title.
+func discoveryQuery(video *Video) string {
+	if video.Snippet == nil {
+		return ""
+	}
+	query := video.Snippet.Title
+	lower := strings.ToLower(query)
+	added := 0
+	for _, tag := range video.Snippet.Tags {
+		if added == discoveryQueryTags {
+			break
+		}
+		if tag == "" || strings.Contains(lower, strings.ToLower(tag)) {
+			continue
+		}
+		query += " " + tag
+		added++
+	}
+	return strings.TrimSpace(query)
+}
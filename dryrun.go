@@ -0,0 +1,232 @@
+package alaitube
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDryRunPages bounds the pages a DryRun answers for a listing, so walking every upload of
+// a channel is estimated as that many pages instead of never ending.
+const DefaultDryRunPages = 10
+
+// PlannedRequest is a request recorded by a DryRun instead of being sent.
+type PlannedRequest struct {
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	// Url is the request URL with credentials redacted.
+	Url        string `json:"url"`
+	QuotaUnits int    `json:"quotaUnits"`
+}
+
+// EndpointEstimate totals the planned requests to an endpoint.
+type EndpointEstimate struct {
+	Method     string `json:"method"`
+	Endpoint   string `json:"endpoint"`
+	Calls      int    `json:"calls"`
+	QuotaUnits int    `json:"quotaUnits"`
+}
+
+// DryRun records the requests of a client created with WithDryRun and answers them with made-up
+// results, so calls run their usual pagination and batching without spending quota:
+//
+//	plan := alaitube.NewDryRun()
+//	dry := alaitube.NewClient(alaitube.WithApiKey(key), alaitube.WithDryRun(plan))
+//	dry.FindTags("golang", 5)
+//	fmt.Println(plan.QuotaUnits()) // 505: 5 searches at 100 units and 5 videos.list batches at 1
+//
+// Listings are answered with full pages of 50 items, up to the page bound, so estimates are for
+// the worst case of queries, channels, and playlists with many results. The results are
+// placeholders and must not be used for anything but counting.
+type DryRun struct {
+	maxPages int
+
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+// NewDryRun creates a DryRun answering up to maxPages pages per listing. A maxPages of zero or
+// less uses DefaultDryRunPages.
+func NewDryRun(maxPages ...int) *DryRun {
+	d := &DryRun{maxPages: DefaultDryRunPages}
+	if len(maxPages) > 0 && maxPages[0] > 0 {
+		d.maxPages = maxPages[0]
+	}
+	return d
+}
+
+// WithDryRun records the requests of the client to d instead of sending them. The client gets a
+// fresh MemoryCache so placeholder results never reach a shared cache, and doesn't persist results
+// or wait on rate limits. Middlewares still see the requests.
+func WithDryRun(d *DryRun) Option {
+	return func(yt *YoutubeApi) {
+		yt.dryRun = d
+	}
+}
+
+// applyDryRun isolates a client created with WithDryRun from the network and shared state.
+func (yt *YoutubeApi) applyDryRun() {
+	if yt.dryRun == nil {
+		return
+	}
+	yt.httpClient = &http.Client{Transport: yt.dryRun}
+	yt.Cache = NewMemoryCache()
+	yt.persister = nil
+	yt.limiter, yt.endpointLimiters = nil, nil
+}
+
+// Requests returns the recorded requests, in the order they were made.
+func (d *DryRun) Requests() []PlannedRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]PlannedRequest(nil), d.requests...)
+}
+
+// QuotaUnits returns the estimated quota cost of the recorded requests.
+func (d *DryRun) QuotaUnits() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	units := 0
+	for _, r := range d.requests {
+		units += r.QuotaUnits
+	}
+	return units
+}
+
+// Estimate totals the recorded requests per endpoint, in the order endpoints were first requested.
+func (d *DryRun) Estimate() []EndpointEstimate {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var estimates []EndpointEstimate
+	index := make(map[string]int)
+	for _, r := range d.requests {
+		key := r.Method + " " + r.Endpoint
+		i, ok := index[key]
+		if !ok {
+			i = len(estimates)
+			index[key] = i
+			estimates = append(estimates, EndpointEstimate{Method: r.Method, Endpoint: r.Endpoint})
+		}
+		estimates[i].Calls++
+		estimates[i].QuotaUnits += r.QuotaUnits
+	}
+	return estimates
+}
+
+// Reset forgets the recorded requests, to estimate another call with the same client. The
+// client's cache isn't cleared, so results it already fetched aren't counted again.
+func (d *DryRun) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.requests = nil
+}
+
+// RoundTrip records req and answers it with placeholder results.
+func (d *DryRun) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	endpoint := endpointName(req.URL)
+	d.mu.Lock()
+	d.requests = append(d.requests, PlannedRequest{
+		Method:     req.Method,
+		Endpoint:   endpoint,
+		Url:        RedactURL(req.URL.String()),
+		QuotaUnits: QuotaCost(req.Method, endpoint),
+	})
+	d.mu.Unlock()
+
+	var body interface{} = map[string]interface{}{"items": []interface{}{}}
+	if req.Method == http.MethodGet {
+		body = d.answer(endpoint, req)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed encoding dry run response: %w", err)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+		Request:       req,
+	}, nil
+}
+
+// answer makes up the response of a GET request to endpoint.
+func (d *DryRun) answer(endpoint string, req *http.Request) interface{} {
+	q := req.URL.Query()
+	switch endpoint {
+	case "search", "playlistItems":
+		page, _ := strconv.Atoi(strings.TrimPrefix(q.Get("pageToken"), "dry-"))
+		size := 50
+		if n, err := strconv.Atoi(q.Get("maxResults")); err == nil && n > 0 && n < size {
+			size = n
+		}
+		items := make([]interface{}, size)
+		for i := range items {
+			id := fmt.Sprintf("dry%08d", page*size+i)
+			if endpoint == "search" {
+				items[i] = map[string]interface{}{
+					"id":      map[string]string{"kind": "youtube#video", "videoId": id},
+					"snippet": map[string]string{"title": id},
+				}
+			} else {
+				items[i] = map[string]interface{}{
+					"contentDetails": map[string]string{"videoId": id},
+					"snippet":        map[string]interface{}{"title": id, "resourceId": map[string]string{"videoId": id}},
+				}
+			}
+		}
+		next := ""
+		if page+1 < d.maxPages {
+			next = "dry-" + strconv.Itoa(page+1)
+		}
+		return map[string]interface{}{"items": items, "nextPageToken": next}
+	case "videos":
+		var items []interface{}
+		for _, id := range splitIds(q.Get("id")) {
+			items = append(items, map[string]interface{}{
+				"id":             id,
+				"snippet":        map[string]interface{}{"title": id, "publishedAt": "2024-01-01T00:00:00Z"},
+				"statistics":     map[string]string{"viewCount": strconv.FormatInt(int64(MinViews)+1, 10)},
+				"contentDetails": map[string]string{"duration": "PT10M"},
+			})
+		}
+		return map[string]interface{}{"items": items}
+	case "channels":
+		ids := splitIds(q.Get("id"))
+		if len(ids) == 0 {
+			// forHandle, forUsername, and mine lookups resolve to a single channel.
+			ids = []string{"UCdryrun0000000000000000"}
+		}
+		var items []interface{}
+		for _, id := range ids {
+			items = append(items, map[string]interface{}{
+				"id":             id,
+				"snippet":        map[string]string{"title": id},
+				"contentDetails": map[string]interface{}{"relatedPlaylists": map[string]string{"uploads": "UU" + strings.TrimPrefix(id, "UC")}},
+				"statistics":     map[string]string{"viewCount": "0", "videoCount": "0", "subscriberCount": "0"},
+			})
+		}
+		return map[string]interface{}{"items": items}
+	}
+	return map[string]interface{}{"items": []interface{}{}}
+}
+
+// splitIds splits a comma-separated id parameter.
+func splitIds(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
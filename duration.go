@@ -0,0 +1,123 @@
+package alaitube
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a video length. YouTube reports durations in ISO 8601 form such as "PT1H2M3S";
+// Duration decodes them into a time.Duration and encodes them back in the same form.
+type Duration time.Duration
+
+// ParseISODuration parses an ISO 8601 duration of the form P[nW][nD][T[nH][nM][n[.n]S]].
+// Years and months are rejected since their length is ambiguous.
+func ParseISODuration(s string) (time.Duration, error) {
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+	}
+	var total time.Duration
+	inTime := false
+	num := ""
+	for _, r := range s[1:] {
+		switch {
+		case r == 'T':
+			if inTime || num != "" {
+				return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+			}
+			inTime = true
+		case (r >= '0' && r <= '9') || r == '.':
+			num += string(r)
+		default:
+			if num == "" {
+				return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+			}
+			n, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid ISO 8601 duration %q: %w", s, err)
+			}
+			num = ""
+			var unit time.Duration
+			switch {
+			case r == 'W' && !inTime:
+				unit = 7 * 24 * time.Hour
+			case r == 'D' && !inTime:
+				unit = 24 * time.Hour
+			case r == 'H' && inTime:
+				unit = time.Hour
+			case r == 'M' && inTime:
+				unit = time.Minute
+			case r == 'S' && inTime:
+				unit = time.Second
+			default:
+				return 0, fmt.Errorf("unsupported ISO 8601 duration %q", s)
+			}
+			total += time.Duration(n * float64(unit))
+		}
+	}
+	// A duration needs at least one component, and so does its time part.
+	if num != "" || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+	}
+	return total, nil
+}
+
+// FormatISODuration formats d as an ISO 8601 duration such as "PT1H2M3S".
+func FormatISODuration(d time.Duration) string {
+	if d <= 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("P")
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d == 0 {
+		return b.String()
+	}
+	b.WriteString("T")
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+		b.WriteString("S")
+	}
+	return b.String()
+}
+
+// UnmarshalJSON decodes an ISO 8601 duration string.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*d = 0
+		return nil
+	}
+	parsed, err := ParseISODuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON encodes the duration as an ISO 8601 string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(FormatISODuration(time.Duration(d)))
+}
+
+// Std returns the duration as a time.Duration.
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
@@ -0,0 +1,71 @@
+package alaitube_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/josephalai/alaitube"
+)
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "PT1H2M3S", want: time.Hour + 2*time.Minute + 3*time.Second},
+		{in: "PT15M", want: 15 * time.Minute},
+		{in: "PT45S", want: 45 * time.Second},
+		{in: "PT1.5S", want: 1500 * time.Millisecond},
+		{in: "P1DT2H", want: 26 * time.Hour},
+		{in: "P1DT1H1M1S", want: 25*time.Hour + time.Minute + time.Second},
+		{in: "P2W", want: 14 * 24 * time.Hour},
+		{in: "P1D", want: 24 * time.Hour},
+		{in: "PT0S", want: 0},
+		// Live broadcasts report a zero duration.
+		{in: "P0D", want: 0},
+		{in: "", wantErr: true},
+		{in: "P", wantErr: true},
+		{in: "PT", wantErr: true},
+		{in: "P1DT", wantErr: true},
+		{in: "1H2M", wantErr: true},
+		{in: "T1H", wantErr: true},
+		{in: "PT1H2", wantErr: true},
+		{in: "PTH", wantErr: true},
+		{in: "PT1X", wantErr: true},
+		{in: "P1H", wantErr: true},
+		{in: "PT1D", wantErr: true},
+		{in: "P1TH", wantErr: true},
+		{in: "PT1HT2M", wantErr: true},
+		{in: "PT1.2.3S", wantErr: true},
+		// Years and months have no fixed length.
+		{in: "P1Y", wantErr: true},
+		{in: "P1M", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := alaitube.ParseISODuration(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseISODuration(%q) = %s, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseISODuration(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseISODuration(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatISODurationRoundTrip(t *testing.T) {
+	for _, d := range []time.Duration{time.Second, 59 * time.Minute, time.Hour + 2*time.Minute + 3*time.Second, 26 * time.Hour} {
+		s := alaitube.FormatISODuration(d)
+		got, err := alaitube.ParseISODuration(s)
+		if err != nil || got != d {
+			t.Errorf("ParseISODuration(FormatISODuration(%s) = %q) = %s, %v", d, s, got, err)
+		}
+	}
+}
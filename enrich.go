@@ -0,0 +1,76 @@
+package alaitube
+
+import "context"
+
+// ChannelSummary holds the statistics and artwork of a video's channel.
+type ChannelSummary struct {
+	Id                    string     `bson:"id,omitempty" json:"id,omitempty"`
+	Title                 string     `bson:"title,omitempty" json:"title,omitempty"`
+	CustomUrl             string     `bson:"customUrl,omitempty" json:"customUrl,omitempty"`
+	Thumbnails            Thumbnails `bson:"thumbnails,omitempty" json:"thumbnails,omitempty"`
+	SubscriberCount       Count      `bson:"subscriberCount,omitempty" json:"subscriberCount,omitempty"`
+	HiddenSubscriberCount bool       `bson:"hiddenSubscriberCount,omitempty" json:"hiddenSubscriberCount,omitempty"`
+	VideoCount            Count      `bson:"videoCount,omitempty" json:"videoCount,omitempty"`
+	ViewCount             Count      `bson:"viewCount,omitempty" json:"viewCount,omitempty"`
+}
+
+// NewChannelSummary summarizes a channel item.
+func NewChannelSummary(item *Item) *ChannelSummary {
+	s := &ChannelSummary{Id: item.Id}
+	if item.Snippet != nil {
+		s.Title = item.Snippet.Title
+		s.CustomUrl = item.Snippet.CustomUrl
+		s.Thumbnails = item.Snippet.Thumbnails
+	}
+	if item.Statistics != nil {
+		s.SubscriberCount = item.Statistics.SubscriberCount
+		s.HiddenSubscriberCount = item.Statistics.HiddenSubscriberCount
+		s.VideoCount = item.Statistics.VideoCount
+		s.ViewCount = item.Statistics.ViewCount
+	}
+	return s
+}
+
+// EnrichWithChannelStats sets the Channel field of every video in results to a summary of its
+// channel. The distinct channels are fetched with GetChannels, so enriching a page of results
+// costs at most one request per 50 uncached channels. Videos whose channel is unknown are
+// left unchanged.
+//
+// The enriched videos are copies replacing the originals in results, since the videos returned
+// by the client are shared with the cache and with concurrent callers.
+func (yt *YoutubeApi) EnrichWithChannelStats(results *VideoResults) error {
+	return yt.EnrichWithChannelStatsContext(context.Background(), results)
+}
+
+// EnrichWithChannelStatsContext is like EnrichWithChannelStats but uses ctx for the channel requests.
+func (yt *YoutubeApi) EnrichWithChannelStatsContext(ctx context.Context, results *VideoResults) error {
+	if results == nil {
+		return nil
+	}
+	var ids []string
+	for _, v := range results.Items {
+		if v != nil && v.Snippet != nil && v.Snippet.ChannelId != "" {
+			ids = append(ids, v.Snippet.ChannelId)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	channels, err := yt.GetChannelsContext(ctx, ids)
+	summaries := make(map[string]*ChannelSummary, len(channels))
+	for id, item := range channels {
+		summaries[id] = NewChannelSummary(item)
+	}
+	// Attach what was fetched even when a later batch failed.
+	for i, v := range results.Items {
+		if v == nil || v.Snippet == nil {
+			continue
+		}
+		if s, ok := summaries[v.Snippet.ChannelId]; ok {
+			enriched := *v
+			enriched.Channel = s
+			results.Items[i] = &enriched
+		}
+	}
+	return err
+}
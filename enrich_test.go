@@ -0,0 +1,75 @@
+package alaitube_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/josephalai/alaitube"
+	"github.com/josephalai/alaitube/testutil"
+)
+
+func TestEnrichWithChannelStatsLeavesCacheUntouched(t *testing.T) {
+	srv := testutil.NewServer()
+	defer srv.Close()
+	var channel alaitube.Item
+	if err := json.Unmarshal([]byte(`{"id":"UCcats","snippet":{"title":"Cat channel"},"statistics":{"subscriberCount":"42"}}`), &channel); err != nil {
+		t.Fatal(err)
+	}
+	srv.AddChannel(&channel)
+	for i := 0; i < 3; i++ {
+		var v alaitube.Video
+		data := fmt.Sprintf(`{"id":"cat%d","snippet":{"title":"cats %d","channelId":"UCcats"},"statistics":{"viewCount":"5000"}}`, i, i)
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			t.Fatal(err)
+		}
+		srv.AddVideo(&v)
+	}
+	yt := alaitube.NewClient(alaitube.WithApiKey("test"), alaitube.WithHttpClient(srv.Client()))
+	// Cached before enriching concurrently.
+	if _, err := yt.FindTags("cats", 1); err != nil {
+		t.Fatalf("FindTags: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(enrich bool) {
+			defer wg.Done()
+			results, err := yt.FindTags("cats", 1)
+			if err != nil {
+				t.Errorf("FindTags: %v", err)
+				return
+			}
+			if enrich {
+				if err := yt.EnrichWithChannelStats(results); err != nil {
+					t.Errorf("EnrichWithChannelStats: %v", err)
+					return
+				}
+			}
+			for _, v := range results.Items {
+				if enrich && (v.Channel == nil || v.Channel.Title != "Cat channel" || v.Channel.SubscriberCount != 42) {
+					t.Errorf("video %s channel = %+v, want the summary of UCcats", v.Id, v.Channel)
+				}
+				if !enrich && v.Channel != nil {
+					t.Errorf("video %s of unenriched results has channel %+v", v.Id, v.Channel)
+				}
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	cached, err := yt.FindTags("cats", 1)
+	if err != nil {
+		t.Fatalf("FindTags: %v", err)
+	}
+	if len(cached.Items) != 3 {
+		t.Fatalf("FindTags returned %d videos, want 3", len(cached.Items))
+	}
+	for _, v := range cached.Items {
+		if v.Channel != nil {
+			t.Errorf("cached video %s was enriched", v.Id)
+		}
+	}
+}
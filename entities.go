@@ -0,0 +1,63 @@
+package alaitube
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// hashtagPattern matches #hashtags not preceded by a word character, such as in URL fragments or "C#".
+	hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&/])#([\p{L}\p{N}_]+)`)
+	// mentionPattern matches @handles not preceded by a word character, as in e-mail addresses.
+	mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.])@([\p{L}\p{N}_.\-]{3,30})`)
+	urlPattern     = regexp.MustCompile(`https?://[^\s<>"]+`)
+)
+
+// Entities holds the hashtags, mentions, and links found in a text, in order of first appearance.
+type Entities struct {
+	// Hashtags are without the leading #, e.g. "golang" for #golang.
+	Hashtags []string `bson:"hashtags,omitempty" json:"hashtags,omitempty"`
+	// Mentions are channel handles without the leading @.
+	Mentions []string `bson:"mentions,omitempty" json:"mentions,omitempty"`
+	Urls     []string `bson:"urls,omitempty" json:"urls,omitempty"`
+}
+
+// ExtractEntities finds the hashtags, @mentions, and links of text. Hashtags made only of digits,
+// such as "#1", are ignored, and duplicates are reported once, ignoring case.
+func ExtractEntities(text string) Entities {
+	var e Entities
+	for _, m := range hashtagPattern.FindAllStringSubmatch(text, -1) {
+		if strings.IndexFunc(m[1], unicode.IsLetter) >= 0 {
+			e.Hashtags = appendUniqueFold(e.Hashtags, m[1])
+		}
+	}
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		e.Mentions = appendUniqueFold(e.Mentions, strings.TrimRight(m[1], ".-"))
+	}
+	for _, u := range urlPattern.FindAllString(text, -1) {
+		e.Urls = appendUniqueFold(e.Urls, strings.TrimRight(u, ".,;:!?)]}'"))
+	}
+	return e
+}
+
+// appendUniqueFold appends s to list unless list already holds it, ignoring case.
+func appendUniqueFold(list []string, s string) []string {
+	if s == "" || containsFold(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+// Entities returns the hashtags, mentions, and links of the video's title and description.
+func (v *Video) Entities() Entities {
+	if v.Snippet == nil {
+		return Entities{}
+	}
+	return ExtractEntities(v.Snippet.Title + "\n" + v.Snippet.Description)
+}
+
+// Hashtags returns the hashtags of the video's title and description, without the leading #.
+func (v *Video) Hashtags() []string {
+	return v.Entities().Hashtags
+}
@@ -0,0 +1,136 @@
+package alaitube
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matched by ApiError through errors.Is.
+var (
+	ErrQuotaExceeded = errors.New("youtube quota exceeded")
+	ErrNotFound      = errors.New("youtube resource not found")
+	ErrInvalidKey    = errors.New("youtube api key invalid")
+	ErrRateLimited   = errors.New("youtube rate limit exceeded")
+	ErrForbidden     = errors.New("youtube request forbidden")
+)
+
+// ErrorDetail is a single entry of the errors list in a YouTube error response.
+type ErrorDetail struct {
+	Domain   string `json:"domain,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+// ApiError is returned when YouTube answers a request with an error response or a non-2xx status.
+// Code is the HTTP status code reported in the error envelope and Reason is the
+// reason of its first error detail, e.g. "quotaExceeded" or "videoNotFound".
+// Responses without an error envelope carry the status as Code and the status text as Message.
+type ApiError struct {
+	Code    int           `json:"code,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Reason  string        `json:"-"`
+	Errors  []ErrorDetail `json:"errors,omitempty"`
+	// StatusCode is the HTTP status of the response.
+	StatusCode int `json:"-"`
+	// Body holds the start of the response body when it wasn't a YouTube error envelope.
+	Body string `json:"-"`
+}
+
+func (e *ApiError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("youtube api error %d (%s): %s", e.Code, e.Reason, e.Message)
+	}
+	return fmt.Sprintf("youtube api error %d: %s", e.Code, e.Message)
+}
+
+// Is reports whether the error matches one of the package's sentinel errors,
+// so callers can write errors.Is(err, ErrQuotaExceeded).
+func (e *ApiError) Is(target error) bool {
+	switch target {
+	case ErrQuotaExceeded:
+		return e.hasReason("quotaExceeded", "dailyLimitExceeded")
+	case ErrNotFound:
+		if e.Code == 404 {
+			return true
+		}
+		for _, d := range e.Errors {
+			if strings.HasSuffix(d.Reason, "NotFound") {
+				return true
+			}
+		}
+		return false
+	case ErrInvalidKey:
+		return e.hasReason("keyInvalid", "keyExpired") || strings.Contains(e.Message, "API key not valid")
+	case ErrRateLimited:
+		return e.Code == 429 || e.hasReason("rateLimitExceeded", "userRateLimitExceeded")
+	case ErrForbidden:
+		return e.Code == 403 && !e.hasReason("quotaExceeded", "dailyLimitExceeded", "rateLimitExceeded", "userRateLimitExceeded")
+	}
+	return false
+}
+
+func (e *ApiError) hasReason(reasons ...string) bool {
+	for _, d := range e.Errors {
+		for _, r := range reasons {
+			if d.Reason == r {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseApiError extracts the YouTube error envelope from a response body.
+// It returns nil when the body does not contain one.
+func parseApiError(body []byte) *ApiError {
+	envelope := struct {
+		Error *ApiError `json:"error"`
+	}{}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Error == nil {
+		return nil
+	}
+	apiErr := envelope.Error
+	if len(apiErr.Errors) > 0 {
+		apiErr.Reason = apiErr.Errors[0].Reason
+	}
+	return apiErr
+}
+
+// maxErrorBody is how much of a response body without an error envelope is kept in ApiError.Body.
+const maxErrorBody = 512
+
+// statusError returns the error of a response with the given status and body: the parsed
+// error envelope if any, otherwise an ApiError carrying the status and the start of the body.
+func statusError(status int, body []byte) *ApiError {
+	apiErr := parseApiError(body)
+	if apiErr == nil {
+		apiErr = &ApiError{Message: http.StatusText(status)}
+		if len(body) > maxErrorBody {
+			body = body[:maxErrorBody]
+		}
+		apiErr.Body = strings.TrimSpace(string(body))
+	}
+	if apiErr.Code == 0 {
+		apiErr.Code = status
+	}
+	apiErr.StatusCode = status
+	return apiErr
+}
+
+// isSuccessStatus reports whether status is a 2xx status.
+func isSuccessStatus(status int) bool {
+	return status >= 200 && status < 300
+}
+
+// errorReason returns the YouTube error reason carried by err, or "" when err is not an ApiError.
+func errorReason(err error) string {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.Reason
+	}
+	return ""
+}
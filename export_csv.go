@@ -0,0 +1,170 @@
+package alaitube
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VideoColumn is a column of a video export: a header and the value of each video.
+type VideoColumn struct {
+	Name  string
+	Value func(v *Video) string
+}
+
+// Columns of video exports.
+var (
+	VideoColumnId          = VideoColumn{"id", func(v *Video) string { return v.Id }}
+	VideoColumnTitle       = VideoColumn{"title", func(v *Video) string { return videoTitle(v) }}
+	VideoColumnChannel     = VideoColumn{"channel", videoSnippetValue(func(v *Video) string { return v.Snippet.ChannelTitle })}
+	VideoColumnChannelId   = VideoColumn{"channelId", videoSnippetValue(func(v *Video) string { return v.Snippet.ChannelId })}
+	VideoColumnViews       = VideoColumn{"views", videoStatValue(func(v *Video) Count { return v.Statistics.ViewCount })}
+	VideoColumnLikes       = VideoColumn{"likes", videoStatValue(func(v *Video) Count { return v.Statistics.LikeCount })}
+	VideoColumnComments    = VideoColumn{"comments", videoStatValue(func(v *Video) Count { return v.Statistics.CommentCount })}
+	VideoColumnTags        = VideoColumn{"tags", videoSnippetValue(func(v *Video) string { return strings.Join(v.Snippet.Tags, ", ") })}
+	VideoColumnPublishedAt = VideoColumn{"publishedAt", func(v *Video) string { return formatExportTime(v.PublishedAt()) }}
+	VideoColumnDuration    = VideoColumn{"duration", videoDurationValue}
+	VideoColumnUrl         = VideoColumn{"url", (*Video).Url}
+)
+
+// DefaultVideoColumns are the columns of a video export when none are given.
+var DefaultVideoColumns = []VideoColumn{
+	VideoColumnId, VideoColumnTitle, VideoColumnChannel, VideoColumnViews, VideoColumnLikes, VideoColumnTags, VideoColumnPublishedAt,
+}
+
+// videoSnippetValue wraps a column value reading the snippet, returning "" when it wasn't fetched.
+func videoSnippetValue(value func(v *Video) string) func(v *Video) string {
+	return func(v *Video) string {
+		if v.Snippet == nil {
+			return ""
+		}
+		return value(v)
+	}
+}
+
+// videoStatValue wraps a column value reading a count, returning "" when statistics weren't fetched.
+func videoStatValue(value func(v *Video) Count) func(v *Video) string {
+	return func(v *Video) string {
+		if v.Statistics == nil {
+			return ""
+		}
+		return strconv.FormatInt(int64(value(v)), 10)
+	}
+}
+
+// videoDurationValue returns the duration of v in seconds, or "" when contentDetails wasn't fetched.
+func videoDurationValue(v *Video) string {
+	if d := v.Duration(); d > 0 {
+		return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+	}
+	return ""
+}
+
+// ChannelColumn is a column of a channel export: a header and the value of each channel.
+type ChannelColumn struct {
+	Name  string
+	Value func(c *Item) string
+}
+
+// Columns of channel exports.
+var (
+	ChannelColumnId          = ChannelColumn{"id", func(c *Item) string { return c.Id }}
+	ChannelColumnTitle       = ChannelColumn{"title", channelTitle}
+	ChannelColumnCustomUrl   = ChannelColumn{"customUrl", channelSnippetValue(func(c *Item) string { return c.Snippet.CustomUrl })}
+	ChannelColumnCountry     = ChannelColumn{"country", channelSnippetValue(func(c *Item) string { return c.Snippet.Country })}
+	ChannelColumnSubscribers = ChannelColumn{"subscribers", channelStatValue(func(c *Item) Count { return c.Statistics.SubscriberCount })}
+	ChannelColumnViews       = ChannelColumn{"views", channelStatValue(func(c *Item) Count { return c.Statistics.ViewCount })}
+	ChannelColumnVideos      = ChannelColumn{"videos", channelStatValue(func(c *Item) Count { return c.Statistics.VideoCount })}
+	ChannelColumnPublishedAt = ChannelColumn{"publishedAt", channelSnippetValue(func(c *Item) string { return formatExportTime(c.Snippet.PublishedAt) })}
+	ChannelColumnUrl         = ChannelColumn{"url", (*Item).Url}
+)
+
+// DefaultChannelColumns are the columns of a channel export when none are given.
+var DefaultChannelColumns = []ChannelColumn{
+	ChannelColumnId, ChannelColumnTitle, ChannelColumnSubscribers, ChannelColumnViews, ChannelColumnVideos, ChannelColumnPublishedAt,
+}
+
+// channelSnippetValue wraps a column value reading the snippet, returning "" when it wasn't fetched.
+func channelSnippetValue(value func(c *Item) string) func(c *Item) string {
+	return func(c *Item) string {
+		if c.Snippet == nil {
+			return ""
+		}
+		return value(c)
+	}
+}
+
+// channelStatValue wraps a column value reading a count, returning "" when statistics weren't fetched.
+func channelStatValue(value func(c *Item) Count) func(c *Item) string {
+	return func(c *Item) string {
+		if c.Statistics == nil {
+			return ""
+		}
+		return strconv.FormatInt(int64(value(c)), 10)
+	}
+}
+
+// formatExportTime formats t as RFC 3339 in UTC, or "" when it is zero.
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ExportVideosCSV writes the videos of results to w as CSV, with a header row followed by a row
+// per video. Without columns, DefaultVideoColumns are written. Missing parts give empty cells.
+func ExportVideosCSV(w io.Writer, results *VideoResults, columns ...VideoColumn) error {
+	if len(columns) == 0 {
+		columns = DefaultVideoColumns
+	}
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Name
+	}
+	return writeCSV(w, header, len(results.Items), func(n int, row []string) {
+		for i, c := range columns {
+			row[i] = c.Value(results.Items[n])
+		}
+	})
+}
+
+// ExportChannelsCSV writes the channels of info to w as CSV, with a header row followed by a row
+// per channel. Without columns, DefaultChannelColumns are written. Missing parts give empty cells.
+func ExportChannelsCSV(w io.Writer, info *ChannelInfo, columns ...ChannelColumn) error {
+	if len(columns) == 0 {
+		columns = DefaultChannelColumns
+	}
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Name
+	}
+	return writeCSV(w, header, len(info.Items), func(n int, row []string) {
+		for i, c := range columns {
+			row[i] = c.Value(info.Items[n])
+		}
+	})
+}
+
+// writeCSV writes header followed by rows rows, each filled in by fill.
+func writeCSV(w io.Writer, header []string, rows int, fill func(n int, row []string)) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("failed writing csv: %w", err)
+	}
+	row := make([]string, len(header))
+	for n := 0; n < rows; n++ {
+		fill(n, row)
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed writing csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed writing csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package alaitube
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// NDJSONWriter writes records as newline-delimited JSON (JSON Lines), one record per line, as
+// they are produced, for piping into jq, BigQuery loads, or log pipelines. When the underlying
+// writer has a Flush method, like a *bufio.Writer or an http.ResponseWriter, each record is
+// flushed as soon as it is written. An NDJSONWriter is not safe for concurrent use.
+type NDJSONWriter struct {
+	w     io.Writer
+	enc   *json.Encoder
+	count int
+}
+
+// NewNDJSONWriter creates an NDJSONWriter writing to w.
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &NDJSONWriter{w: w, enc: enc}
+}
+
+// Count returns the number of records written.
+func (w *NDJSONWriter) Count() int {
+	return w.count
+}
+
+// Write writes a single record, such as a *Video, an *Item, or a TagStat, on its own line.
+func (w *NDJSONWriter) Write(record interface{}) error {
+	if err := w.enc.Encode(record); err != nil {
+		return fmt.Errorf("failed writing ndjson record: %w", err)
+	}
+	w.count++
+	switch f := w.w.(type) {
+	case interface{ Flush() error }:
+		if err := f.Flush(); err != nil {
+			return fmt.Errorf("failed flushing ndjson record: %w", err)
+		}
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
+
+// WriteResults writes every video of results, one per line.
+func (w *NDJSONWriter) WriteResults(results *VideoResults) error {
+	for _, v := range results.Items {
+		if err := w.Write(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteChannels writes every channel of info, one per line.
+func (w *NDJSONWriter) WriteChannels(info *ChannelInfo) error {
+	for _, item := range info.Items {
+		if err := w.Write(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteStream writes the videos of a stream, such as the channels returned by FindTagsStream,
+// as they arrive, until the video channel is closed. It returns the error delivered on errc, or
+// the first write error; after a write error the stream is drained so its goroutine can exit,
+// and cancelling its context stops the search early.
+func (w *NDJSONWriter) WriteStream(videos <-chan *Video, errc <-chan error) error {
+	var writeErr error
+	for v := range videos {
+		if writeErr == nil {
+			writeErr = w.Write(v)
+		}
+	}
+	if err := <-errc; err != nil && writeErr == nil {
+		return err
+	}
+	return writeErr
+}
@@ -0,0 +1,362 @@
+package alaitube
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultFileTTL is how long FileStore entries live when no TTL is configured for a region.
+const DefaultFileTTL = 24 * time.Hour
+
+// DefaultFileMaxBytes is the default size a FileStore directory is kept under by garbage collection.
+const DefaultFileMaxBytes int64 = 256 << 20
+
+// FileStore is a CacheV2 that persists entries as JSON files under a directory, one
+// subdirectory per region, named by the SHA-256 of the key. It survives restarts, which suits
+// CLI and batch workflows. Entries carry their expiration time; GC removes expired entries and,
+// once the directory exceeds its size cap, the least recently used ones.
+type FileStore struct {
+	dir      string
+	ttls     map[CacheRegion]time.Duration
+	maxBytes int64
+	now      func() time.Time
+
+	mu      sync.Mutex
+	written int64
+}
+
+// fileEntry is the on-disk format of a FileStore entry. Values that are valid JSON are stored
+// inline so the files stay readable; anything else is stored base64-encoded in Data.
+type fileEntry struct {
+	Key       string          `json:"key"`
+	ExpiresAt *time.Time      `json:"expiresAt,omitempty"`
+	Value     json.RawMessage `json:"value,omitempty"`
+	Data      []byte          `json:"data,omitempty"`
+}
+
+// FileStoreOption configures a FileStore created with NewFileStore or NewFileCache.
+type FileStoreOption func(*FileStore)
+
+// WithFileTTL sets the entry lifetime for every region.
+// A zero TTL keeps entries until they are deleted, purged, or collected for size.
+func WithFileTTL(ttl time.Duration) FileStoreOption {
+	return func(s *FileStore) {
+		for _, region := range cacheRegions {
+			s.ttls[region] = ttl
+		}
+	}
+}
+
+// WithFileRegionTTL sets the entry lifetime for a single region.
+func WithFileRegionTTL(region CacheRegion, ttl time.Duration) FileStoreOption {
+	return func(s *FileStore) {
+		s.ttls[region] = ttl
+	}
+}
+
+// WithFileMaxBytes caps the total size of the entries. A cap of zero or less disables
+// size-based collection; expired entries are still removed by GC.
+func WithFileMaxBytes(maxBytes int64) FileStoreOption {
+	return func(s *FileStore) {
+		s.maxBytes = maxBytes
+	}
+}
+
+// NewFileStore creates a FileStore under dir, creating the directory if needed.
+func NewFileStore(dir string, opts ...FileStoreOption) (*FileStore, error) {
+	s := &FileStore{
+		dir:      dir,
+		ttls:     make(map[CacheRegion]time.Duration),
+		maxBytes: DefaultFileMaxBytes,
+		now:      time.Now,
+	}
+	for _, region := range cacheRegions {
+		s.ttls[region] = DefaultFileTTL
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed creating cache directory: %w", err)
+	}
+	return s, nil
+}
+
+func (s *FileStore) regionDir(region CacheRegion) string {
+	return filepath.Join(s.dir, url.PathEscape(string(region)))
+}
+
+func (s *FileStore) path(region CacheRegion, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.regionDir(region), hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the value of key in region. A hit refreshes the file's modification time,
+// which GC uses as the last access time.
+func (s *FileStore) Get(ctx context.Context, region CacheRegion, key string) ([]byte, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	path := s.path(region, key)
+	entry, err := readFileEntry(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, false, err
+	}
+	if entry.Key != key {
+		return nil, false, nil
+	}
+	now := s.now()
+	if entry.ExpiresAt != nil && now.After(*entry.ExpiresAt) {
+		os.Remove(path)
+		return nil, false, nil
+	}
+	os.Chtimes(path, now, now)
+	if entry.Value != nil {
+		return entry.Value, true, nil
+	}
+	return entry.Data, true, nil
+}
+
+// Set writes value under key in region. Files are written through a temporary file so
+// readers never see a partial entry.
+func (s *FileStore) Set(ctx context.Context, region CacheRegion, key string, value []byte, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ttl == 0 {
+		ttl = s.ttls[region]
+	}
+	entry := fileEntry{Key: key}
+	if ttl > 0 {
+		expiresAt := s.now().Add(ttl)
+		entry.ExpiresAt = &expiresAt
+	}
+	if json.Valid(value) {
+		entry.Value = value
+	} else {
+		entry.Data = value
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	dir := s.regionDir(region)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed creating cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed writing cache entry: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path(region, key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed writing cache entry: %w", err)
+	}
+
+	// Collect once a tenth of the cap has been written since the last collection,
+	// so the directory stays near its cap without scanning it on every write.
+	s.mu.Lock()
+	s.written += int64(len(data))
+	collect := s.maxBytes > 0 && s.written >= s.maxBytes/10
+	if collect {
+		s.written = 0
+	}
+	s.mu.Unlock()
+	if collect {
+		if _, err := s.GC(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the entry for key in region.
+func (s *FileStore) Delete(ctx context.Context, region CacheRegion, key string) error {
+	if err := os.Remove(s.path(region, key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed deleting cache entry: %w", err)
+	}
+	return nil
+}
+
+// PurgeRegion removes every entry of region.
+func (s *FileStore) PurgeRegion(ctx context.Context, region CacheRegion) error {
+	if err := os.RemoveAll(s.regionDir(region)); err != nil {
+		return fmt.Errorf("failed purging cache region: %w", err)
+	}
+	return nil
+}
+
+// PurgeAll removes every region directory. Other files in the directory are left alone.
+func (s *FileStore) PurgeAll(ctx context.Context) error {
+	dirs, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed purging cache: %w", err)
+	}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.dir, d.Name())); err != nil {
+			return fmt.Errorf("failed purging cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// GC removes expired and unreadable entries, then removes the least recently used entries
+// until the store is under its size cap. It returns how many entries were removed.
+func (s *FileStore) GC(ctx context.Context) (int, error) {
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	var total int64
+	removed := 0
+	now := s.now()
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Entries live in region directories; files at the top level aren't ours.
+		if d.IsDir() || filepath.Ext(path) != ".json" || path == filepath.Join(s.dir, d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		entry, err := readFileEntry(path)
+		if err != nil || (entry.ExpiresAt != nil && now.After(*entry.ExpiresAt)) {
+			if os.Remove(path) == nil {
+				removed++
+			}
+			return nil
+		}
+		files = append(files, file{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("failed collecting cache: %w", err)
+	}
+
+	if s.maxBytes <= 0 || total <= s.maxBytes {
+		return removed, nil
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	for _, f := range files {
+		if total <= s.maxBytes {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			removed++
+			total -= f.size
+		}
+	}
+	return removed, nil
+}
+
+// RegionSize returns the number of entry files of region and their size on disk.
+func (s *FileStore) RegionSize(ctx context.Context, region CacheRegion) (int64, int64, error) {
+	files, err := os.ReadDir(s.regionDir(region))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed reading cache region: %w", err)
+	}
+	var entries, bytes int64
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, err
+		}
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		entries++
+		bytes += info.Size()
+	}
+	return entries, bytes, nil
+}
+
+func readFileEntry(path string) (*fileEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entry := &fileEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cache entry %s: %w", filepath.Base(path), err)
+	}
+	return entry, nil
+}
+
+// FileCache is a Cache that persists results to disk through a FileStore.
+type FileCache struct {
+	*CacheV2Adapter
+	store *FileStore
+}
+
+// NewFileCache creates a FileCache under dir. Not-found results are remembered for DefaultNegativeTTL.
+func NewFileCache(dir string, opts ...FileStoreOption) (*FileCache, error) {
+	store, err := NewFileStore(dir, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &FileCache{
+		CacheV2Adapter: NewCacheV2Adapter(store, WithAdapterName("file-cache"), WithAdapterTimeout(0)),
+		store:          store,
+	}, nil
+}
+
+// Store returns the underlying FileStore, e.g. to back additional Regions.
+func (c *FileCache) Store() *FileStore {
+	return c.store
+}
+
+// GC removes expired entries and trims the cache to its size cap. See FileStore.GC.
+func (c *FileCache) GC() (int, error) {
+	return c.store.GC(context.Background())
+}
+
+var _ CacheV2 = (*FileStore)(nil)
+var _ CacheV2Sizer = (*FileStore)(nil)
+var _ Cache = (*FileCache)(nil)
+var _ NegativeCache = (*FileCache)(nil)
@@ -0,0 +1,471 @@
+// Package graphqlapi exposes a YoutubeApi client through a GraphQL schema, so frontends can
+// query exactly the fields they need:
+//
+//	schema, err := graphqlapi.NewSchema(yt)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	http.Handle("/graphql", graphqlapi.Handler(schema))
+//
+// Videos and channels looked up while resolving a query, such as the channel of every video of a
+// search, are batched into single GetVideos and GetChannels calls, so a query costs the same
+// quota however many of them it selects.
+package graphqlapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/josephalai/alaitube"
+)
+
+// Schema is the GraphQL schema served by the package.
+const Schema = `
+schema {
+	query: Query
+}
+
+"A 64-bit integer, for counts that overflow GraphQL's 32-bit Int."
+scalar Int64
+
+type Query {
+	video(id: ID!): Video
+	"Videos in the order of ids, null for the ones YouTube doesn't know."
+	videos(ids: [ID!]!): [Video]!
+	channel(id: ID!): Channel
+	playlist(id: ID!): Playlist!
+	"Videos matching query. Each page costs 100 quota units."
+	search(query: String!, pages: Int = 1, order: String, regionCode: String, first: Int): [Video!]!
+}
+
+type Video {
+	id: ID!
+	title: String!
+	description: String!
+	"Publication time in RFC 3339, null when the snippet wasn't fetched."
+	publishedAt: String
+	tags: [String!]!
+	durationSeconds: Int
+	viewCount: Int64
+	likeCount: Int64
+	commentCount: Int64
+	thumbnailUrl: String
+	url: String!
+	channel: Channel
+}
+
+type Channel {
+	id: ID!
+	title: String!
+	description: String!
+	customUrl: String
+	country: String
+	publishedAt: String
+	"Null when the channel hides it."
+	subscriberCount: Int64
+	viewCount: Int64
+	videoCount: Int64
+	thumbnailUrl: String
+	url: String!
+	"Latest uploads, newest first."
+	uploads(first: Int = 25): [Video!]!
+}
+
+type Playlist {
+	id: ID!
+	videos(first: Int = 50): [Video!]!
+}
+`
+
+// Backend is the part of the client used by the resolvers. *alaitube.YoutubeApi implements it.
+type Backend interface {
+	FindTagsContext(ctx context.Context, input string, numPages int, opts ...alaitube.SearchOptions) (*alaitube.VideoResults, error)
+	GetVideosContext(ctx context.Context, videoIds []string) (*alaitube.VideoResults, error)
+	GetChannelsContext(ctx context.Context, channelIds []string) (map[string]*alaitube.Item, error)
+	GetChannelPlaylistContext(ctx context.Context, item *alaitube.Item, vidCount int) (*alaitube.VideoResults, error)
+	GetPlaylistVideosContext(ctx context.Context, playlistId string, n int) (*alaitube.VideoResults, error)
+}
+
+var _ Backend = (*alaitube.YoutubeApi)(nil)
+
+// Limits of the arguments of list fields, bounding the quota a single query can spend.
+const (
+	MaxSearchPages = 5
+	MaxListVideos  = 500
+)
+
+// NewSchema parses Schema with resolvers calling yt. opts are passed to graphql.ParseSchema,
+// e.g. graphql.MaxDepth to bound the nesting of queries.
+func NewSchema(yt Backend, opts ...graphql.SchemaOpt) (*graphql.Schema, error) {
+	// Sibling fields are resolved concurrently; a higher parallelism lets the loaders batch
+	// whole lists instead of the default ten lookups at a time.
+	opts = append([]graphql.SchemaOpt{graphql.MaxParallelism(MaxListVideos)}, opts...)
+	return graphql.ParseSchema(Schema, &resolver{yt: yt, wait: DefaultBatchWait}, opts...)
+}
+
+// Handler serves schema over HTTP, answering POST requests holding a JSON query, with batching
+// enabled for every request.
+func Handler(schema *graphql.Schema) http.Handler {
+	h := &relay.Handler{Schema: schema}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(WithBatching(r.Context())))
+	})
+}
+
+// resolver is the root resolver of Schema.
+type resolver struct {
+	yt   Backend
+	wait time.Duration
+}
+
+// loaders returns the loaders installed in ctx by WithBatching, or new ones batching nothing
+// beyond a single field when there are none.
+func (r *resolver) loaders(ctx context.Context) *loaders {
+	l, ok := ctx.Value(loadersKey{}).(*loaders)
+	if !ok {
+		l = &loaders{}
+	}
+	l.init(r.yt, r.wait)
+	return l
+}
+
+func (r *resolver) Video(ctx context.Context, args struct{ ID graphql.ID }) (*videoResolver, error) {
+	v, err := r.loaders(ctx).videos.Load(ctx, string(args.ID))
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return &videoResolver{v: v, r: r}, nil
+}
+
+func (r *resolver) Videos(ctx context.Context, args struct{ IDs []graphql.ID }) ([]*videoResolver, error) {
+	if len(args.IDs) > MaxListVideos {
+		return nil, fmt.Errorf("at most %d ids are allowed", MaxListVideos)
+	}
+	ids := make([]string, len(args.IDs))
+	for i, id := range args.IDs {
+		ids[i] = string(id)
+	}
+	videos, err := r.loaders(ctx).videos.LoadMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*videoResolver, len(videos))
+	for i, v := range videos {
+		if v != nil {
+			resolvers[i] = &videoResolver{v: v, r: r}
+		}
+	}
+	return resolvers, nil
+}
+
+func (r *resolver) Channel(ctx context.Context, args struct{ ID graphql.ID }) (*channelResolver, error) {
+	c, err := r.loaders(ctx).channels.Load(ctx, string(args.ID))
+	if err != nil || c == nil {
+		return nil, err
+	}
+	return &channelResolver{c: c, r: r}, nil
+}
+
+func (r *resolver) Playlist(args struct{ ID graphql.ID }) *playlistResolver {
+	return &playlistResolver{id: string(args.ID), r: r}
+}
+
+func (r *resolver) Search(ctx context.Context, args struct {
+	Query      string
+	Pages      int32
+	Order      *string
+	RegionCode *string
+	First      *int32
+}) ([]*videoResolver, error) {
+	if args.Pages < 1 || args.Pages > MaxSearchPages {
+		return nil, fmt.Errorf("pages must be between 1 and %d", MaxSearchPages)
+	}
+	var opts alaitube.SearchOptions
+	if args.Order != nil {
+		opts.Order = *args.Order
+	}
+	if args.RegionCode != nil {
+		opts.RegionCode = *args.RegionCode
+	}
+	if args.First != nil {
+		opts.Limit = int(*args.First)
+	}
+	results, err := r.yt.FindTagsContext(ctx, args.Query, int(args.Pages), opts)
+	if err != nil {
+		return nil, err
+	}
+	return r.videoResolvers(results), nil
+}
+
+// videoResolvers wraps the videos of results.
+func (r *resolver) videoResolvers(results *alaitube.VideoResults) []*videoResolver {
+	resolvers := make([]*videoResolver, len(results.Items))
+	for i, v := range results.Items {
+		resolvers[i] = &videoResolver{v: v, r: r}
+	}
+	return resolvers
+}
+
+// listLength validates the first argument of a list field.
+func listLength(first int32) (int, error) {
+	if first < 1 || first > MaxListVideos {
+		return 0, fmt.Errorf("first must be between 1 and %d", MaxListVideos)
+	}
+	return int(first), nil
+}
+
+// int64Scalar is the Int64 scalar of Schema, serialized as a JSON number.
+type int64Scalar int64
+
+func (int64Scalar) ImplementsGraphQLType(name string) bool {
+	return name == "Int64"
+}
+
+func (n *int64Scalar) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case int32:
+		*n = int64Scalar(input)
+	case int64:
+		*n = int64Scalar(input)
+	case float64:
+		*n = int64Scalar(input)
+	case string:
+		v, err := strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			return err
+		}
+		*n = int64Scalar(v)
+	default:
+		return fmt.Errorf("wrong type for Int64: %T", input)
+	}
+	return nil
+}
+
+func (n int64Scalar) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(n), 10), nil
+}
+
+// count returns n as an Int64.
+func count(n alaitube.Count) *int64Scalar {
+	v := int64Scalar(n)
+	return &v
+}
+
+// optionalTime formats t in RFC 3339, or returns null when it is zero.
+func optionalTime(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339)
+	return &s
+}
+
+// optionalString returns s, or null when it is empty.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// thumbnailUrl returns the URL of the largest of thumbnails, or null when there is none.
+func thumbnailUrl(thumbnails alaitube.Thumbnails) *string {
+	if t := thumbnails.BestAvailable(); t != nil {
+		return &t.Url
+	}
+	return nil
+}
+
+type videoResolver struct {
+	v *alaitube.Video
+	r *resolver
+}
+
+func (v *videoResolver) ID() graphql.ID { return graphql.ID(v.v.Id) }
+func (v *videoResolver) URL() string    { return v.v.Url() }
+
+func (v *videoResolver) Title() string {
+	if v.v.Snippet == nil {
+		return ""
+	}
+	return v.v.Snippet.Title
+}
+
+func (v *videoResolver) Description() string {
+	if v.v.Snippet == nil {
+		return ""
+	}
+	return v.v.Snippet.Description
+}
+
+func (v *videoResolver) PublishedAt() *string {
+	return optionalTime(v.v.PublishedAt())
+}
+
+func (v *videoResolver) Tags() []string {
+	if v.v.Snippet == nil || v.v.Snippet.Tags == nil {
+		return []string{}
+	}
+	return v.v.Snippet.Tags
+}
+
+func (v *videoResolver) DurationSeconds() *int32 {
+	if d := v.v.Duration(); d > 0 {
+		s := int32(d.Seconds())
+		return &s
+	}
+	return nil
+}
+
+func (v *videoResolver) ViewCount() *int64Scalar {
+	if v.v.Statistics == nil {
+		return nil
+	}
+	return count(v.v.Statistics.ViewCount)
+}
+
+func (v *videoResolver) LikeCount() *int64Scalar {
+	if v.v.Statistics == nil {
+		return nil
+	}
+	return count(v.v.Statistics.LikeCount)
+}
+
+func (v *videoResolver) CommentCount() *int64Scalar {
+	if v.v.Statistics == nil {
+		return nil
+	}
+	return count(v.v.Statistics.CommentCount)
+}
+
+func (v *videoResolver) ThumbnailURL() *string {
+	if v.v.Snippet == nil {
+		return nil
+	}
+	return thumbnailUrl(v.v.Snippet.Thumbnails)
+}
+
+// Channel is batched with the channels of the other videos of the query.
+func (v *videoResolver) Channel(ctx context.Context) (*channelResolver, error) {
+	if v.v.Snippet == nil || v.v.Snippet.ChannelId == "" {
+		return nil, nil
+	}
+	c, err := v.r.loaders(ctx).channels.Load(ctx, v.v.Snippet.ChannelId)
+	if err != nil || c == nil {
+		return nil, err
+	}
+	return &channelResolver{c: c, r: v.r}, nil
+}
+
+type channelResolver struct {
+	c *alaitube.Item
+	r *resolver
+}
+
+func (c *channelResolver) ID() graphql.ID { return graphql.ID(c.c.Id) }
+func (c *channelResolver) URL() string    { return c.c.Url() }
+
+func (c *channelResolver) Title() string {
+	if c.c.Snippet == nil {
+		return ""
+	}
+	return c.c.Snippet.Title
+}
+
+func (c *channelResolver) Description() string {
+	if c.c.Snippet == nil {
+		return ""
+	}
+	return c.c.Snippet.Description
+}
+
+func (c *channelResolver) CustomURL() *string {
+	if c.c.Snippet == nil {
+		return nil
+	}
+	return optionalString(c.c.Snippet.CustomUrl)
+}
+
+func (c *channelResolver) Country() *string {
+	if c.c.Snippet == nil {
+		return nil
+	}
+	return optionalString(c.c.Snippet.Country)
+}
+
+func (c *channelResolver) PublishedAt() *string {
+	if c.c.Snippet == nil {
+		return nil
+	}
+	return optionalTime(c.c.Snippet.PublishedAt)
+}
+
+func (c *channelResolver) SubscriberCount() *int64Scalar {
+	st := c.c.Statistics
+	if st == nil || st.HiddenSubscriberCount {
+		return nil
+	}
+	return count(st.SubscriberCount)
+}
+
+func (c *channelResolver) ViewCount() *int64Scalar {
+	if c.c.Statistics == nil {
+		return nil
+	}
+	return count(c.c.Statistics.ViewCount)
+}
+
+func (c *channelResolver) VideoCount() *int64Scalar {
+	if c.c.Statistics == nil {
+		return nil
+	}
+	return count(c.c.Statistics.VideoCount)
+}
+
+func (c *channelResolver) ThumbnailURL() *string {
+	if c.c.Snippet == nil {
+		return nil
+	}
+	return thumbnailUrl(c.c.Snippet.Thumbnails)
+}
+
+func (c *channelResolver) Uploads(ctx context.Context, args struct{ First int32 }) ([]*videoResolver, error) {
+	n, err := listLength(args.First)
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.r.yt.GetChannelPlaylistContext(ctx, c.c, n)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := c.r.videoResolvers(results)
+	// The playlist is fetched by whole pages, which may hold more than n videos.
+	if len(resolvers) > n {
+		resolvers = resolvers[:n]
+	}
+	return resolvers, nil
+}
+
+type playlistResolver struct {
+	id string
+	r  *resolver
+}
+
+func (p *playlistResolver) ID() graphql.ID { return graphql.ID(p.id) }
+
+func (p *playlistResolver) Videos(ctx context.Context, args struct{ First int32 }) ([]*videoResolver, error) {
+	n, err := listLength(args.First)
+	if err != nil {
+		return nil, err
+	}
+	results, err := p.r.yt.GetPlaylistVideosContext(ctx, p.id, n)
+	if err != nil {
+		return nil, err
+	}
+	return p.r.videoResolvers(results), nil
+}
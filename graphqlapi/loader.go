@@ -0,0 +1,129 @@
+package graphqlapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/josephalai/alaitube"
+)
+
+// DefaultBatchWait is how long a loader collects keys before fetching them in a single batch.
+// Resolvers of sibling fields run concurrently, so a short wait is enough to gather a whole list.
+const DefaultBatchWait = 2 * time.Millisecond
+
+// loader batches the lookups made while resolving a query into calls of fetch, in the manner of
+// a dataloader: every key requested within wait of the first is fetched together.
+type loader[T any] struct {
+	fetch func(ctx context.Context, keys []string) (map[string]T, error)
+	wait  time.Duration
+
+	mu      sync.Mutex
+	pending *batch[T]
+}
+
+// batch is a set of keys fetched together. done is closed once results and err are set.
+type batch[T any] struct {
+	keys    []string
+	seen    map[string]bool
+	done    chan struct{}
+	results map[string]T
+	err     error
+}
+
+// Load returns the value of key, or the zero value when fetch didn't return it.
+func (l *loader[T]) Load(ctx context.Context, key string) (T, error) {
+	b := l.enqueue(ctx, key)
+	select {
+	case <-b.done:
+		return b.results[key], b.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// LoadMany returns the values of keys, in order, fetched in as few batches as possible.
+// Values fetch didn't return are zero.
+func (l *loader[T]) LoadMany(ctx context.Context, keys []string) ([]T, error) {
+	batches := make([]*batch[T], len(keys))
+	for i, key := range keys {
+		batches[i] = l.enqueue(ctx, key)
+	}
+	values := make([]T, len(keys))
+	for i, b := range batches {
+		select {
+		case <-b.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if b.err != nil {
+			return nil, b.err
+		}
+		values[i] = b.results[keys[i]]
+	}
+	return values, nil
+}
+
+// enqueue adds key to the pending batch, starting one when there is none.
+func (l *loader[T]) enqueue(ctx context.Context, key string) *batch[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.pending == nil {
+		b := &batch[T]{seen: make(map[string]bool), done: make(chan struct{})}
+		l.pending = b
+		time.AfterFunc(l.wait, func() { l.dispatch(ctx, b) })
+	}
+	b := l.pending
+	if !b.seen[key] {
+		b.seen[key] = true
+		b.keys = append(b.keys, key)
+	}
+	return b
+}
+
+// dispatch fetches the keys of b. Keys requested afterwards start a new batch.
+func (l *loader[T]) dispatch(ctx context.Context, b *batch[T]) {
+	l.mu.Lock()
+	if l.pending == b {
+		l.pending = nil
+	}
+	l.mu.Unlock()
+
+	b.results, b.err = l.fetch(ctx, b.keys)
+	close(b.done)
+}
+
+// loaders holds the loaders of a single query.
+type loaders struct {
+	once     sync.Once
+	videos   *loader[*alaitube.Video]
+	channels *loader[*alaitube.Item]
+}
+
+// init creates the loaders, fetching through yt.
+func (l *loaders) init(yt Backend, wait time.Duration) {
+	l.once.Do(func() {
+		l.videos = &loader[*alaitube.Video]{wait: wait, fetch: func(ctx context.Context, ids []string) (map[string]*alaitube.Video, error) {
+			results, err := yt.GetVideosContext(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			videos := make(map[string]*alaitube.Video, len(results.Items))
+			for _, v := range results.Items {
+				videos[v.Id] = v
+			}
+			return videos, nil
+		}}
+		l.channels = &loader[*alaitube.Item]{wait: wait, fetch: yt.GetChannelsContext}
+	})
+}
+
+type loadersKey struct{}
+
+// WithBatching returns a context batching the video and channel lookups of the queries executed
+// with it. Handler installs it on every request; use it when calling Schema.Exec directly.
+// A context should only be used for a single query, so results aren't shared between requests.
+func WithBatching(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{})
+}
@@ -0,0 +1,292 @@
+// Package grpcserver implements the alaitubepb.YoutubeService gRPC service on top of a
+// YoutubeApi client:
+//
+//	s := grpc.NewServer()
+//	alaitubepb.RegisterYoutubeServiceServer(s, grpcserver.New(yt))
+//	ln, err := net.Listen("tcp", ":9090")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	log.Fatal(s.Serve(ln))
+//
+// Errors are reported with gRPC status codes: NotFound for unknown resources,
+// ResourceExhausted when the quota or rate limit is hit, and InvalidArgument for invalid requests.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/josephalai/alaitube"
+	"github.com/josephalai/alaitube/alaitubepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultMaxSearchPages bounds the pages of a search request when no bound is set.
+const DefaultMaxSearchPages = 5
+
+// maxVideoIds is the number of IDs a GetVideos request accepts, the size of a YouTube batch.
+const maxVideoIds = 50
+
+// Server implements alaitubepb.YoutubeServiceServer.
+type Server struct {
+	alaitubepb.UnimplementedYoutubeServiceServer
+	yt       *alaitube.YoutubeApi
+	maxPages int
+}
+
+// Option configures a Server created with New.
+type Option func(*Server)
+
+// WithMaxSearchPages bounds the pages of Search and SearchStream requests, since each page costs
+// 100 quota units.
+func WithMaxSearchPages(n int) Option {
+	return func(s *Server) {
+		s.maxPages = n
+	}
+}
+
+// New creates a Server answering requests with yt.
+func New(yt *alaitube.YoutubeApi, opts ...Option) *Server {
+	s := &Server{yt: yt, maxPages: DefaultMaxSearchPages}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Search implements alaitubepb.YoutubeServiceServer.
+func (s *Server) Search(ctx context.Context, req *alaitubepb.SearchRequest) (*alaitubepb.SearchResponse, error) {
+	pages, opts, err := s.searchOptions(req)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.yt.FindTagsContext(ctx, req.GetQuery(), pages, opts)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &alaitubepb.SearchResponse{Videos: videoProtos(results.Items)}, nil
+}
+
+// SearchStream implements alaitubepb.YoutubeServiceServer.
+func (s *Server) SearchStream(req *alaitubepb.SearchRequest, stream alaitubepb.YoutubeService_SearchStreamServer) error {
+	pages, opts, err := s.searchOptions(req)
+	if err != nil {
+		return err
+	}
+	// Canceled once the limit is reached, so the search stops requesting pages.
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	videos, errc := s.yt.FindTagsStream(ctx, req.GetQuery(), pages, opts)
+	sent := 0
+	var sendErr error
+	for v := range videos {
+		if sendErr = stream.Send(VideoProto(v)); sendErr != nil {
+			cancel()
+			break
+		}
+		sent++
+		if opts.Limit > 0 && sent == opts.Limit {
+			cancel()
+			break
+		}
+	}
+	for range videos {
+		// Drained so the search goroutine can observe the cancellation and exit.
+	}
+	err = <-errc
+	switch {
+	case sendErr != nil:
+		return sendErr
+	case err != nil && ctx.Err() == nil:
+		return statusError(err)
+	}
+	return nil
+}
+
+// searchOptions validates a search request and converts it to the client's options.
+func (s *Server) searchOptions(req *alaitubepb.SearchRequest) (int, alaitube.SearchOptions, error) {
+	opts := alaitube.SearchOptions{
+		Order:             req.GetOrder(),
+		RegionCode:        req.GetRegionCode(),
+		RelevanceLanguage: req.GetRelevanceLanguage(),
+		VideoDuration:     req.GetVideoDuration(),
+		Limit:             int(req.GetLimit()),
+	}
+	if req.GetQuery() == "" {
+		return 0, opts, status.Error(codes.InvalidArgument, "query is required")
+	}
+	pages := int(req.GetPages())
+	if pages == 0 {
+		pages = 1
+	}
+	if pages < 0 || pages > s.maxPages {
+		return 0, opts, status.Errorf(codes.InvalidArgument, "pages must be between 1 and %d", s.maxPages)
+	}
+	if opts.Limit < 0 {
+		return 0, opts, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+	if req.PublishedAfter != nil {
+		opts.PublishedAfter = req.GetPublishedAfter().AsTime()
+	}
+	if req.PublishedBefore != nil {
+		opts.PublishedBefore = req.GetPublishedBefore().AsTime()
+	}
+	return pages, opts, nil
+}
+
+// GetVideos implements alaitubepb.YoutubeServiceServer.
+func (s *Server) GetVideos(ctx context.Context, req *alaitubepb.GetVideosRequest) (*alaitubepb.GetVideosResponse, error) {
+	ids := req.GetIds()
+	if len(ids) == 0 || len(ids) > maxVideoIds {
+		return nil, status.Errorf(codes.InvalidArgument, "between 1 and %d ids are required", maxVideoIds)
+	}
+	results, err := s.yt.GetVideosContext(ctx, ids)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &alaitubepb.GetVideosResponse{Videos: videoProtos(results.Items)}
+	for _, u := range results.Unavailable {
+		resp.UnavailableIds = append(resp.UnavailableIds, u.VideoId)
+	}
+	return resp, nil
+}
+
+// GetChannel implements alaitubepb.YoutubeServiceServer.
+func (s *Server) GetChannel(ctx context.Context, req *alaitubepb.GetChannelRequest) (*alaitubepb.Channel, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	info, err := s.yt.GetChannelInfoContext(ctx, req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if len(info.Items) == 0 {
+		return nil, status.Errorf(codes.NotFound, "channel %s not found", req.GetId())
+	}
+	return ChannelProto(info.Items[0]), nil
+}
+
+// GetUploads implements alaitubepb.YoutubeServiceServer.
+func (s *Server) GetUploads(req *alaitubepb.GetUploadsRequest, stream alaitubepb.YoutubeService_GetUploadsServer) error {
+	if req.GetChannelId() == "" {
+		return status.Error(codes.InvalidArgument, "channel_id is required")
+	}
+	if req.GetMaxVideos() < 0 {
+		return status.Error(codes.InvalidArgument, "max_videos must not be negative")
+	}
+	limit := int(req.GetMaxVideos())
+	pager := s.yt.ChannelUploads(req.GetChannelId())
+	sent := 0
+	for pager.HasNext() {
+		page, err := pager.NextContext(stream.Context())
+		if errors.Is(err, alaitube.ErrNoMorePages) {
+			break
+		}
+		if err != nil {
+			return statusError(err)
+		}
+		for _, v := range page.Items {
+			if err := stream.Send(VideoProto(v)); err != nil {
+				return err
+			}
+			sent++
+			if limit > 0 && sent == limit {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// statusError converts an error of the client to a gRPC status error.
+func statusError(err error) error {
+	if st := status.FromContextError(err); st.Code() != codes.Unknown {
+		return st.Err()
+	}
+	code := codes.Internal
+	switch {
+	case errors.Is(err, alaitube.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, alaitube.ErrQuotaExceeded), errors.Is(err, alaitube.ErrRateLimited):
+		code = codes.ResourceExhausted
+	default:
+		var apiErr *alaitube.ApiError
+		if errors.As(err, &apiErr) {
+			// Other API errors, such as an invalid key, are failures of the server's configuration.
+			code = codes.Unavailable
+		}
+	}
+	return status.Error(code, err.Error())
+}
+
+// videoProtos converts videos to their messages.
+func videoProtos(videos []*alaitube.Video) []*alaitubepb.Video {
+	out := make([]*alaitubepb.Video, len(videos))
+	for i, v := range videos {
+		out[i] = VideoProto(v)
+	}
+	return out
+}
+
+// VideoProto converts a video to its message.
+func VideoProto(v *alaitube.Video) *alaitubepb.Video {
+	m := &alaitubepb.Video{Id: v.Id, Url: v.Url(), MadeForKids: v.IsMadeForKids(), DurationSeconds: v.DurationSeconds()}
+	if s := v.Snippet; s != nil {
+		m.Title, m.Description = s.Title, s.Description
+		m.ChannelId, m.ChannelTitle = s.ChannelId, s.ChannelTitle
+		m.CategoryId, m.DefaultLanguage = s.CategoryId, s.DefaultLanguage
+		m.PublishedAt = timestamp(s.PublishedAt)
+		m.Tags = s.Tags
+		if t := s.Thumbnails.BestAvailable(); t != nil {
+			m.ThumbnailUrl = t.Url
+		}
+	}
+	if st := v.Statistics; st != nil {
+		m.ViewCount, m.LikeCount, m.CommentCount = int64Ptr(st.ViewCount), int64Ptr(st.LikeCount), int64Ptr(st.CommentCount)
+	}
+	if v.Status != nil {
+		m.PrivacyStatus = v.Status.PrivacyStatus
+	}
+	return m
+}
+
+// ChannelProto converts a channel to its message.
+func ChannelProto(c *alaitube.Item) *alaitubepb.Channel {
+	m := &alaitubepb.Channel{Id: c.Id, Url: c.Url()}
+	if s := c.Snippet; s != nil {
+		m.Title, m.Description = s.Title, s.Description
+		m.CustomUrl, m.Country = s.CustomUrl, s.Country
+		m.PublishedAt = timestamp(s.PublishedAt)
+		if t := s.Thumbnails.BestAvailable(); t != nil {
+			m.ThumbnailUrl = t.Url
+		}
+	}
+	if st := c.Statistics; st != nil {
+		if !st.HiddenSubscriberCount {
+			m.SubscriberCount = int64Ptr(st.SubscriberCount)
+		}
+		m.ViewCount, m.VideoCount = int64Ptr(st.ViewCount), int64Ptr(st.VideoCount)
+	}
+	if c.ContentDetails != nil && c.ContentDetails.RelatedPlaylists != nil {
+		m.UploadsPlaylistId = c.ContentDetails.RelatedPlaylists.Uploads
+	}
+	return m
+}
+
+func int64Ptr(n alaitube.Count) *int64 {
+	v := n.Int64()
+	return &v
+}
+
+// timestamp converts t to a message, or nil when it is zero.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,107 @@
+package alaitube
+
+import "context"
+
+// IncrementalCrawler fetches only the channel uploads and search results published since its
+// previous run. The newest video seen for each channel or search is kept in a MarkStore, so
+// recurring jobs using a FileMarkStore spend quota on new videos only, across restarts.
+// Marks are saved only once a run succeeds, so a failed run is retried in full.
+type IncrementalCrawler struct {
+	yt    *YoutubeApi
+	store MarkStore
+}
+
+// NewIncrementalCrawler creates an IncrementalCrawler keeping its marks in store. A nil store is
+// replaced by a MemoryMarkStore, which doesn't survive restarts.
+func (yt *YoutubeApi) NewIncrementalCrawler(store MarkStore) *IncrementalCrawler {
+	if store == nil {
+		store = NewMemoryMarkStore()
+	}
+	return &IncrementalCrawler{yt: yt, store: store}
+}
+
+// Uploads returns the uploads of a channel published since the previous call, newest first,
+// walking at most maxPages pages of its uploads playlist. The first call returns every upload on
+// those pages. Marks of uploads share the store keys of a Watcher of the same channel.
+func (c *IncrementalCrawler) Uploads(ctx context.Context, channelId string, maxPages int) (_ *VideoResults, err error) {
+	ctx, span := c.yt.startOperation(ctx, "IncrementalCrawler.Uploads")
+	defer func() { endSpan(span, err) }()
+
+	mark, err := c.store.LoadMark(channelId)
+	if err != nil {
+		return nil, err
+	}
+	fresh := &VideoResults{}
+	pager := c.yt.ChannelUploads(channelId)
+	reached := false
+	for i := 0; i < maxPages && pager.HasNext() && !reached; i++ {
+		page, err := pager.NextContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range page.Items {
+			if !newerThanMark(v, mark) {
+				reached = true
+				break
+			}
+			fresh.Items = append(fresh.Items, v)
+		}
+	}
+	return fresh, c.advance(channelId, mark, fresh)
+}
+
+// Search runs FindTags for query restricted to the videos published since the previous call with
+// the same query and options, by setting opts.PublishedAfter to the stored mark. The first call
+// searches with opts unchanged. Results are ordered by date unless opts.Order is set.
+func (c *IncrementalCrawler) Search(ctx context.Context, query string, numPages int, opts SearchOptions) (_ *VideoResults, err error) {
+	ctx, span := c.yt.startOperation(ctx, "IncrementalCrawler.Search")
+	defer func() { endSpan(span, err) }()
+
+	key := "search:" + opts.markKey(query)
+	mark, err := c.store.LoadMark(key)
+	if err != nil {
+		return nil, err
+	}
+	if mark.PublishedAt.After(opts.PublishedAfter) {
+		opts.PublishedAfter = mark.PublishedAt
+	}
+	results, err := c.yt.FindTagsContext(ctx, query, numPages, opts)
+	if err != nil {
+		return nil, err
+	}
+	// publishedAfter is inclusive, so the marked video itself comes back.
+	fresh := results.Filter(func(v *Video) bool { return newerThanMark(v, mark) })
+	return fresh, c.advance(key, mark, fresh)
+}
+
+// Reset forgets the mark of a channel, so the next Uploads call starts over.
+func (c *IncrementalCrawler) Reset(channelId string) error {
+	return c.store.SaveMark(channelId, WatchMark{})
+}
+
+// ResetSearch forgets the mark of a search, so the next Search with query and opts starts over.
+func (c *IncrementalCrawler) ResetSearch(query string, opts SearchOptions) error {
+	return c.store.SaveMark("search:"+opts.markKey(query), WatchMark{})
+}
+
+// newerThanMark reports whether v was published after the marked video.
+func newerThanMark(v *Video, mark WatchMark) bool {
+	if v.Id == mark.VideoId {
+		return false
+	}
+	return mark.PublishedAt.IsZero() || v.PublishedAt().After(mark.PublishedAt)
+}
+
+// advance moves the mark of key to the newest video of fresh, when it is newer than mark.
+func (c *IncrementalCrawler) advance(key string, mark WatchMark, fresh *VideoResults) error {
+	newest := mark
+	for _, v := range fresh.Items {
+		if published := v.PublishedAt(); published.After(newest.PublishedAt) {
+			newest = WatchMark{VideoId: v.Id, PublishedAt: published}
+		}
+	}
+	if newest == mark {
+		return nil
+	}
+	return c.store.SaveMark(key, newest)
+}
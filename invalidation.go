@@ -0,0 +1,185 @@
+package alaitube
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/go-redis/redis"
+)
+
+// DefaultInvalidationChannel is the Redis channel invalidations are published on.
+const DefaultInvalidationChannel = "alaitube:invalidate"
+
+// RedisPubSub is the subset of the Redis client used to broadcast invalidations.
+// *redis.Client satisfies it.
+type RedisPubSub interface {
+	Publish(channel string, message interface{}) *redis.IntCmd
+	Subscribe(channels ...string) *redis.PubSub
+}
+
+// invalidation is the message published for a Delete, PurgeRegion, or PurgeAll.
+type invalidation struct {
+	Origin string      `json:"origin"`
+	Op     string      `json:"op"`
+	Region CacheRegion `json:"region,omitempty"`
+	Key    string      `json:"key,omitempty"`
+}
+
+const (
+	opDelete      = "delete"
+	opPurgeRegion = "purgeRegion"
+	opPurgeAll    = "purgeAll"
+)
+
+// InvalidatingCache wraps an instance-local cache, typically the MemoryCache tier of a
+// TieredCache in front of a shared RedisCache, and keeps it consistent across instances:
+// Delete, PurgeRegion, and PurgeAll are applied locally and published on a Redis channel,
+// and invalidations published by other instances are applied to the local cache.
+//
+// Sets are not broadcast, so another instance may serve a replaced entry from its local
+// cache until it expires; keep the local TTL short.
+type InvalidatingCache struct {
+	Cache
+	client  RedisPubSub
+	channel string
+	origin  string
+	logger  Logger
+
+	pubsub *redis.PubSub
+	done   chan struct{}
+	close  sync.Once
+}
+
+// InvalidatingCacheOption configures an InvalidatingCache created with NewInvalidatingCache.
+type InvalidatingCacheOption func(*InvalidatingCache)
+
+// WithInvalidationChannel sets the Redis channel invalidations are published on.
+// Every instance sharing a cache must use the same channel.
+func WithInvalidationChannel(channel string) InvalidatingCacheOption {
+	return func(c *InvalidatingCache) {
+		c.channel = channel
+	}
+}
+
+// WithInvalidationLogger sets the Logger publish and decode failures are reported to.
+// A nil logger is ignored and the default slog adapter is kept.
+func WithInvalidationLogger(logger Logger) InvalidatingCacheOption {
+	return func(c *InvalidatingCache) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// NewInvalidatingCache wraps local and subscribes to the invalidation channel.
+// Call Close to unsubscribe.
+func NewInvalidatingCache(client RedisPubSub, local Cache, opts ...InvalidatingCacheOption) (*InvalidatingCache, error) {
+	origin := make([]byte, 8)
+	if _, err := rand.Read(origin); err != nil {
+		return nil, err
+	}
+	c := &InvalidatingCache{
+		Cache:   local,
+		client:  client,
+		channel: DefaultInvalidationChannel,
+		origin:  hex.EncodeToString(origin),
+		logger:  NewSlogLogger(nil),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.pubsub = client.Subscribe(c.channel)
+	// Wait for the subscription to be confirmed so no invalidation published after
+	// the constructor returns is missed.
+	if _, err := c.pubsub.Receive(); err != nil {
+		c.pubsub.Close()
+		return nil, fmt.Errorf("failed subscribing to %s: %w", c.channel, err)
+	}
+	go c.listen(c.pubsub.Channel())
+	return c, nil
+}
+
+// listen applies the invalidations published by other instances until the subscription is closed.
+func (c *InvalidatingCache) listen(messages <-chan *redis.Message) {
+	defer close(c.done)
+	for msg := range messages {
+		inv := invalidation{}
+		if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+			c.logger.Warn("invalid cache invalidation", Field{"channel", msg.Channel}, Field{"error", err})
+			continue
+		}
+		if inv.Origin == c.origin {
+			continue
+		}
+		switch inv.Op {
+		case opDelete:
+			c.Cache.Delete(inv.Region, inv.Key)
+		case opPurgeRegion:
+			c.Cache.PurgeRegion(inv.Region)
+		case opPurgeAll:
+			c.Cache.PurgeAll()
+		}
+	}
+}
+
+func (c *InvalidatingCache) publish(inv invalidation) {
+	inv.Origin = c.origin
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return
+	}
+	if err := c.client.Publish(c.channel, data).Err(); err != nil {
+		c.logger.Warn("failed publishing cache invalidation", Field{"op", inv.Op}, Field{"region", inv.Region}, Field{"error", err})
+	}
+}
+
+// Delete removes the entry for key in region locally and on every other instance.
+func (c *InvalidatingCache) Delete(region CacheRegion, key string) {
+	c.Cache.Delete(region, key)
+	c.publish(invalidation{Op: opDelete, Region: region, Key: key})
+}
+
+// PurgeRegion removes every entry of region locally and on every other instance.
+func (c *InvalidatingCache) PurgeRegion(region CacheRegion) {
+	c.Cache.PurgeRegion(region)
+	c.publish(invalidation{Op: opPurgeRegion, Region: region})
+}
+
+// PurgeAll removes every entry locally and on every other instance.
+func (c *InvalidatingCache) PurgeAll() {
+	c.Cache.PurgeAll()
+	c.publish(invalidation{Op: opPurgeAll})
+}
+
+// SetNotFound records key as missing in region when the local cache supports negative caching.
+func (c *InvalidatingCache) SetNotFound(region CacheRegion, key string) {
+	if nc, ok := c.Cache.(NegativeCache); ok {
+		nc.SetNotFound(region, key)
+	}
+}
+
+// IsNotFound reports whether the local cache records key as missing in region.
+func (c *InvalidatingCache) IsNotFound(region CacheRegion, key string) bool {
+	nc, ok := c.Cache.(NegativeCache)
+	return ok && nc.IsNotFound(region, key)
+}
+
+// Close unsubscribes from the invalidation channel. The local cache stays usable but is no
+// longer kept consistent with other instances.
+func (c *InvalidatingCache) Close() error {
+	var err error
+	c.close.Do(func() {
+		err = c.pubsub.Close()
+		<-c.done
+	})
+	return err
+}
+
+var _ Cache = (*InvalidatingCache)(nil)
+var _ NegativeCache = (*InvalidatingCache)(nil)
+var _ RedisPubSub = (*redis.Client)(nil)
@@ -0,0 +1,172 @@
+package alaitube
+
+import (
+	"strings"
+	"unicode"
+)
+
+// LanguageDetector guesses the language of a text. Detect returns an ISO 639-1 code such as
+// "en", or "" when it can't tell, along with a confidence between 0 and 1.
+type LanguageDetector interface {
+	Detect(text string) (language string, confidence float64)
+}
+
+// MinLanguageConfidence is the confidence below which Video.Language ignores a detected language.
+const MinLanguageConfidence = 0.5
+
+// WithLanguageDetector sets the detector used by SearchOptions.Languages for videos that don't
+// declare their language. A nil detector is ignored and the BasicLanguageDetector is kept.
+func WithLanguageDetector(d LanguageDetector) Option {
+	return func(yt *YoutubeApi) {
+		if d != nil {
+			yt.languages = d
+		}
+	}
+}
+
+// BasicLanguageDetector is a dependency-free LanguageDetector. It recognizes languages with their
+// own script, such as Japanese, Korean, Russian, or Arabic, from the characters of the text, and
+// the major Latin-script languages from their most common words. Short texts such as titles give
+// low confidences; plug in a statistical detector for better accuracy.
+type BasicLanguageDetector struct{}
+
+// scriptLanguages maps the scripts used by a single major language to it.
+var scriptLanguages = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Thai, "th"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Bengali, "bn"},
+	{unicode.Tamil, "ta"},
+	{unicode.Arabic, "ar"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Han, "zh"},
+}
+
+// languageStopwords lists frequent words that are rare in the other detected Latin-script languages.
+var languageStopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "with", "you", "this", "that", "how", "what", "for", "of", "to", "in", "my", "your"},
+	"es": {"el", "los", "las", "es", "con", "por", "para", "que", "una", "del", "cómo", "como", "mi", "tu", "y"},
+	"fr": {"le", "les", "est", "avec", "pour", "que", "une", "des", "du", "et", "dans", "comment", "mon", "ton", "sur"},
+	"de": {"der", "die", "das", "und", "ist", "mit", "für", "ein", "eine", "nicht", "wie", "ich", "du", "auf", "zu"},
+	"pt": {"os", "as", "é", "com", "para", "que", "uma", "do", "da", "em", "não", "como", "meu", "seu", "e"},
+	"it": {"il", "gli", "è", "con", "per", "che", "una", "del", "della", "di", "e", "non", "come", "mio", "tuo"},
+	"nl": {"de", "het", "en", "is", "met", "voor", "een", "van", "niet", "hoe", "ik", "je", "op", "te"},
+}
+
+// Detect implements LanguageDetector.
+func (BasicLanguageDetector) Detect(text string) (string, float64) {
+	counts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.table, r) {
+				counts[s.language]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return "", 0
+	}
+	// Kana decide between Japanese and Chinese, which share Han characters.
+	if counts["ja"] > 0 {
+		counts["ja"] += counts["zh"]
+		delete(counts, "zh")
+	}
+	best, bestCount := "", 0
+	for lang, n := range counts {
+		if n > bestCount || (n == bestCount && lang < best) {
+			best, bestCount = lang, n
+		}
+	}
+	if float64(bestCount) > float64(letters)/2 {
+		return best, float64(bestCount) / float64(letters)
+	}
+	return detectByStopwords(text)
+}
+
+// detectByStopwords scores the Latin-script languages by the share of the words of text that are
+// among their stopwords.
+func detectByStopwords(text string) (string, float64) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) == 0 {
+		return "", 0
+	}
+	best, bestHits, total := "", 0, 0
+	for lang, stopwords := range languageStopwords {
+		hits := 0
+		for _, w := range words {
+			if containsFold(stopwords, w) {
+				hits++
+			}
+		}
+		total += hits
+		if hits > bestHits || (hits == bestHits && lang < best) {
+			best, bestHits = lang, hits
+		}
+	}
+	if bestHits == 0 {
+		return "", 0
+	}
+	// Confident when the language's stopwords dominate and are frequent enough in the text.
+	share := float64(bestHits) / float64(total)
+	density := min(1, 4*float64(bestHits)/float64(len(words)))
+	return best, share * density
+}
+
+// Language returns the ISO 639-1 code of the video's language. The language declared by the
+// uploader is preferred; otherwise the title and description are passed to d, and the result is
+// used when its confidence reaches MinLanguageConfidence. It returns "" when the language is unknown.
+func (v *Video) Language(d LanguageDetector) string {
+	if v.Snippet == nil {
+		return ""
+	}
+	for _, declared := range []string{v.Snippet.DefaultLanguage, v.Snippet.DefaultAudioLanguage} {
+		if declared != "" {
+			return primaryLanguage(declared)
+		}
+	}
+	if d == nil {
+		return ""
+	}
+	lang, confidence := d.Detect(v.Snippet.Title + "\n" + v.Snippet.Description)
+	if confidence < MinLanguageConfidence {
+		return ""
+	}
+	return primaryLanguage(lang)
+}
+
+// primaryLanguage returns the primary subtag of a BCP-47 language tag, e.g. "en" for "en-US".
+func primaryLanguage(tag string) string {
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(tag)
+}
+
+// FilterLanguages returns a copy of the results holding only the videos in one of languages, given
+// as ISO 639-1 codes. Videos whose language is unknown are dropped.
+func (r *VideoResults) FilterLanguages(d LanguageDetector, languages ...string) *VideoResults {
+	return r.Filter(func(v *Video) bool {
+		return v.inLanguages(d, languages)
+	})
+}
+
+// inLanguages reports whether the language of v, detected with d, is one of languages.
+func (v *Video) inLanguages(d LanguageDetector, languages []string) bool {
+	lang := v.Language(d)
+	return lang != "" && containsFold(languages, lang)
+}
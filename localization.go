@@ -0,0 +1,56 @@
+package alaitube
+
+import "strings"
+
+// Localization is the title and description of a video or channel in one language.
+type Localization struct {
+	Title       string `bson:"title,omitempty" json:"title,omitempty"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+}
+
+// LocalizedTitle returns the title of the video in language, a BCP-47 code such as "fr". It falls
+// back to the localized snippet requested with Parts.Hl, then to the original title.
+func (v *Video) LocalizedTitle(language string) string {
+	return v.localized(language).Title
+}
+
+// LocalizedDescription is like LocalizedTitle for the description of the video.
+func (v *Video) LocalizedDescription(language string) string {
+	return v.localized(language).Description
+}
+
+// localized returns the metadata of the video in language, falling back as LocalizedTitle does.
+func (v *Video) localized(language string) Localization {
+	if l, ok := v.Localizations[language]; ok {
+		return l
+	}
+	if v.Snippet == nil {
+		return Localization{}
+	}
+	if v.Snippet.Localized != nil {
+		return *v.Snippet.Localized
+	}
+	return Localization{Title: v.Snippet.Title, Description: v.Snippet.Description}
+}
+
+// WithLanguage returns a copy of p requesting the localized snippet in language, a BCP-47 code
+// such as "fr", in Video.Snippet.Localized. Videos without a translation in that language keep
+// their original metadata.
+func (p Parts) WithLanguage(language string) Parts {
+	p.Hl = language
+	return p
+}
+
+// WithLocalizations returns a copy of p also requesting every translation of the metadata, in
+// Video.Localizations.
+func (p Parts) WithLocalizations() Parts {
+	if strings.Contains(p.Part, "localizations") {
+		return p
+	}
+	p.Part += ",localizations"
+	// Fields is an items(...) filter, so the part is added inside it.
+	if strings.HasSuffix(p.Fields, ")") {
+		p.Fields = strings.TrimSuffix(p.Fields, ")") + ",localizations)"
+	}
+	return p
+}
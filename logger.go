@@ -0,0 +1,109 @@
+package alaitube
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Field is a structured key/value pair attached to a log message.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger receives the package's diagnostic messages. Implementations must be safe for concurrent use.
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+}
+
+// NopLogger discards every message.
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...Field) {}
+func (NopLogger) Info(string, ...Field)  {}
+func (NopLogger) Warn(string, ...Field)  {}
+func (NopLogger) Error(string, ...Field) {}
+
+// slogLogger adapts a *slog.Logger to Logger.
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger returns a Logger writing to l, or to slog.Default() when l is nil.
+func NewSlogLogger(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return slogLogger{l: l}
+}
+
+func (s slogLogger) log(level slog.Level, msg string, fields []Field) {
+	attrs := make([]slog.Attr, len(fields))
+	for i, f := range fields {
+		attrs[i] = slog.Any(f.Key, f.Value)
+	}
+	s.l.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func (s slogLogger) Debug(msg string, fields ...Field) { s.log(slog.LevelDebug, msg, fields) }
+func (s slogLogger) Info(msg string, fields ...Field)  { s.log(slog.LevelInfo, msg, fields) }
+func (s slogLogger) Warn(msg string, fields ...Field)  { s.log(slog.LevelWarn, msg, fields) }
+func (s slogLogger) Error(msg string, fields ...Field) { s.log(slog.LevelError, msg, fields) }
+
+// WithLogger sets the Logger the client reports retries and failures to.
+// A nil logger is ignored and the default slog adapter is kept; pass NopLogger{} to silence the client.
+func WithLogger(logger Logger) Option {
+	return func(yt *YoutubeApi) {
+		if logger != nil {
+			yt.logger = logger
+		}
+	}
+}
+
+// redactedParams are the query parameters hidden by RedactURL.
+var redactedParams = []string{"key", "access_token"}
+
+// redactedParamsPattern matches the values of redactedParams in a string that doesn't parse as a URL.
+var redactedParamsPattern = regexp.MustCompile(`([?&](?:` + strings.Join(redactedParams, "|") + `)=)[^&#\s"]*`)
+
+// RedactURL hides credentials passed in the query string of rawUrl so it can be logged safely.
+// In strings that don't parse as a URL, such as URLs built from unescaped IDs, the values of
+// the credential parameters are replaced wherever they appear.
+func RedactURL(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return redactedParamsPattern.ReplaceAllString(rawUrl, "${1}REDACTED")
+	}
+	if u.RawQuery == "" {
+		return rawUrl
+	}
+	q := u.Query()
+	changed := false
+	for _, p := range redactedParams {
+		if q.Get(p) != "" {
+			q.Set(p, "REDACTED")
+			changed = true
+		}
+	}
+	if !changed {
+		return rawUrl
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// redactError hides credentials in the URL of a transport error, which net/http includes in its message.
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = RedactURL(urlErr.URL)
+	}
+	return err
+}
@@ -0,0 +1,302 @@
+// Package mcp exposes search, tag retrieval, and channel statistics as tools for LLM agents.
+//
+// Server implements the Model Context Protocol over stdio, so the client can be added to agents
+// such as Claude Desktop or any other MCP host:
+//
+//	yt := alaitube.NewClient(alaitube.WithApiKey(key), alaitube.WithCache(alaitube.NewMemoryCache()))
+//	if err := mcp.NewServer(yt).Serve(ctx, os.Stdin, os.Stdout); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// The alai-youtube command runs this server with "alai-youtube mcp". For function-calling APIs,
+// pass the name, description, and input schema of each of Toolset.Tools to the model and answer
+// the calls it makes with Toolset.Call:
+//
+//	tools := mcp.NewToolset(yt)
+//	result, err := tools.Call(ctx, call.Name, call.Arguments)
+//
+// The tools are search_videos, get_video_tags, top_tags, and get_channel_stats. Their results are
+// JSON objects described by each tool's output schema.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/josephalai/alaitube"
+)
+
+// ProtocolVersion is the latest MCP revision implemented by Server. Clients requesting one of the
+// earlier revisions in supportedVersions are answered with theirs.
+const ProtocolVersion = "2025-06-18"
+
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Server serves a Toolset with the Model Context Protocol, reading newline-delimited JSON-RPC
+// messages and writing their responses.
+type Server struct {
+	tools   *Toolset
+	name    string
+	version string
+	logger  alaitube.Logger
+}
+
+// Option configures a Server created with NewServer.
+type Option func(*Server)
+
+// WithServerInfo sets the name and version the server reports to clients.
+func WithServerInfo(name, version string) Option {
+	return func(s *Server) {
+		s.name, s.version = name, version
+	}
+}
+
+// WithLogger sets the Logger failed tool calls are reported to.
+// A nil logger is ignored and the default slog adapter is kept.
+func WithLogger(logger alaitube.Logger) Option {
+	return func(s *Server) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
+
+// NewServer creates a Server answering the tools of NewToolset(yt).
+func NewServer(yt Backend, opts ...Option) *Server {
+	s := &Server{
+		tools:   NewToolset(yt),
+		name:    "alai-youtube",
+		version: "1.0.0",
+		logger:  alaitube.NewSlogLogger(nil),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// message is a JSON-RPC request, notification, or response.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// Serve answers the requests read from r on w until r is exhausted or ctx is done. Tool calls run
+// concurrently and are canceled by the client's notifications/cancelled, or when Serve returns.
+// Logs must not be written to w, which is reserved to the protocol.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex // guards writes to w and inflight
+		inflight = make(map[string]context.CancelFunc)
+		wg       sync.WaitGroup
+	)
+	defer wg.Wait()
+	enc := json.NewEncoder(w)
+	reply := func(id json.RawMessage, result interface{}, err error) {
+		resp := message{JSONRPC: "2.0", Id: id, Result: result}
+		if err != nil {
+			var rerr *rpcError
+			if !errors.As(err, &rerr) {
+				rerr = &rpcError{Code: codeInternalError, Message: err.Error()}
+			}
+			resp.Result, resp.Error = nil, rerr
+		} else if result == nil {
+			resp.Result = struct{}{}
+		}
+		if len(id) == 0 {
+			resp.Id = json.RawMessage("null")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if err := enc.Encode(resp); err != nil {
+			s.logger.Error("failed writing response", alaitube.Field{Key: "error", Value: err})
+		}
+	}
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), 16<<20)
+		for sc.Scan() {
+			select {
+			case lines <- append([]byte(nil), sc.Bytes()...):
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- sc.Err()
+		close(lines)
+	}()
+
+	for {
+		var line []byte
+		select {
+		case <-ctx.Done():
+			return nil
+		case l, ok := <-lines:
+			if !ok {
+				if err := <-readErr; err != nil {
+					return fmt.Errorf("failed reading requests: %w", err)
+				}
+				return nil
+			}
+			line = l
+		}
+		if len(line) == 0 {
+			continue
+		}
+		var req message
+		if err := json.Unmarshal(line, &req); err != nil {
+			reply(nil, nil, &rpcError{codeParseError, "parse error: " + err.Error()})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			if req.Method == "" && len(req.Id) > 0 {
+				// A response to a request of ours; the server sends none.
+				continue
+			}
+			reply(req.Id, nil, &rpcError{codeInvalidRequest, "invalid request"})
+			continue
+		}
+		if len(req.Id) == 0 {
+			if req.Method == "notifications/cancelled" {
+				var p struct {
+					RequestId json.RawMessage `json:"requestId"`
+				}
+				if json.Unmarshal(req.Params, &p) == nil {
+					mu.Lock()
+					if cancelCall, ok := inflight[string(p.RequestId)]; ok {
+						cancelCall()
+					}
+					mu.Unlock()
+				}
+			}
+			// Other notifications, such as notifications/initialized, need no action.
+			continue
+		}
+		if req.Method != "tools/call" {
+			result, err := s.handle(req)
+			reply(req.Id, result, err)
+			continue
+		}
+
+		callCtx, cancelCall := context.WithCancel(ctx)
+		mu.Lock()
+		inflight[string(req.Id)] = cancelCall
+		mu.Unlock()
+		wg.Add(1)
+		go func(req message) {
+			defer wg.Done()
+			result, err := s.callTool(callCtx, req.Params)
+			mu.Lock()
+			delete(inflight, string(req.Id))
+			mu.Unlock()
+			// A call canceled by the client expects no response.
+			canceled := callCtx.Err() != nil && ctx.Err() == nil
+			cancelCall()
+			if canceled {
+				return
+			}
+			reply(req.Id, result, err)
+		}(req)
+	}
+}
+
+// handle answers the requests other than tools/call.
+func (s *Server) handle(req message) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &p); err != nil {
+				return nil, &rpcError{codeInvalidParams, "invalid params: " + err.Error()}
+			}
+		}
+		version := ProtocolVersion
+		for _, v := range supportedVersions {
+			if v == p.ProtocolVersion {
+				version = v
+			}
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return nil, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.tools.Tools()}, nil
+	}
+	return nil, &rpcError{codeMethodNotFound, "method not found: " + req.Method}
+}
+
+// toolResult is the result of tools/call. Tool failures are reported in it rather than as
+// JSON-RPC errors, so the model sees them and can correct its arguments.
+type toolResult struct {
+	Content           []textContent `json:"content"`
+	StructuredContent interface{}   `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError,omitempty"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// callTool answers tools/call.
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{codeInvalidParams, "invalid params: " + err.Error()}
+	}
+	result, err := s.tools.Call(ctx, p.Name, p.Arguments)
+	if errors.Is(err, ErrUnknownTool) {
+		return nil, &rpcError{codeInvalidParams, err.Error()}
+	}
+	if err != nil {
+		s.logger.Warn("tool call failed", alaitube.Field{Key: "tool", Value: p.Name}, alaitube.Field{Key: "error", Value: err})
+		return toolResult{Content: []textContent{{"text", err.Error()}}, IsError: true}, nil
+	}
+	text, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed encoding result: %w", err)
+	}
+	return toolResult{Content: []textContent{{"text", string(text)}}, StructuredContent: result}, nil
+}
@@ -0,0 +1,452 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/josephalai/alaitube"
+)
+
+// Backend is the part of the client used by the tools. *alaitube.YoutubeApi implements it.
+type Backend interface {
+	FindTagsContext(ctx context.Context, input string, numPages int, opts ...alaitube.SearchOptions) (*alaitube.VideoResults, error)
+	GetVideosContext(ctx context.Context, videoIds []string) (*alaitube.VideoResults, error)
+	GetChannelsContext(ctx context.Context, channelIds []string) (map[string]*alaitube.Item, error)
+}
+
+var _ Backend = (*alaitube.YoutubeApi)(nil)
+
+// Limits of the tool arguments, bounding the quota a single call can spend.
+const (
+	MaxSearchPages = 3
+	MaxVideoIds    = 50
+	MaxChannelIds  = 50
+)
+
+// ErrUnknownTool is returned by Toolset.Call for a name that isn't one of its tools.
+var ErrUnknownTool = errors.New("unknown tool")
+
+// Tool describes a tool in the shape used by MCP and by the function-calling APIs of LLMs: a
+// name, a description for the model, and JSON schemas of its arguments and result.
+type Tool struct {
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	InputSchema  json.RawMessage `json:"inputSchema"`
+	OutputSchema json.RawMessage `json:"outputSchema,omitempty"`
+
+	call func(ctx context.Context, yt Backend, args json.RawMessage) (interface{}, error)
+}
+
+// Toolset holds the tools answered with a client. It can be served with Server, or its Tools
+// passed to an LLM's function-calling API and the calls the model makes answered with Call.
+type Toolset struct {
+	yt    Backend
+	tools []*Tool
+}
+
+// NewToolset creates the tools answered with yt.
+func NewToolset(yt Backend) *Toolset {
+	return &Toolset{yt: yt, tools: []*Tool{searchVideosTool, getVideoTagsTool, topTagsTool, getChannelStatsTool}}
+}
+
+// Tools returns the descriptions of the tools.
+func (t *Toolset) Tools() []*Tool {
+	return t.tools
+}
+
+// Call runs the tool name with args, a JSON object matching its input schema, and returns its
+// result, which marshals to JSON matching its output schema.
+func (t *Toolset) Call(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	for _, tool := range t.tools {
+		if tool.Name == name {
+			if len(args) == 0 || string(args) == "null" {
+				args = json.RawMessage("{}")
+			}
+			return tool.call(ctx, t.yt, args)
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownTool, name)
+}
+
+// decodeArgs unmarshals args into v, rejecting unknown properties so a model's typos are
+// reported instead of silently ignored.
+func decodeArgs(args json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(strings.NewReader(string(args)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// VideoSummary is the compact form of a video returned by the tools, keeping the fields useful
+// to a model without the thumbnails and localizations of the full resource.
+type VideoSummary struct {
+	Id              string   `json:"id"`
+	Title           string   `json:"title"`
+	ChannelId       string   `json:"channelId,omitempty"`
+	ChannelTitle    string   `json:"channelTitle,omitempty"`
+	PublishedAt     string   `json:"publishedAt,omitempty"`
+	DurationSeconds int64    `json:"durationSeconds,omitempty"`
+	Views           *int64   `json:"views,omitempty"`
+	Likes           *int64   `json:"likes,omitempty"`
+	Comments        *int64   `json:"comments,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Url             string   `json:"url"`
+}
+
+// NewVideoSummary summarizes a video.
+func NewVideoSummary(v *alaitube.Video) VideoSummary {
+	s := VideoSummary{Id: v.Id, Url: v.Url(), DurationSeconds: v.DurationSeconds()}
+	if sn := v.Snippet; sn != nil {
+		s.Title, s.ChannelId, s.ChannelTitle, s.Tags = sn.Title, sn.ChannelId, sn.ChannelTitle, sn.Tags
+	}
+	if t := v.PublishedAt(); !t.IsZero() {
+		s.PublishedAt = t.UTC().Format(time.RFC3339)
+	}
+	if st := v.Statistics; st != nil {
+		s.Views, s.Likes, s.Comments = int64Ptr(st.ViewCount), int64Ptr(st.LikeCount), int64Ptr(st.CommentCount)
+	}
+	return s
+}
+
+// ChannelStats is the compact form of a channel returned by the tools.
+type ChannelStats struct {
+	Id          string `json:"id"`
+	Title       string `json:"title"`
+	CustomUrl   string `json:"customUrl,omitempty"`
+	Country     string `json:"country,omitempty"`
+	CreatedAt   string `json:"createdAt,omitempty"`
+	Subscribers *int64 `json:"subscribers,omitempty"`
+	Views       *int64 `json:"views,omitempty"`
+	Videos      *int64 `json:"videos,omitempty"`
+	// ViewsPerVideo is the average view count of the channel's videos.
+	ViewsPerVideo *float64 `json:"viewsPerVideo,omitempty"`
+	Url           string   `json:"url"`
+}
+
+// NewChannelStats summarizes a channel.
+func NewChannelStats(c *alaitube.Item) ChannelStats {
+	s := ChannelStats{Id: c.Id, Url: c.Url()}
+	if sn := c.Snippet; sn != nil {
+		s.Title, s.CustomUrl, s.Country = sn.Title, sn.CustomUrl, sn.Country
+		if !sn.PublishedAt.IsZero() {
+			s.CreatedAt = sn.PublishedAt.UTC().Format(time.RFC3339)
+		}
+	}
+	if st := c.Statistics; st != nil {
+		if !st.HiddenSubscriberCount {
+			s.Subscribers = int64Ptr(st.SubscriberCount)
+		}
+		s.Views, s.Videos = int64Ptr(st.ViewCount), int64Ptr(st.VideoCount)
+		if st.VideoCount > 0 {
+			avg := float64(st.ViewCount) / float64(st.VideoCount)
+			s.ViewsPerVideo = &avg
+		}
+	}
+	return s
+}
+
+func int64Ptr(n alaitube.Count) *int64 {
+	v := n.Int64()
+	return &v
+}
+
+// videoSummarySchema is the JSON schema of VideoSummary.
+const videoSummarySchema = `{
+	"type": "object",
+	"properties": {
+		"id": {"type": "string"},
+		"title": {"type": "string"},
+		"channelId": {"type": "string"},
+		"channelTitle": {"type": "string"},
+		"publishedAt": {"type": "string", "format": "date-time"},
+		"durationSeconds": {"type": "integer"},
+		"views": {"type": "integer"},
+		"likes": {"type": "integer"},
+		"comments": {"type": "integer"},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"url": {"type": "string"}
+	},
+	"required": ["id", "title", "url"]
+}`
+
+var searchVideosTool = &Tool{
+	Name: "search_videos",
+	Description: "Search YouTube videos matching a query. Returns each video's title, channel, publication date, " +
+		"duration, view, like, and comment counts, and tags. Each page holds up to 50 videos and costs 100 quota units.",
+	InputSchema: json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"query": {"type": "string", "description": "Search terms."},
+		"pages": {"type": "integer", "minimum": 1, "maximum": ` + fmt.Sprint(MaxSearchPages) + `, "default": 1},
+		"order": {"type": "string", "enum": ["relevance", "date", "viewCount", "rating", "title"], "default": "relevance"},
+		"regionCode": {"type": "string", "description": "ISO 3166-1 alpha-2 country code to search in."},
+		"publishedAfter": {"type": "string", "format": "date-time", "description": "Only videos published after this RFC 3339 time."},
+		"limit": {"type": "integer", "minimum": 1, "description": "Maximum number of videos to return."}
+	},
+	"required": ["query"],
+	"additionalProperties": false
+}`),
+	OutputSchema: json.RawMessage(`{
+	"type": "object",
+	"properties": {"videos": {"type": "array", "items": ` + videoSummarySchema + `}},
+	"required": ["videos"]
+}`),
+	call: func(ctx context.Context, yt Backend, raw json.RawMessage) (interface{}, error) {
+		var args struct {
+			Query          string    `json:"query"`
+			Pages          int       `json:"pages"`
+			Order          string    `json:"order"`
+			RegionCode     string    `json:"regionCode"`
+			PublishedAfter time.Time `json:"publishedAfter"`
+			Limit          int       `json:"limit"`
+		}
+		if err := decodeArgs(raw, &args); err != nil {
+			return nil, err
+		}
+		results, err := search(ctx, yt, args.Query, args.Pages, alaitube.SearchOptions{
+			Order:          defaultString(args.Order, alaitube.OrderRelevance),
+			RegionCode:     args.RegionCode,
+			PublishedAfter: args.PublishedAfter,
+			Limit:          args.Limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		videos := make([]VideoSummary, len(results.Items))
+		for i, v := range results.Items {
+			videos[i] = NewVideoSummary(v)
+		}
+		return map[string]interface{}{"videos": videos}, nil
+	},
+}
+
+// search validates the common search arguments and runs the search.
+func search(ctx context.Context, yt Backend, query string, pages int, opts alaitube.SearchOptions) (*alaitube.VideoResults, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("query is required")
+	}
+	if pages == 0 {
+		pages = 1
+	}
+	if pages < 1 || pages > MaxSearchPages {
+		return nil, fmt.Errorf("pages must be between 1 and %d", MaxSearchPages)
+	}
+	if opts.Limit < 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	return yt.FindTagsContext(ctx, query, pages, opts)
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+var getVideoTagsTool = &Tool{
+	Name:        "get_video_tags",
+	Description: "Get the tags of YouTube videos by ID, along with their titles and hashtags. Costs 1 quota unit per 50 videos.",
+	InputSchema: json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"videoIds": {"type": "array", "items": {"type": "string"}, "minItems": 1, "maxItems": ` + fmt.Sprint(MaxVideoIds) + `}
+	},
+	"required": ["videoIds"],
+	"additionalProperties": false
+}`),
+	OutputSchema: json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"videos": {"type": "array", "items": {
+			"type": "object",
+			"properties": {
+				"id": {"type": "string"},
+				"title": {"type": "string"},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"hashtags": {"type": "array", "items": {"type": "string"}}
+			},
+			"required": ["id", "title", "tags"]
+		}},
+		"notFound": {"type": "array", "items": {"type": "string"}}
+	},
+	"required": ["videos"]
+}`),
+	call: func(ctx context.Context, yt Backend, raw json.RawMessage) (interface{}, error) {
+		var args struct {
+			VideoIds []string `json:"videoIds"`
+		}
+		if err := decodeArgs(raw, &args); err != nil {
+			return nil, err
+		}
+		if len(args.VideoIds) == 0 || len(args.VideoIds) > MaxVideoIds {
+			return nil, fmt.Errorf("between 1 and %d videoIds are required", MaxVideoIds)
+		}
+		results, err := yt.GetVideosContext(ctx, args.VideoIds)
+		if err != nil {
+			return nil, err
+		}
+		type videoTags struct {
+			Id       string   `json:"id"`
+			Title    string   `json:"title"`
+			Tags     []string `json:"tags"`
+			Hashtags []string `json:"hashtags,omitempty"`
+		}
+		out := struct {
+			Videos   []videoTags `json:"videos"`
+			NotFound []string    `json:"notFound,omitempty"`
+		}{Videos: []videoTags{}}
+		found := make(map[string]bool)
+		for _, v := range results.Items {
+			found[v.Id] = true
+			vt := videoTags{Id: v.Id, Tags: []string{}, Hashtags: v.Hashtags()}
+			if v.Snippet != nil {
+				vt.Title = v.Snippet.Title
+				if v.Snippet.Tags != nil {
+					vt.Tags = v.Snippet.Tags
+				}
+			}
+			out.Videos = append(out.Videos, vt)
+		}
+		for _, id := range args.VideoIds {
+			if !found[id] {
+				out.NotFound = append(out.NotFound, id)
+			}
+		}
+		return out, nil
+	},
+}
+
+var topTagsTool = &Tool{
+	Name: "top_tags",
+	Description: "Find the tags most used by the popular YouTube videos matching a query, ranked by a view-weighted score. " +
+		"Useful to pick tags for a new video about a topic. Each page of videos searched costs 100 quota units.",
+	InputSchema: json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"query": {"type": "string", "description": "Topic or keyword."},
+		"pages": {"type": "integer", "minimum": 1, "maximum": ` + fmt.Sprint(MaxSearchPages) + `, "default": 1},
+		"limit": {"type": "integer", "minimum": 1, "maximum": 100, "default": 20, "description": "Number of tags to return."}
+	},
+	"required": ["query"],
+	"additionalProperties": false
+}`),
+	OutputSchema: json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"videosAnalyzed": {"type": "integer"},
+		"tags": {"type": "array", "items": {
+			"type": "object",
+			"properties": {
+				"tag": {"type": "string"},
+				"videos": {"type": "integer", "description": "Number of videos using the tag."},
+				"views": {"type": "integer", "description": "Total views of the videos using the tag."},
+				"score": {"type": "number"}
+			},
+			"required": ["tag", "videos", "views", "score"]
+		}}
+	},
+	"required": ["videosAnalyzed", "tags"]
+}`),
+	call: func(ctx context.Context, yt Backend, raw json.RawMessage) (interface{}, error) {
+		var args struct {
+			Query string `json:"query"`
+			Pages int    `json:"pages"`
+			Limit int    `json:"limit"`
+		}
+		if err := decodeArgs(raw, &args); err != nil {
+			return nil, err
+		}
+		if args.Limit == 0 {
+			args.Limit = 20
+		}
+		if args.Limit < 1 || args.Limit > 100 {
+			return nil, errors.New("limit must be between 1 and 100")
+		}
+		results, err := search(ctx, yt, args.Query, args.Pages, alaitube.SearchOptions{Order: alaitube.OrderRelevance})
+		if err != nil {
+			return nil, err
+		}
+		type tagScore struct {
+			Tag    string  `json:"tag"`
+			Videos int     `json:"videos"`
+			Views  int64   `json:"views"`
+			Score  float64 `json:"score"`
+		}
+		tags := []tagScore{}
+		for _, s := range alaitube.NewTagAnalyzer().Analyze(results).TopByScore(args.Limit) {
+			tags = append(tags, tagScore{s.Tag, s.Count, s.Views, math.Round(s.Score*100) / 100})
+		}
+		return map[string]interface{}{"videosAnalyzed": len(results.Items), "tags": tags}, nil
+	},
+}
+
+var getChannelStatsTool = &Tool{
+	Name:        "get_channel_stats",
+	Description: "Get the statistics of YouTube channels by ID: subscribers, total views, video count, average views per video, country, and creation date. Costs 1 quota unit per 50 channels.",
+	InputSchema: json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"channelIds": {"type": "array", "items": {"type": "string", "pattern": "^UC"}, "minItems": 1, "maxItems": ` + fmt.Sprint(MaxChannelIds) + `}
+	},
+	"required": ["channelIds"],
+	"additionalProperties": false
+}`),
+	OutputSchema: json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"channels": {"type": "array", "items": {
+			"type": "object",
+			"properties": {
+				"id": {"type": "string"},
+				"title": {"type": "string"},
+				"customUrl": {"type": "string"},
+				"country": {"type": "string"},
+				"createdAt": {"type": "string", "format": "date-time"},
+				"subscribers": {"type": "integer", "description": "Absent when the channel hides it."},
+				"views": {"type": "integer"},
+				"videos": {"type": "integer"},
+				"viewsPerVideo": {"type": "number"},
+				"url": {"type": "string"}
+			},
+			"required": ["id", "title", "url"]
+		}},
+		"notFound": {"type": "array", "items": {"type": "string"}}
+	},
+	"required": ["channels"]
+}`),
+	call: func(ctx context.Context, yt Backend, raw json.RawMessage) (interface{}, error) {
+		var args struct {
+			ChannelIds []string `json:"channelIds"`
+		}
+		if err := decodeArgs(raw, &args); err != nil {
+			return nil, err
+		}
+		if len(args.ChannelIds) == 0 || len(args.ChannelIds) > MaxChannelIds {
+			return nil, fmt.Errorf("between 1 and %d channelIds are required", MaxChannelIds)
+		}
+		channels, err := yt.GetChannelsContext(ctx, args.ChannelIds)
+		if err != nil {
+			return nil, err
+		}
+		out := struct {
+			Channels []ChannelStats `json:"channels"`
+			NotFound []string       `json:"notFound,omitempty"`
+		}{Channels: []ChannelStats{}}
+		for _, id := range args.ChannelIds {
+			if c, ok := channels[id]; ok {
+				out.Channels = append(out.Channels, NewChannelStats(c))
+			} else {
+				out.NotFound = append(out.NotFound, id)
+			}
+		}
+		return out, nil
+	},
+}
@@ -0,0 +1,222 @@
+// Package memcached provides a cache backend built on memcached, for teams that already run
+// memcached and don't want to add Redis.
+//
+//	store := memcached.New(memcache.New("localhost:11211"), memcached.WithTTL(6*time.Hour))
+//	client := alaitube.NewClient(alaitube.WithApiKey(key), alaitube.WithCache(store.Cache()))
+package memcached
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/josephalai/alaitube"
+)
+
+// DefaultTTL is how long entries live when no TTL is configured for a region.
+const DefaultTTL = 24 * time.Hour
+
+// DefaultKeyPrefix is prepended to every key written by a Store.
+const DefaultKeyPrefix = "alaitube:"
+
+// maxRelativeExpiration is the longest expiration memcached accepts as relative seconds;
+// longer expirations must be sent as Unix timestamps.
+const maxRelativeExpiration = 30 * 24 * time.Hour
+
+// Client is the subset of *memcache.Client used by Store.
+type Client interface {
+	GetMulti(keys []string) (map[string]*memcache.Item, error)
+	Set(item *memcache.Item) error
+	Add(item *memcache.Item) error
+	Delete(key string) error
+	Increment(key string, delta uint64) (uint64, error)
+}
+
+// Store is an alaitube.CacheV2 backed by memcached.
+//
+// Memcached can't enumerate keys, so purging bumps a generation counter that is part of every
+// key: entries written under an older generation are never read again and are left for
+// memcached to evict. Keys are hashed, since cache keys may exceed memcached's 250 byte limit
+// or contain spaces.
+type Store struct {
+	client Client
+	prefix string
+	ttl    time.Duration
+	ttls   map[alaitube.CacheRegion]time.Duration
+}
+
+// Option configures a Store created with New.
+type Option func(*Store)
+
+// WithKeyPrefix sets the prefix prepended to every key.
+func WithKeyPrefix(prefix string) Option {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// WithTTL sets the entry lifetime for every region without a region TTL.
+// A zero TTL stores entries without expiration.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Store) {
+		s.ttl = ttl
+	}
+}
+
+// WithRegionTTL sets the entry lifetime for a single region.
+func WithRegionTTL(region alaitube.CacheRegion, ttl time.Duration) Option {
+	return func(s *Store) {
+		s.ttls[region] = ttl
+	}
+}
+
+// New creates a Store using the given client, typically a *memcache.Client.
+func New(client Client, opts ...Option) *Store {
+	s := &Store{
+		client: client,
+		prefix: DefaultKeyPrefix,
+		ttl:    DefaultTTL,
+		ttls:   make(map[alaitube.CacheRegion]time.Duration),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Cache returns an alaitube.Cache backed by the store, to pass to alaitube.WithCache.
+func (s *Store) Cache(opts ...alaitube.CacheV2AdapterOption) *alaitube.CacheV2Adapter {
+	opts = append([]alaitube.CacheV2AdapterOption{alaitube.WithAdapterName("memcached-cache")}, opts...)
+	return alaitube.NewCacheV2Adapter(s, opts...)
+}
+
+func (s *Store) allGenerationKey() string {
+	return s.prefix + "gen"
+}
+
+func (s *Store) regionGenerationKey(region alaitube.CacheRegion) string {
+	return s.prefix + "gen:" + string(region)
+}
+
+// key returns the memcached key of key in region under the current generations.
+func (s *Store) key(region alaitube.CacheRegion, key string) (string, error) {
+	all, regionKey := s.allGenerationKey(), s.regionGenerationKey(region)
+	gens, err := s.client.GetMulti([]string{all, regionKey})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(key))
+	return s.prefix + string(region) + ":" + generation(gens[all]) + "." + generation(gens[regionKey]) + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// generation returns the value of a generation counter, "0" when it doesn't exist yet.
+func generation(item *memcache.Item) string {
+	if item == nil {
+		return "0"
+	}
+	return string(item.Value)
+}
+
+// bump increments a generation counter, creating it when missing.
+func (s *Store) bump(key string) error {
+	_, err := s.client.Increment(key, 1)
+	if !errors.Is(err, memcache.ErrCacheMiss) {
+		return err
+	}
+	err = s.client.Add(&memcache.Item{Key: key, Value: []byte("1")})
+	if errors.Is(err, memcache.ErrNotStored) {
+		// Another instance created the counter first.
+		_, err = s.client.Increment(key, 1)
+	}
+	return err
+}
+
+// expiration converts ttl to memcached's expiration format.
+func expiration(ttl time.Duration) int32 {
+	if ttl <= 0 {
+		return 0
+	}
+	if ttl > maxRelativeExpiration {
+		return int32(time.Now().Add(ttl).Unix())
+	}
+	if ttl < time.Second {
+		return 1
+	}
+	return int32(ttl / time.Second)
+}
+
+// Get returns the value of key in region. The memcached client doesn't take a context,
+// so ctx is only checked before the call.
+func (s *Store) Get(ctx context.Context, region alaitube.CacheRegion, key string) ([]byte, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	k, err := s.key(region, key)
+	if err != nil {
+		return nil, false, err
+	}
+	items, err := s.client.GetMulti([]string{k})
+	if err != nil {
+		return nil, false, err
+	}
+	item, ok := items[k]
+	if !ok {
+		return nil, false, nil
+	}
+	return item.Value, true, nil
+}
+
+// Set stores value under key in region. A zero ttl uses the region's TTL.
+func (s *Store) Set(ctx context.Context, region alaitube.CacheRegion, key string, value []byte, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ttl == 0 {
+		ttl = s.ttl
+		if regionTTL, ok := s.ttls[region]; ok {
+			ttl = regionTTL
+		}
+	}
+	k, err := s.key(region, key)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(&memcache.Item{Key: k, Value: value, Expiration: expiration(ttl)})
+}
+
+// Delete removes the entry for key in region.
+func (s *Store) Delete(ctx context.Context, region alaitube.CacheRegion, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	k, err := s.key(region, key)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Delete(k); err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+		return err
+	}
+	return nil
+}
+
+// PurgeRegion invalidates every entry of region.
+func (s *Store) PurgeRegion(ctx context.Context, region alaitube.CacheRegion) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.bump(s.regionGenerationKey(region))
+}
+
+// PurgeAll invalidates every entry written with the store's key prefix.
+func (s *Store) PurgeAll(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.bump(s.allGenerationKey())
+}
+
+var _ alaitube.CacheV2 = (*Store)(nil)
+var _ Client = (*memcache.Client)(nil)
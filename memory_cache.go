@@ -1,80 +1,390 @@
 package alaitube
 
 import (
+	"container/list"
 	"sync"
+	"time"
 )
 
+// DefaultMemoryTTL is how long MemoryCache entries live when no TTL is configured for a region.
+const DefaultMemoryTTL = time.Hour
+
+// DefaultMemoryMaxEntries is the default number of entries each MemoryCache region holds
+// before the least recently used entry is evicted.
+const DefaultMemoryMaxEntries = 1000
+
 type MemoryCache struct {
-	videoCache        map[string]*VideoResults
-	channelCache      map[string]*ChannelInfo
-	playlistCache     map[string]*VideoResults
-	videoDetailsCache map[string]*VideoResults
+	regions     map[CacheRegion]*memoryRegion
+	negativeTTL time.Duration
+	now         func() time.Time
 	sync.Mutex
 }
 
-func NewMemoryCache() *MemoryCache {
-	return &MemoryCache{
-		videoCache:        make(map[string]*VideoResults),
-		channelCache:      make(map[string]*ChannelInfo),
-		playlistCache:     make(map[string]*VideoResults),
-		videoDetailsCache: make(map[string]*VideoResults),
+// notFoundEntry is the value of a negative cache entry.
+type notFoundEntry struct{}
+
+// memoryRegion is a single LRU-ordered region of a MemoryCache.
+// The front of order holds the most recently used entry.
+type memoryRegion struct {
+	ttl        time.Duration
+	maxEntries int
+	items      map[string]*list.Element
+	order      *list.List
+
+	hits, misses int64
+	// bytes is the sum of the estimated sizes of the measured entries.
+	bytes int64
+}
+
+type memoryEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+	// size estimates the memory held by the entry. It is -1 until Stats measures the entry.
+	size int64
+}
+
+// MemoryCacheOption configures a MemoryCache created with NewMemoryCache.
+type MemoryCacheOption func(*MemoryCache)
+
+// WithMemoryTTL sets the entry lifetime for every region.
+// A zero TTL keeps entries until they are evicted or purged.
+func WithMemoryTTL(ttl time.Duration) MemoryCacheOption {
+	return func(c *MemoryCache) {
+		for _, r := range c.regions {
+			r.ttl = ttl
+		}
 	}
 }
 
-// GetVideo retrieves a video from Cache.
-func (c *MemoryCache) GetVideo(key string) *VideoResults {
+// WithMemoryRegionTTL sets the entry lifetime for a single region.
+func WithMemoryRegionTTL(region CacheRegion, ttl time.Duration) MemoryCacheOption {
+	return func(c *MemoryCache) {
+		if r, ok := c.regions[region]; ok {
+			r.ttl = ttl
+		}
+	}
+}
+
+// WithMaxEntries caps the number of entries held by each region.
+// A cap of zero or less leaves the regions unbounded.
+func WithMaxEntries(maxEntries int) MemoryCacheOption {
+	return func(c *MemoryCache) {
+		for _, r := range c.regions {
+			r.maxEntries = maxEntries
+		}
+	}
+}
+
+// WithMemoryNegativeTTL sets how long not-found results are remembered.
+func WithMemoryNegativeTTL(ttl time.Duration) MemoryCacheOption {
+	return func(c *MemoryCache) {
+		c.negativeTTL = ttl
+	}
+}
+
+func NewMemoryCache(opts ...MemoryCacheOption) *MemoryCache {
+	c := &MemoryCache{
+		regions:     make(map[CacheRegion]*memoryRegion),
+		negativeTTL: DefaultNegativeTTL,
+		now:         time.Now,
+	}
+	for _, region := range cacheRegions {
+		c.regions[region] = &memoryRegion{
+			ttl:        DefaultMemoryTTL,
+			maxEntries: DefaultMemoryMaxEntries,
+			items:      make(map[string]*list.Element),
+			order:      list.New(),
+		}
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// get returns the live entry for key in region, marking it as recently used, and counts the
+// lookup as a hit or a miss. Expired entries are removed and reported as missing.
+func (c *MemoryCache) get(region CacheRegion, key string) interface{} {
 	c.Lock()
 	defer c.Unlock()
-	return c.videoCache[key]
+	v := c.lookup(region, key)
+	if _, notFound := v.(notFoundEntry); v != nil && !notFound {
+		c.regions[region].hits++
+	} else {
+		c.regions[region].misses++
+	}
+	return v
 }
 
-// SetVideo stores a video to Cache.
-func (c *MemoryCache) SetVideo(key string, video *VideoResults) {
+// lookup is like get but doesn't count the lookup. c must be locked.
+func (c *MemoryCache) lookup(region CacheRegion, key string) interface{} {
+	r := c.regions[region]
+	el, ok := r.items[key]
+	if !ok {
+		return nil
+	}
+	entry := el.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && c.now().After(entry.expiresAt) {
+		r.remove(el)
+		return nil
+	}
+	r.order.MoveToFront(el)
+	return entry.value
+}
+
+// set stores value under key in region, evicting the least recently used entries
+// once the region exceeds its cap.
+func (c *MemoryCache) set(region CacheRegion, key string, value interface{}) {
+	c.setWithTTL(region, key, value, c.regions[region].ttl)
+}
+
+// setWithTTL is like set but uses ttl instead of the region's TTL.
+func (c *MemoryCache) setWithTTL(region CacheRegion, key string, value interface{}, ttl time.Duration) {
 	c.Lock()
 	defer c.Unlock()
-	c.videoCache[key] = video
+	r := c.regions[region]
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+	entry := &memoryEntry{key: key, value: value, expiresAt: expiresAt, size: -1}
+	if el, ok := r.items[key]; ok {
+		// Replaced rather than updated, so a measure in progress notices the change.
+		r.unmeasure(el.Value.(*memoryEntry))
+		el.Value = entry
+		r.order.MoveToFront(el)
+		return
+	}
+	r.items[key] = r.order.PushFront(entry)
+	for r.maxEntries > 0 && r.order.Len() > r.maxEntries {
+		r.remove(r.order.Back())
+	}
 }
 
-// GetChannel retrieves a channel from Cache.
-func (c *MemoryCache) GetChannel(key string) *ChannelInfo {
+func (r *memoryRegion) remove(el *list.Element) {
+	r.order.Remove(el)
+	entry := el.Value.(*memoryEntry)
+	delete(r.items, entry.key)
+	r.unmeasure(entry)
+}
+
+// unmeasure drops the size of entry, if it was measured, from the region's bytes.
+func (r *memoryRegion) unmeasure(entry *memoryEntry) {
+	if entry.size >= 0 {
+		r.bytes -= entry.size
+	}
+}
+
+// Expire removes every entry whose TTL has elapsed and returns how many were removed.
+// Expired entries are also dropped lazily on access, so calling Expire is only needed to reclaim memory.
+func (c *MemoryCache) Expire() int {
 	c.Lock()
 	defer c.Unlock()
-	return c.channelCache[key]
+	now := c.now()
+	removed := 0
+	for _, r := range c.regions {
+		for el := r.order.Front(); el != nil; {
+			next := el.Next()
+			entry := el.Value.(*memoryEntry)
+			if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+				r.remove(el)
+				removed++
+			}
+			el = next
+		}
+	}
+	return removed
 }
 
-// SetChannel stores a channel to Cache.
-func (c *MemoryCache) SetChannel(key string, channel *ChannelInfo) {
+// Purge removes every entry from every region.
+//
+// Deprecated: use PurgeAll, which is part of the Cache interface.
+func (c *MemoryCache) Purge() {
+	c.PurgeAll()
+}
+
+// Delete removes the entry for key in region.
+func (c *MemoryCache) Delete(region CacheRegion, key string) {
 	c.Lock()
 	defer c.Unlock()
-	c.channelCache[key] = channel
+	r := c.regions[region]
+	if r == nil {
+		return
+	}
+	if el, ok := r.items[key]; ok {
+		r.remove(el)
+	}
 }
 
-// GetPlaylist retrieves a playlist from Cache.
-func (c *MemoryCache) GetPlaylist(key string) *VideoResults {
+// PurgeRegion removes every entry of region.
+func (c *MemoryCache) PurgeRegion(region CacheRegion) {
 	c.Lock()
 	defer c.Unlock()
-	return c.playlistCache[key]
+	if r := c.regions[region]; r != nil {
+		r.clear()
+	}
 }
 
-// SetPlaylist stores a playlist to Cache.
-func (c *MemoryCache) SetPlaylist(key string, playlist *VideoResults) {
+// PurgeAll removes every entry from every region.
+func (c *MemoryCache) PurgeAll() {
 	c.Lock()
 	defer c.Unlock()
-	c.playlistCache[key] = playlist
+	for _, r := range c.regions {
+		r.clear()
+	}
 }
 
-// GetVideoDetail retrieves a VideoDetail from Cache.
-func (c *MemoryCache) GetVideoDetail(key string) *VideoResults {
+func (r *memoryRegion) clear() {
+	r.items = make(map[string]*list.Element)
+	r.order.Init()
+	r.bytes = 0
+}
+
+// Stats returns the lookups and the size of every region. Bytes estimates the memory held by the
+// values from the size of their JSON encoding, computed by Stats for the entries set since its
+// last call, so that setting entries stays cheap.
+func (c *MemoryCache) Stats() CacheStats {
+	c.measure()
+	c.Lock()
+	defer c.Unlock()
+	stats := CacheStats{Name: c.GetServiceName(), Regions: make(map[CacheRegion]RegionStats, len(c.regions))}
+	for region, r := range c.regions {
+		stats.Regions[region] = RegionStats{Hits: r.hits, Misses: r.misses, Entries: int64(len(r.items)), Bytes: r.bytes}
+	}
+	return stats
+}
+
+// pendingSize is an entry to measure, along with the region holding it.
+type pendingSize struct {
+	region *memoryRegion
+	entry  *memoryEntry
+	size   int64
+}
+
+// measure estimates the size of the entries that weren't measured yet. The values are encoded
+// outside the lock, since encoding large results takes a while.
+func (c *MemoryCache) measure() {
+	c.Lock()
+	var pending []pendingSize
+	for _, r := range c.regions {
+		for _, el := range r.items {
+			if entry := el.Value.(*memoryEntry); entry.size < 0 {
+				pending = append(pending, pendingSize{region: r, entry: entry})
+			}
+		}
+	}
+	c.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	for i, p := range pending {
+		pending[i].size = int64(len(p.entry.key)) + jsonSize(p.entry.value)
+	}
+
 	c.Lock()
 	defer c.Unlock()
-	return c.videoDetailsCache[key]
+	for _, p := range pending {
+		// Skipped when the entry was removed, replaced, or measured by another call meanwhile.
+		if el, ok := p.region.items[p.entry.key]; !ok || el.Value != p.entry || p.entry.size >= 0 {
+			continue
+		}
+		p.entry.size = p.size
+		p.region.bytes += p.size
+	}
 }
 
-// SetVideoDetail stores a VideoDetail to Cache.
-func (c *MemoryCache) SetVideoDetail(key string, detail *VideoResults) {
+// SetNotFound records key as missing in region for the negative TTL.
+func (c *MemoryCache) SetNotFound(region CacheRegion, key string) {
+	if c.negativeTTL > 0 {
+		c.setWithTTL(region, key, notFoundEntry{}, c.negativeTTL)
+	}
+}
+
+// IsNotFound reports whether key is recorded as missing in region.
+func (c *MemoryCache) IsNotFound(region CacheRegion, key string) bool {
 	c.Lock()
 	defer c.Unlock()
-	c.videoDetailsCache[key] = detail
+	_, ok := c.lookup(region, key).(notFoundEntry)
+	return ok
+}
+
+// GetVideo retrieves a video from Cache.
+func (c *MemoryCache) GetVideo(key string) *VideoResults {
+	v, _ := c.get(RegionVideos, key).(*VideoResults)
+	return v
+}
+
+// SetVideo stores a video to Cache.
+func (c *MemoryCache) SetVideo(key string, video *VideoResults) {
+	c.set(RegionVideos, key, video)
+}
+
+// GetChannel retrieves a channel from Cache.
+func (c *MemoryCache) GetChannel(key string) *ChannelInfo {
+	v, _ := c.get(RegionChannels, key).(*ChannelInfo)
+	return v
+}
+
+// SetChannel stores a channel to Cache.
+func (c *MemoryCache) SetChannel(key string, channel *ChannelInfo) {
+	c.set(RegionChannels, key, channel)
+}
+
+// GetPlaylist retrieves a playlist from Cache.
+func (c *MemoryCache) GetPlaylist(key string) *VideoResults {
+	v, _ := c.get(RegionPlaylists, key).(*VideoResults)
+	return v
+}
+
+// SetPlaylist stores a playlist to Cache.
+func (c *MemoryCache) SetPlaylist(key string, playlist *VideoResults) {
+	c.set(RegionPlaylists, key, playlist)
+}
+
+// GetVideoDetail retrieves a VideoDetail from Cache.
+func (c *MemoryCache) GetVideoDetail(key string) *VideoResults {
+	v, _ := c.get(RegionVideoDetails, key).(*VideoResults)
+	return v
+}
+
+// SetVideoDetail stores a VideoDetail to Cache.
+func (c *MemoryCache) SetVideoDetail(key string, detail *VideoResults) {
+	c.set(RegionVideoDetails, key, detail)
+}
+
+// GetCommentThreads retrieves comment threads from Cache.
+func (c *MemoryCache) GetCommentThreads(key string) *CommentThreadResults {
+	v, _ := c.get(RegionComments, key).(*CommentThreadResults)
+	return v
+}
+
+// SetCommentThreads stores comment threads to Cache.
+func (c *MemoryCache) SetCommentThreads(key string, threads *CommentThreadResults) {
+	c.set(RegionComments, key, threads)
+}
+
+// GetCategories retrieves video categories from Cache.
+func (c *MemoryCache) GetCategories(key string) *VideoCategoryResults {
+	v, _ := c.get(RegionCategories, key).(*VideoCategoryResults)
+	return v
+}
+
+// SetCategories stores video categories to Cache.
+func (c *MemoryCache) SetCategories(key string, categories *VideoCategoryResults) {
+	c.set(RegionCategories, key, categories)
+}
+
+// GetSubscriptions retrieves subscriptions from Cache.
+func (c *MemoryCache) GetSubscriptions(key string) *SubscriptionResults {
+	v, _ := c.get(RegionSubscriptions, key).(*SubscriptionResults)
+	return v
+}
+
+// SetSubscriptions stores subscriptions to Cache.
+func (c *MemoryCache) SetSubscriptions(key string, subscriptions *SubscriptionResults) {
+	c.set(RegionSubscriptions, key, subscriptions)
 }
 
 func (c *MemoryCache) GetServiceName() string {
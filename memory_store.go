@@ -0,0 +1,114 @@
+package alaitube
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process CacheV2. Unlike MemoryCache it accepts any region name,
+// so it can back Regions of resources the Cache interface doesn't know about.
+// Entries expire after their TTL; there is no size cap.
+type MemoryStore struct {
+	regions map[CacheRegion]map[string]memoryStoreEntry
+	ttl     time.Duration
+	now     func() time.Time
+	sync.Mutex
+}
+
+type memoryStoreEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates a MemoryStore whose entries live for ttl unless Set is given another TTL.
+// A zero ttl keeps entries until they are deleted or purged.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		regions: make(map[CacheRegion]map[string]memoryStoreEntry),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// Get returns the value of key in region. Expired entries are removed and reported as missing.
+func (s *MemoryStore) Get(ctx context.Context, region CacheRegion, key string) ([]byte, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	s.Lock()
+	defer s.Unlock()
+	entry, ok := s.regions[region][key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expiresAt.IsZero() && s.now().After(entry.expiresAt) {
+		delete(s.regions[region], key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set stores a copy of value under key in region.
+func (s *MemoryStore) Set(ctx context.Context, region CacheRegion, key string, value []byte, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ttl == 0 {
+		ttl = s.ttl
+	}
+	entry := memoryStoreEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = s.now().Add(ttl)
+	}
+	s.Lock()
+	defer s.Unlock()
+	r, ok := s.regions[region]
+	if !ok {
+		r = make(map[string]memoryStoreEntry)
+		s.regions[region] = r
+	}
+	r[key] = entry
+	return nil
+}
+
+// Delete removes the entry for key in region.
+func (s *MemoryStore) Delete(ctx context.Context, region CacheRegion, key string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.regions[region], key)
+	return nil
+}
+
+// PurgeRegion removes every entry of region.
+func (s *MemoryStore) PurgeRegion(ctx context.Context, region CacheRegion) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.regions, region)
+	return nil
+}
+
+// PurgeAll removes every entry of every region.
+func (s *MemoryStore) PurgeAll(ctx context.Context) error {
+	s.Lock()
+	defer s.Unlock()
+	s.regions = make(map[CacheRegion]map[string]memoryStoreEntry)
+	return nil
+}
+
+// RegionSize returns the number of entries of region and the bytes of their keys and values.
+func (s *MemoryStore) RegionSize(ctx context.Context, region CacheRegion) (int64, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+	s.Lock()
+	defer s.Unlock()
+	var bytes int64
+	for key, entry := range s.regions[region] {
+		bytes += int64(len(key) + len(entry.value))
+	}
+	return int64(len(s.regions[region])), bytes, nil
+}
+
+var _ CacheV2 = (*MemoryStore)(nil)
+var _ CacheV2Sizer = (*MemoryStore)(nil)
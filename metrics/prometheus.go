@@ -0,0 +1,106 @@
+// Package metrics exports the instrumentation of alaitube clients as Prometheus metrics.
+//
+//	collector := metrics.NewCollector("alaitube")
+//	prometheus.MustRegister(collector)
+//	client := alaitube.NewClient(alaitube.WithApiKey(key), alaitube.WithObserver(collector))
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/josephalai/alaitube"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is an alaitube.Observer that records API requests, quota usage, and cache lookups,
+// and a prometheus.Collector exposing them. A single Collector may observe several clients.
+type Collector struct {
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	quota    *prometheus.CounterVec
+	cache    *prometheus.CounterVec
+}
+
+var _ alaitube.Observer = (*Collector)(nil)
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a Collector whose metric names start with namespace.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "YouTube API request attempts by endpoint, method, and HTTP status code.",
+		}, []string{"endpoint", "method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "YouTube API request latency by endpoint.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "request_errors_total",
+			Help:      "Failed YouTube API request attempts by endpoint and error reason.",
+		}, []string{"endpoint", "reason"}),
+		quota: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "quota_units_total",
+			Help:      "Estimated YouTube API quota units consumed by endpoint.",
+		}, []string{"endpoint"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Cache lookups by region and result (hit or miss).",
+		}, []string{"region", "result"}),
+	}
+}
+
+// ObserveRequest records a request attempt.
+func (c *Collector) ObserveRequest(info alaitube.RequestInfo) {
+	code := "none"
+	if info.Status != 0 {
+		code = strconv.Itoa(info.Status)
+	}
+	c.requests.WithLabelValues(info.Endpoint, info.Method, code).Inc()
+	c.latency.WithLabelValues(info.Endpoint).Observe(info.Duration.Seconds())
+	c.quota.WithLabelValues(info.Endpoint).Add(float64(info.QuotaUnits))
+	if info.Err != nil {
+		reason := info.Reason
+		if reason == "" {
+			reason = "transport"
+			if info.Status != 0 {
+				reason = "http_" + code
+			}
+		}
+		c.errors.WithLabelValues(info.Endpoint, reason).Inc()
+	}
+}
+
+// ObserveCache records a cache lookup.
+func (c *Collector) ObserveCache(region alaitube.CacheRegion, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	c.cache.WithLabelValues(string(region), result).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.latency.Describe(ch)
+	c.errors.Describe(ch)
+	c.quota.Describe(ch)
+	c.cache.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.latency.Collect(ch)
+	c.errors.Collect(ch)
+	c.quota.Collect(ch)
+	c.cache.Collect(ch)
+}
@@ -0,0 +1,68 @@
+package alaitube
+
+import "net/http"
+
+// Middleware wraps the transport every request of a client is sent through, to add headers,
+// log, measure, or answer requests without forking the client. It sees each attempt of a
+// request after the client set its authentication and encoding headers and waited on the rate
+// limiter, so retries go through it again. A middleware may answer a request itself, as a
+// caching layer does, by returning a response without calling next.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to an http.RoundTripper, to write a Middleware inline:
+//
+//	logging := func(next http.RoundTripper) http.RoundTripper {
+//		return alaitube.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+//			start := time.Now()
+//			resp, err := next.RoundTrip(req)
+//			log.Printf("%s %s took %s", req.Method, req.URL.Path, time.Since(start))
+//			return resp, err
+//		})
+//	}
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware adds middlewares to the chain requests are sent through. The first middleware
+// is the outermost: it receives requests first and responses last. The chain wraps the transport
+// of the client's http.Client, which is copied rather than modified, so WithHttpClient may be
+// given before or after it.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(yt *YoutubeApi) {
+		yt.middlewares = append(yt.middlewares, middlewares...)
+	}
+}
+
+// HeaderMiddleware sets a header on every request, such as the authorization expected by a proxy
+// in front of the API.
+func HeaderMiddleware(key, value string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			// A RoundTripper must not modify its request, so the header is set on a copy.
+			req = req.Clone(req.Context())
+			req.Header.Set(key, value)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// applyMiddlewares replaces the client's http.Client with a copy whose transport is wrapped in the
+// middleware chain.
+func (yt *YoutubeApi) applyMiddlewares() {
+	if len(yt.middlewares) == 0 {
+		return
+	}
+	transport := yt.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(yt.middlewares) - 1; i >= 0; i-- {
+		transport = yt.middlewares[i](transport)
+	}
+	client := *yt.httpClient
+	client.Transport = transport
+	yt.httpClient = &client
+}
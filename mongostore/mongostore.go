@@ -0,0 +1,206 @@
+// Package mongostore persists fetched YouTube resources to MongoDB for later offline analysis.
+// A Store is an alaitube.Persister, so the client can write every fetched resource through:
+//
+//	client, err := mongo.Connect(options.Client().ApplyURI("mongodb://localhost:27017"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	store := mongostore.New(client.Database("youtube"))
+//	if err := store.EnsureIndexes(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+//	yt := alaitube.NewClient(alaitube.WithApiKey(key), alaitube.WithPersister(store))
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/josephalai/alaitube"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Default collection names.
+const (
+	DefaultVideosCollection    = "videos"
+	DefaultChannelsCollection  = "channels"
+	DefaultPlaylistsCollection = "playlists"
+)
+
+// Store upserts videos and channels by their YouTube ID, using the bson tags of the alaitube
+// types, and stores playlists as the ordered IDs of their videos.
+type Store struct {
+	videos    *mongo.Collection
+	channels  *mongo.Collection
+	playlists *mongo.Collection
+	now       func() time.Time
+}
+
+// Option configures a Store created with New.
+type Option func(*config)
+
+type config struct {
+	videos, channels, playlists string
+}
+
+// WithCollections overrides the names of the collections. Empty names keep the default.
+func WithCollections(videos, channels, playlists string) Option {
+	return func(c *config) {
+		if videos != "" {
+			c.videos = videos
+		}
+		if channels != "" {
+			c.channels = channels
+		}
+		if playlists != "" {
+			c.playlists = playlists
+		}
+	}
+}
+
+// New creates a Store writing to collections of db.
+func New(db *mongo.Database, opts ...Option) *Store {
+	cfg := config{
+		videos:    DefaultVideosCollection,
+		channels:  DefaultChannelsCollection,
+		playlists: DefaultPlaylistsCollection,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Store{
+		videos:    db.Collection(cfg.videos),
+		channels:  db.Collection(cfg.channels),
+		playlists: db.Collection(cfg.playlists),
+		now:       time.Now,
+	}
+}
+
+// playlist is the document stored for a playlist.
+type playlist struct {
+	Id        string    `bson:"_id"`
+	VideoIds  []string  `bson:"videoIds"`
+	FetchedAt time.Time `bson:"fetchedAt"`
+}
+
+// EnsureIndexes creates the unique ID indexes that make upserts efficient.
+func (s *Store) EnsureIndexes(ctx context.Context) error {
+	for _, coll := range []*mongo.Collection{s.videos, s.channels} {
+		_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			return fmt.Errorf("failed creating index on %s: %w", coll.Name(), err)
+		}
+	}
+	return nil
+}
+
+// upsert replaces the documents of coll whose "id" matches, inserting the missing ones.
+func upsert[T any](ctx context.Context, coll *mongo.Collection, docs []*T, id func(*T) string) error {
+	models := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		if doc == nil || id(doc) == "" {
+			continue
+		}
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "id", Value: id(doc)}}).
+			SetReplacement(doc).
+			SetUpsert(true))
+	}
+	if len(models) == 0 {
+		return nil
+	}
+	if _, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed upserting into %s: %w", coll.Name(), err)
+	}
+	return nil
+}
+
+// SaveVideos upserts videos by ID.
+func (s *Store) SaveVideos(ctx context.Context, videos []*alaitube.Video) error {
+	return upsert(ctx, s.videos, videos, func(v *alaitube.Video) string { return v.Id })
+}
+
+// SaveChannels upserts channels by ID.
+func (s *Store) SaveChannels(ctx context.Context, channels []*alaitube.Item) error {
+	return upsert(ctx, s.channels, channels, func(c *alaitube.Item) string { return c.Id })
+}
+
+// SavePlaylist upserts the videos of a playlist and records their order under playlistId.
+func (s *Store) SavePlaylist(ctx context.Context, playlistId string, results *alaitube.VideoResults) error {
+	if err := s.SaveVideos(ctx, results.Items); err != nil {
+		return err
+	}
+	doc := playlist{Id: playlistId, FetchedAt: s.now()}
+	for _, v := range results.Items {
+		doc.VideoIds = append(doc.VideoIds, v.Id)
+	}
+	_, err := s.playlists.ReplaceOne(ctx, bson.D{{Key: "_id", Value: playlistId}}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed upserting into %s: %w", s.playlists.Name(), err)
+	}
+	return nil
+}
+
+// Videos loads the stored videos among ids. Videos that were never stored are omitted.
+func (s *Store) Videos(ctx context.Context, ids []string) ([]*alaitube.Video, error) {
+	cur, err := s.videos.Find(ctx, bson.D{{Key: "id", Value: bson.D{{Key: "$in", Value: ids}}}})
+	if err != nil {
+		return nil, fmt.Errorf("failed finding videos: %w", err)
+	}
+	var videos []*alaitube.Video
+	if err := cur.All(ctx, &videos); err != nil {
+		return nil, fmt.Errorf("failed decoding videos: %w", err)
+	}
+	return videos, nil
+}
+
+// Channel loads a stored channel. It returns an error wrapping alaitube.ErrNotFound
+// when the channel was never stored.
+func (s *Store) Channel(ctx context.Context, channelId string) (*alaitube.Item, error) {
+	channel := &alaitube.Item{}
+	err := s.channels.FindOne(ctx, bson.D{{Key: "id", Value: channelId}}).Decode(channel)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("channel %s: %w", channelId, alaitube.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed finding channel: %w", err)
+	}
+	return channel, nil
+}
+
+// Playlist loads a stored playlist with its videos in playlist order. It returns an error
+// wrapping alaitube.ErrNotFound when the playlist was never stored.
+func (s *Store) Playlist(ctx context.Context, playlistId string) (*alaitube.VideoResults, error) {
+	doc := playlist{}
+	err := s.playlists.FindOne(ctx, bson.D{{Key: "_id", Value: playlistId}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("playlist %s: %w", playlistId, alaitube.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed finding playlist: %w", err)
+	}
+	videos, err := s.Videos(ctx, doc.VideoIds)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]*alaitube.Video, len(videos))
+	for _, v := range videos {
+		byId[v.Id] = v
+	}
+	results := &alaitube.VideoResults{}
+	for _, id := range doc.VideoIds {
+		if v, ok := byId[id]; ok {
+			results.Items = append(results.Items, v)
+		}
+	}
+	return results, nil
+}
+
+var _ alaitube.Persister = (*Store)(nil)
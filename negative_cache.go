@@ -0,0 +1,45 @@
+package alaitube
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultNegativeTTL is how long a not-found result is remembered when no negative TTL is configured.
+// It is kept short so resources that appear later, like a freshly created channel, are picked up quickly.
+const DefaultNegativeTTL = 5 * time.Minute
+
+// ErrCachedNotFound is returned when a lookup is answered from a negative cache entry instead of the API.
+// It matches ErrNotFound through errors.Is.
+var ErrCachedNotFound = fmt.Errorf("cached: %w", ErrNotFound)
+
+// NegativeCache is implemented by caches that can remember that a lookup found nothing, so repeated
+// lookups of missing resources don't spend quota. MemoryCache and RedisCache implement it; the client
+// skips negative caching for caches that don't.
+type NegativeCache interface {
+	// SetNotFound records that key does not exist in region, for the cache's negative TTL.
+	SetNotFound(region CacheRegion, key string)
+	// IsNotFound reports whether key was recorded as not found and the record hasn't expired.
+	// A false result means the key was never fetched or its record expired, not that it exists.
+	IsNotFound(region CacheRegion, key string) bool
+}
+
+// cachedNotFound returns an ErrCachedNotFound error naming the resource id when the client's cache
+// remembers key as missing.
+func (yt *YoutubeApi) cachedNotFound(region CacheRegion, key, id string) error {
+	if nc, ok := yt.Cache.(NegativeCache); ok && nc.IsNotFound(region, key) {
+		return fmt.Errorf("%s %s: %w", region, id, ErrCachedNotFound)
+	}
+	return nil
+}
+
+// rememberNotFound records key as missing when err reports a not-found resource.
+func (yt *YoutubeApi) rememberNotFound(region CacheRegion, key string, err error) {
+	if !errors.Is(err, ErrNotFound) {
+		return
+	}
+	if nc, ok := yt.Cache.(NegativeCache); ok {
+		nc.SetNotFound(region, key)
+	}
+}
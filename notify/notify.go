@@ -0,0 +1,95 @@
+// Package notify posts formatted messages about videos to Slack and Discord incoming webhooks:
+// the title and link of the video, its thumbnail, channel, and view count.
+//
+// NotifyUpload has the signature of a Watcher callback, so new uploads are announced with:
+//
+//	slack := notify.NewSlack(os.Getenv("SLACK_WEBHOOK_URL"))
+//	watcher := yt.NewWatcher(channelId, slack.NotifyUpload)
+//
+// and a BreakoutAlert announces the videos gaining views faster than their peers each time a
+// scheduled query is refreshed:
+//
+//	alert := notify.NewBreakoutAlert(notify.NewDiscord(discordURL), alaitube.BaselineChannel, 3)
+//	scheduler.RefreshQuery("golang", "@hourly", "golang", 2, alaitube.SearchOptions{}, alert.Check)
+package notify
+
+import (
+	"context"
+	"sync"
+
+	"github.com/josephalai/alaitube"
+)
+
+// Notifier announces videos.
+type Notifier interface {
+	NotifyUpload(ctx context.Context, v *alaitube.Video) error
+	NotifyBreakout(ctx context.Context, score alaitube.VelocityScore) error
+}
+
+var (
+	_ Notifier = (*Webhook)(nil)
+	_ Notifier = Multi(nil)
+)
+
+// Multi is a Notifier announcing videos with each of its notifiers, e.g. to both Slack and Discord.
+// Every notifier is tried, and the first error is returned.
+type Multi []Notifier
+
+// NotifyUpload implements Notifier.
+func (m Multi) NotifyUpload(ctx context.Context, v *alaitube.Video) error {
+	var first error
+	for _, n := range m {
+		if err := n.NotifyUpload(ctx, v); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// NotifyBreakout implements Notifier.
+func (m Multi) NotifyBreakout(ctx context.Context, score alaitube.VelocityScore) error {
+	var first error
+	for _, n := range m {
+		if err := n.NotifyBreakout(ctx, score); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// BreakoutAlert announces the breakout videos of results, each video once.
+type BreakoutAlert struct {
+	notifier Notifier
+	by       alaitube.VelocityBaseline
+	ratio    float64
+
+	mu   sync.Mutex
+	sent map[string]bool
+}
+
+// NewBreakoutAlert creates an alert announcing with notifier the videos gaining views at least
+// ratio times faster than their baseline, as computed by VideoResults.Breakouts.
+func NewBreakoutAlert(notifier Notifier, by alaitube.VelocityBaseline, ratio float64) *BreakoutAlert {
+	return &BreakoutAlert{notifier: notifier, by: by, ratio: ratio, sent: make(map[string]bool)}
+}
+
+// Check announces the breakouts of results that weren't announced by a previous call. It has the
+// signature of a Scheduler.RefreshQuery callback. A video that fails to be announced is retried
+// by the next call.
+func (a *BreakoutAlert) Check(ctx context.Context, results *alaitube.VideoResults) error {
+	for _, score := range results.Breakouts(a.by, a.ratio) {
+		a.mu.Lock()
+		sent := a.sent[score.Video.Id]
+		a.mu.Unlock()
+		if sent {
+			continue
+		}
+		if err := a.notifier.NotifyBreakout(ctx, score); err != nil {
+			return err
+		}
+		a.mu.Lock()
+		a.sent[score.Video.Id] = true
+		a.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/josephalai/alaitube"
+)
+
+// maxAttempts is the number of times a message is posted when the service rate limits it.
+const maxAttempts = 3
+
+// Webhook is a Notifier posting to a Slack or Discord incoming webhook.
+type Webhook struct {
+	url        string
+	httpClient *http.Client
+	render     func(m *message) interface{}
+}
+
+// Option configures a Webhook created with NewSlack or NewDiscord.
+type Option func(*Webhook)
+
+// WithHttpClient sets the http.Client messages are posted with.
+// A nil client is ignored and the default client is kept.
+func WithHttpClient(client *http.Client) Option {
+	return func(w *Webhook) {
+		if client != nil {
+			w.httpClient = client
+		}
+	}
+}
+
+// NewSlack creates a Notifier posting to a Slack incoming webhook URL.
+func NewSlack(webhookURL string, opts ...Option) *Webhook {
+	return newWebhook(webhookURL, slackPayload, opts)
+}
+
+// NewDiscord creates a Notifier posting to a Discord webhook URL.
+func NewDiscord(webhookURL string, opts ...Option) *Webhook {
+	return newWebhook(webhookURL, discordPayload, opts)
+}
+
+func newWebhook(webhookURL string, render func(*message) interface{}, opts []Option) *Webhook {
+	w := &Webhook{
+		url:        webhookURL,
+		httpClient: &http.Client{Timeout: alaitube.DefaultTimeout},
+		render:     render,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// NotifyUpload posts a message announcing a new upload.
+func (w *Webhook) NotifyUpload(ctx context.Context, v *alaitube.Video) error {
+	return w.post(ctx, newMessage("New upload", v))
+}
+
+// NotifyBreakout posts a message announcing a video gaining views faster than its baseline.
+func (w *Webhook) NotifyBreakout(ctx context.Context, score alaitube.VelocityScore) error {
+	m := newMessage(fmt.Sprintf("Breakout: %.1f× its baseline", score.Ratio), score.Video)
+	m.detail = fmt.Sprintf("%s views/hour, baseline %s", formatRate(score.ViewsPerHour), formatRate(score.Baseline))
+	return w.post(ctx, m)
+}
+
+// post sends a message, retrying when the service answers 429 Too Many Requests.
+func (w *Webhook) post(ctx context.Context, m *message) error {
+	body, err := json.Marshal(w.render(m))
+	if err != nil {
+		return fmt.Errorf("failed encoding message: %w", err)
+	}
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed creating webhook request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := w.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed posting message: %w", err)
+		}
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		switch {
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			return nil
+		case resp.StatusCode == http.StatusTooManyRequests && attempt < maxAttempts:
+			if err := sleep(ctx, retryDelay(resp.Header)); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("webhook answered %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+		}
+	}
+}
+
+// retryDelay returns the wait requested by a 429 response. Slack sends Retry-After in seconds,
+// and Discord sends it too, along with a finer X-RateLimit-Reset-After.
+func retryDelay(header http.Header) time.Duration {
+	for _, key := range []string{"X-RateLimit-Reset-After", "Retry-After"} {
+		if seconds, err := strconv.ParseFloat(header.Get(key), 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second))
+		}
+	}
+	return time.Second
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// message is the content of a notification, rendered by each service.
+type message struct {
+	heading     string
+	title       string
+	url         string
+	channel     string
+	thumbnail   string
+	views       *int64
+	publishedAt time.Time
+	// detail is an extra line, such as the velocity of a breakout.
+	detail string
+}
+
+func newMessage(heading string, v *alaitube.Video) *message {
+	m := &message{heading: heading, title: v.Id, url: v.Url(), publishedAt: v.PublishedAt()}
+	if s := v.Snippet; s != nil {
+		if s.Title != "" {
+			m.title = s.Title
+		}
+		m.channel = s.ChannelTitle
+		if t := s.Thumbnails.BestAvailable(); t != nil {
+			m.thumbnail = t.Url
+		}
+	}
+	if v.Statistics != nil {
+		views := v.Statistics.ViewCount.Int64()
+		m.views = &views
+	}
+	return m
+}
+
+// summary is the channel and view count line of the message.
+func (m *message) summary() string {
+	var parts []string
+	if m.channel != "" {
+		parts = append(parts, m.channel)
+	}
+	if m.views != nil {
+		parts = append(parts, formatCount(*m.views)+" views")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatCount abbreviates a count the way YouTube displays it, e.g. 1.2K or 3.4M.
+func formatCount(n int64) string {
+	switch {
+	case n >= 1e9:
+		return strconv.FormatFloat(float64(n)/1e9, 'f', 1, 64) + "B"
+	case n >= 1e6:
+		return strconv.FormatFloat(float64(n)/1e6, 'f', 1, 64) + "M"
+	case n >= 1e3:
+		return strconv.FormatFloat(float64(n)/1e3, 'f', 1, 64) + "K"
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// formatRate formats a views per hour rate, keeping a decimal for slow rates.
+func formatRate(f float64) string {
+	if f < 100 {
+		return strconv.FormatFloat(f, 'f', 1, 64)
+	}
+	return formatCount(int64(f))
+}
+
+// slackEscaper escapes the control characters of Slack's mrkdwn.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackPayload renders a message as Block Kit blocks, with text as the fallback of notifications.
+func slackPayload(m *message) interface{} {
+	lines := []string{"*" + m.heading + "*", "*<" + m.url + "|" + slackEscaper.Replace(m.title) + ">*"}
+	if s := m.summary(); s != "" {
+		lines = append(lines, slackEscaper.Replace(s))
+	}
+	if m.detail != "" {
+		lines = append(lines, slackEscaper.Replace(m.detail))
+	}
+	section := map[string]interface{}{
+		"type": "section",
+		"text": map[string]string{"type": "mrkdwn", "text": strings.Join(lines, "\n")},
+	}
+	if m.thumbnail != "" {
+		section["accessory"] = map[string]string{"type": "image", "image_url": m.thumbnail, "alt_text": m.title}
+	}
+	return map[string]interface{}{
+		"text":   slackEscaper.Replace(m.heading + ": " + m.title + " " + m.url),
+		"blocks": []interface{}{section},
+	}
+}
+
+// discordColor is YouTube red, the color of the embeds' side bar.
+const discordColor = 0xFF0000
+
+// discordPayload renders a message as an embed.
+func discordPayload(m *message) interface{} {
+	embed := map[string]interface{}{
+		"title": truncate(m.title, 256),
+		"url":   m.url,
+		"color": discordColor,
+	}
+	var fields []map[string]interface{}
+	if m.channel != "" {
+		fields = append(fields, map[string]interface{}{"name": "Channel", "value": truncate(m.channel, 1024), "inline": true})
+	}
+	if m.views != nil {
+		fields = append(fields, map[string]interface{}{"name": "Views", "value": formatCount(*m.views), "inline": true})
+	}
+	if fields != nil {
+		embed["fields"] = fields
+	}
+	if m.detail != "" {
+		embed["description"] = m.detail
+	}
+	if m.thumbnail != "" {
+		embed["image"] = map[string]string{"url": m.thumbnail}
+	}
+	if !m.publishedAt.IsZero() {
+		embed["timestamp"] = m.publishedAt.UTC().Format(time.RFC3339)
+	}
+	return map[string]interface{}{
+		"content": "**" + m.heading + "**",
+		"embeds":  []interface{}{embed},
+		// Titles mentioning @everyone or a role must not ping the server.
+		"allowed_mentions": map[string]interface{}{"parse": []string{}},
+	}
+}
+
+// truncate shortens s to at most n runes, the limits of Discord's embed fields.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,105 @@
+package alaitube
+
+import "time"
+
+// RequestInfo describes a single API request attempt reported to an Observer.
+type RequestInfo struct {
+	// Endpoint is the short name of the API endpoint, such as "videos" or "search".
+	Endpoint string
+	Method   string
+	// Status is the HTTP status code, or zero when no response was received.
+	Status   int
+	Duration time.Duration
+	// Err is the error the attempt failed with, if any.
+	Err error
+	// Reason is the YouTube error reason, such as "quotaExceeded", when the API reported one.
+	Reason string
+	// QuotaUnits is the estimated quota the attempt consumed.
+	QuotaUnits int
+}
+
+// Observer receives instrumentation events from a client, typically to export them as metrics.
+// Implementations must be safe for concurrent use and return quickly.
+type Observer interface {
+	// ObserveRequest is called after every request attempt, including retried ones.
+	ObserveRequest(info RequestInfo)
+	// ObserveCache is called on every cache lookup the client makes.
+	ObserveCache(region CacheRegion, hit bool)
+}
+
+// WithObserver reports every request and cache lookup of the client to o.
+// The client's Cache is wrapped to observe lookups, so it no longer type-asserts to the concrete cache.
+func WithObserver(o Observer) Option {
+	return func(yt *YoutubeApi) {
+		yt.observer = o
+	}
+}
+
+// observeRequest reports a finished request attempt to the client's observer, if any.
+func (yt *YoutubeApi) observeRequest(info RequestInfo) {
+	if yt.observer == nil {
+		return
+	}
+	info.Reason = errorReason(info.Err)
+	info.QuotaUnits = QuotaCost(info.Method, info.Endpoint)
+	yt.observer.ObserveRequest(info)
+}
+
+// observedCache reports the hits and misses of the wrapped Cache to an Observer.
+type observedCache struct {
+	Cache
+	observer Observer
+}
+
+func (c observedCache) GetVideo(key string) *VideoResults {
+	v := c.Cache.GetVideo(key)
+	c.observer.ObserveCache(RegionVideos, v != nil)
+	return v
+}
+
+func (c observedCache) GetChannel(key string) *ChannelInfo {
+	v := c.Cache.GetChannel(key)
+	c.observer.ObserveCache(RegionChannels, v != nil)
+	return v
+}
+
+func (c observedCache) GetPlaylist(key string) *VideoResults {
+	v := c.Cache.GetPlaylist(key)
+	c.observer.ObserveCache(RegionPlaylists, v != nil)
+	return v
+}
+
+func (c observedCache) GetVideoDetail(key string) *VideoResults {
+	v := c.Cache.GetVideoDetail(key)
+	c.observer.ObserveCache(RegionVideoDetails, v != nil)
+	return v
+}
+
+func (c observedCache) GetCommentThreads(key string) *CommentThreadResults {
+	v := c.Cache.GetCommentThreads(key)
+	c.observer.ObserveCache(RegionComments, v != nil)
+	return v
+}
+
+func (c observedCache) GetCategories(key string) *VideoCategoryResults {
+	v := c.Cache.GetCategories(key)
+	c.observer.ObserveCache(RegionCategories, v != nil)
+	return v
+}
+
+func (c observedCache) GetSubscriptions(key string) *SubscriptionResults {
+	v := c.Cache.GetSubscriptions(key)
+	c.observer.ObserveCache(RegionSubscriptions, v != nil)
+	return v
+}
+
+func (c observedCache) SetNotFound(region CacheRegion, key string) {
+	if nc, ok := c.Cache.(NegativeCache); ok {
+		nc.SetNotFound(region, key)
+	}
+}
+
+func (c observedCache) IsNotFound(region CacheRegion, key string) bool {
+	nc, ok := c.Cache.(NegativeCache)
+	return ok && nc.IsNotFound(region, key)
+}
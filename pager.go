@@ -0,0 +1,117 @@
+package alaitube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoMorePages is returned by PlaylistPager.Next once every page has been consumed.
+var ErrNoMorePages = errors.New("no more pages")
+
+// PlaylistPager lazily walks a playlist one page (up to 50 videos) at a time.
+// Each call to Next fetches exactly one playlistItems page plus the details of its videos,
+// so callers only spend quota on the pages they actually read.
+// A PlaylistPager is not safe for concurrent use.
+type PlaylistPager struct {
+	yt         *YoutubeApi
+	channelId  string
+	playlistId string
+	nextPage   string
+	page       int
+	done       bool
+}
+
+// ChannelUploads returns a pager over a channel's uploads playlist, newest first.
+// The channel is only looked up when the first page is requested.
+func (yt *YoutubeApi) ChannelUploads(channelId string) *PlaylistPager {
+	return &PlaylistPager{yt: yt, channelId: channelId}
+}
+
+// PlaylistPages returns a pager over an arbitrary playlist.
+func (yt *YoutubeApi) PlaylistPages(playlistId string) *PlaylistPager {
+	return &PlaylistPager{yt: yt, playlistId: playlistId}
+}
+
+// Resume makes the pager continue at pageToken, the value of PageToken saved by an earlier pager
+// over the same playlist, so a walk can pick up where it stopped after a restart. An empty
+// pageToken restarts at the first page.
+func (p *PlaylistPager) Resume(pageToken string) *PlaylistPager {
+	p.nextPage = pageToken
+	p.done = false
+	return p
+}
+
+// PageToken returns the token of the page the next call to Next fetches, to be saved and given to
+// Resume. It is empty before the first page and once the playlist is exhausted.
+func (p *PlaylistPager) PageToken() string {
+	return p.nextPage
+}
+
+// HasNext reports whether another page may be available.
+func (p *PlaylistPager) HasNext() bool {
+	return !p.done
+}
+
+// Next fetches the next page of videos. It returns ErrNoMorePages when the playlist is exhausted.
+func (p *PlaylistPager) Next() (*VideoResults, error) {
+	return p.NextContext(context.Background())
+}
+
+// NextContext is like Next but uses ctx for the underlying requests.
+func (p *PlaylistPager) NextContext(ctx context.Context) (*VideoResults, error) {
+	if p.done {
+		return nil, ErrNoMorePages
+	}
+	if p.playlistId == "" {
+		playlistId, err := p.yt.uploadsPlaylistId(ctx, p.channelId)
+		if err != nil {
+			return nil, err
+		}
+		p.playlistId = playlistId
+	}
+
+	res, err := p.yt.fetchVideoResultsFromAPI(ctx, p.yt.generatePageUrl(p.playlistId, p.nextPage, p.page))
+	if err != nil {
+		return nil, err
+	}
+	p.page++
+	p.nextPage = res.NextPageToken
+	p.done = p.nextPage == ""
+
+	var videos []string
+	thumbnails := make(map[string]Thumbnails)
+	titles := make(map[string]string)
+	for _, vid := range res.Items {
+		if vid.ContentDetails == nil {
+			continue
+		}
+		videos = append(videos, vid.ContentDetails.VideoId)
+		if vid.Snippet != nil {
+			thumbnails[vid.ContentDetails.VideoId] = vid.Snippet.Thumbnails
+			titles[vid.ContentDetails.VideoId] = vid.Snippet.Title
+		}
+	}
+	if len(videos) == 0 {
+		return &VideoResults{NextPageToken: p.nextPage}, nil
+	}
+	results, err := p.yt.GetVideosContext(ctx, videos)
+	if err != nil {
+		return nil, err
+	}
+	page := &VideoResults{Items: results.Items, NextPageToken: p.nextPage, Unavailable: unavailableVideos(videos, results, titles)}
+	return processVideoItems(page, thumbnails), nil
+}
+
+// uploadsPlaylistId resolves the ID of a channel's uploads playlist.
+func (yt *YoutubeApi) uploadsPlaylistId(ctx context.Context, channelId string) (string, error) {
+	cInfo, err := yt.GetChannelInfoContext(ctx, channelId)
+	if err != nil {
+		return "", err
+	}
+	item := cInfo.Items[0]
+	if item.ContentDetails == nil || item.ContentDetails.RelatedPlaylists == nil || item.ContentDetails.RelatedPlaylists.Uploads == "" {
+		return "", fmt.Errorf("channel %s has no uploads playlist", channelId)
+	}
+	return item.ContentDetails.RelatedPlaylists.Uploads, nil
+}
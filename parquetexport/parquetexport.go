@@ -0,0 +1,210 @@
+// Package parquetexport writes fetched videos and channels as Parquet files with typed,
+// columnar schemas, so crawled datasets can be queried in DuckDB or Spark without conversion:
+//
+//	f, err := os.Create("videos.parquet")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	if err := parquetexport.WriteVideos(f, results); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// Counts and timestamps are optional columns, null when the part they come from wasn't fetched or
+// the count is hidden. Timestamps are stored in UTC with millisecond precision.
+package parquetexport
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/josephalai/alaitube"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// VideoRecord is the Parquet row of a video.
+type VideoRecord struct {
+	Id           string `parquet:"id"`
+	Title        string `parquet:"title"`
+	Description  string `parquet:"description"`
+	ChannelId    string `parquet:"channel_id"`
+	ChannelTitle string `parquet:"channel_title"`
+	CategoryId   string `parquet:"category_id"`
+	// PublishedAt is in milliseconds since the Unix epoch, stored as a timestamp column.
+	PublishedAt     int64    `parquet:"published_at,optional,timestamp(millisecond)"`
+	DurationSeconds *int64   `parquet:"duration_seconds,optional"`
+	Views           *int64   `parquet:"views,optional"`
+	Likes           *int64   `parquet:"likes,optional"`
+	Comments        *int64   `parquet:"comments,optional"`
+	Tags            []string `parquet:"tags,list"`
+	DefaultLanguage string   `parquet:"default_language"`
+	PrivacyStatus   string   `parquet:"privacy_status"`
+	MadeForKids     bool     `parquet:"made_for_kids"`
+	Url             string   `parquet:"url"`
+}
+
+// NewVideoRecord converts a video into its Parquet row.
+func NewVideoRecord(v *alaitube.Video) VideoRecord {
+	r := VideoRecord{Id: v.Id, Url: v.Url(), MadeForKids: v.IsMadeForKids()}
+	if s := v.Snippet; s != nil {
+		r.Title, r.Description = s.Title, s.Description
+		r.ChannelId, r.ChannelTitle = s.ChannelId, s.ChannelTitle
+		r.CategoryId, r.DefaultLanguage = s.CategoryId, s.DefaultLanguage
+		r.PublishedAt = unixMillis(s.PublishedAt)
+		r.Tags = s.Tags
+	}
+	if d := v.Duration(); d > 0 {
+		r.DurationSeconds = int64Ptr(int64(d.Seconds()))
+	}
+	if st := v.Statistics; st != nil {
+		r.Views, r.Likes, r.Comments = int64Ptr(st.ViewCount.Int64()), int64Ptr(st.LikeCount.Int64()), int64Ptr(st.CommentCount.Int64())
+	}
+	if v.Status != nil {
+		r.PrivacyStatus = v.Status.PrivacyStatus
+	}
+	return r
+}
+
+// ChannelRecord is the Parquet row of a channel.
+type ChannelRecord struct {
+	Id          string `parquet:"id"`
+	Title       string `parquet:"title"`
+	Description string `parquet:"description"`
+	CustomUrl   string `parquet:"custom_url"`
+	Country     string `parquet:"country"`
+	// PublishedAt is in milliseconds since the Unix epoch, stored as a timestamp column.
+	PublishedAt int64  `parquet:"published_at,optional,timestamp(millisecond)"`
+	Subscribers *int64 `parquet:"subscribers,optional"`
+	Views       *int64 `parquet:"views,optional"`
+	Videos      *int64 `parquet:"videos,optional"`
+	Uploads     string `parquet:"uploads_playlist_id"`
+	Url         string `parquet:"url"`
+}
+
+// NewChannelRecord converts a channel into its Parquet row.
+func NewChannelRecord(c *alaitube.Item) ChannelRecord {
+	r := ChannelRecord{Id: c.Id, Url: c.Url()}
+	if s := c.Snippet; s != nil {
+		r.Title, r.Description = s.Title, s.Description
+		r.CustomUrl, r.Country = s.CustomUrl, s.Country
+		r.PublishedAt = unixMillis(s.PublishedAt)
+	}
+	if st := c.Statistics; st != nil {
+		if !st.HiddenSubscriberCount {
+			r.Subscribers = int64Ptr(st.SubscriberCount.Int64())
+		}
+		r.Views, r.Videos = int64Ptr(st.ViewCount.Int64()), int64Ptr(st.VideoCount.Int64())
+	}
+	if c.ContentDetails != nil && c.ContentDetails.RelatedPlaylists != nil {
+		r.Uploads = c.ContentDetails.RelatedPlaylists.Uploads
+	}
+	return r
+}
+
+func int64Ptr(n int64) *int64 {
+	return &n
+}
+
+// unixMillis returns t in milliseconds since the Unix epoch, or zero, written as null, when t is zero.
+func unixMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// Option configures the Parquet writers.
+type Option func(*config)
+
+type config struct {
+	compression  parquet.WriterOption
+	rowGroupSize int64
+}
+
+// WithCompression sets the compression codec of the columns, such as &parquet.Zstd.
+// The default is Snappy, which every Parquet reader supports.
+func WithCompression(codec compress.Codec) Option {
+	return func(c *config) {
+		if codec != nil {
+			c.compression = parquet.Compression(codec)
+		}
+	}
+}
+
+// WithRowGroupSize bounds the number of rows per row group. Zero keeps the writer's default.
+func WithRowGroupSize(rows int64) Option {
+	return func(c *config) {
+		c.rowGroupSize = rows
+	}
+}
+
+// writerOptions builds the parquet-go options of opts.
+func writerOptions(opts []Option) []parquet.WriterOption {
+	c := config{compression: parquet.Compression(&parquet.Snappy)}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	options := []parquet.WriterOption{c.compression, parquet.CreatedBy("alaitube", "", "")}
+	if c.rowGroupSize > 0 {
+		options = append(options, parquet.MaxRowsPerRowGroup(c.rowGroupSize))
+	}
+	return options
+}
+
+// VideoWriter streams videos into a Parquet file, for crawls too large to hold in memory.
+// Close must be called to write the file footer; it doesn't close the underlying writer.
+type VideoWriter struct {
+	w *parquet.GenericWriter[VideoRecord]
+}
+
+// NewVideoWriter creates a VideoWriter writing to w.
+func NewVideoWriter(w io.Writer, opts ...Option) *VideoWriter {
+	return &VideoWriter{w: parquet.NewGenericWriter[VideoRecord](w, writerOptions(opts)...)}
+}
+
+// Write appends videos to the file.
+func (w *VideoWriter) Write(videos ...*alaitube.Video) error {
+	records := make([]VideoRecord, len(videos))
+	for i, v := range videos {
+		records[i] = NewVideoRecord(v)
+	}
+	if _, err := w.w.Write(records); err != nil {
+		return fmt.Errorf("failed writing parquet videos: %w", err)
+	}
+	return nil
+}
+
+// Close flushes the buffered rows and writes the file footer.
+func (w *VideoWriter) Close() error {
+	if err := w.w.Close(); err != nil {
+		return fmt.Errorf("failed closing parquet file: %w", err)
+	}
+	return nil
+}
+
+// WriteVideos writes the videos of results to w as a complete Parquet file.
+func WriteVideos(w io.Writer, results *alaitube.VideoResults, opts ...Option) error {
+	vw := NewVideoWriter(w, opts...)
+	if err := vw.Write(results.Items...); err != nil {
+		return err
+	}
+	return vw.Close()
+}
+
+// WriteChannels writes the channels of info to w as a complete Parquet file.
+func WriteChannels(w io.Writer, info *alaitube.ChannelInfo, opts ...Option) error {
+	pw := parquet.NewGenericWriter[ChannelRecord](w, writerOptions(opts)...)
+	records := make([]ChannelRecord, len(info.Items))
+	for i, item := range info.Items {
+		records[i] = NewChannelRecord(item)
+	}
+	if _, err := pw.Write(records); err != nil {
+		return fmt.Errorf("failed writing parquet channels: %w", err)
+	}
+	if err := pw.Close(); err != nil {
+		return fmt.Errorf("failed closing parquet file: %w", err)
+	}
+	return nil
+}
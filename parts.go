@@ -0,0 +1,91 @@
+package alaitube
+
+import (
+	"context"
+	"net/url"
+)
+
+const VideosEndpoint = "https://www.googleapis.com/youtube/v3/videos"
+
+// Parts selects the part and fields parameters of a videos request. Part is a comma-separated
+// list of resource parts and Fields a partial response filter; an empty Fields returns every
+// field of the requested parts. The zero value uses the client's default, PartsStandard unless
+// changed with WithVideoParts.
+type Parts struct {
+	Part   string
+	Fields string
+	// Hl is the language of the localized snippet, see WithLanguage. Empty keeps the original metadata.
+	Hl string
+}
+
+// Presets for Parts. Search results are filtered on view count, so every preset used by
+// FindTags must include statistics(viewCount).
+var (
+	// PartsMinimal fetches just enough to rank videos: title, publish date, channel, tags, and counters.
+	PartsMinimal = Parts{
+		Part:   "snippet,statistics",
+		Fields: "items(id,snippet(title,publishedAt,channelId,tags),statistics(viewCount,likeCount,commentCount))",
+	}
+	// PartsStandard is the historical selection of the package, covering every field of Video.
+	PartsStandard = Parts{
+		Part:   "snippet,statistics,status,contentDetails,liveStreamingDetails,topicDetails",
+		Fields: "items(snippet(title,publishedAt,description,tags,liveBroadcastContent,categoryId,defaultLanguage,defaultAudioLanguage),id,statistics,status,contentDetails,liveStreamingDetails,topicDetails)",
+	}
+	// PartsFull fetches every field of the parts Video models, without a partial response filter.
+	PartsFull = Parts{
+		Part: "snippet,statistics,status,contentDetails,liveStreamingDetails,topicDetails",
+	}
+)
+
+// WithVideoParts sets the parts and fields fetched by GetVideos and FindTags when a call doesn't choose its own.
+func WithVideoParts(parts Parts) Option {
+	return func(yt *YoutubeApi) {
+		if parts.Part != "" {
+			yt.videoParts = parts
+		}
+	}
+}
+
+// orDefault returns p, or def when p is the zero value. A language set on its own is applied to def.
+func (p Parts) orDefault(def Parts) Parts {
+	if p.Part == "" {
+		return def.WithLanguage(p.Hl)
+	}
+	return p
+}
+
+// keyValues adds the parts to the parameters v of a cache key, with names prefixed by prefix, so
+// results fetched with different parts don't share cache entries.
+func (p Parts) keyValues(v url.Values, prefix string) url.Values {
+	v.Set(prefix+"part", p.Part)
+	if p.Fields != "" {
+		v.Set(prefix+"fields", p.Fields)
+	}
+	if p.Hl != "" {
+		v.Set(prefix+"hl", p.Hl)
+	}
+	return v
+}
+
+// videosUrl builds the URL of a videos request for a comma-separated batch of IDs.
+func (yt *YoutubeApi) videosUrl(ids string, parts Parts, pageToken string) string {
+	v := url.Values{}
+	v.Set("key", yt.apiKey)
+	v.Set("part", parts.Part)
+	if parts.Fields != "" {
+		v.Set("fields", parts.Fields)
+	}
+	if parts.Hl != "" {
+		v.Set("hl", parts.Hl)
+	}
+	v.Set("id", ids)
+	if pageToken != "" {
+		v.Set("pageToken", pageToken)
+	}
+	return VideosEndpoint + "?" + v.Encode()
+}
+
+// GetVideosWithParts is like GetVideosContext but fetches the given parts and fields instead of the client's default.
+func (yt *YoutubeApi) GetVideosWithParts(ctx context.Context, videoIds []string, parts Parts) (*VideoResults, error) {
+	return yt.getVideos(ctx, videoIds, parts.orDefault(yt.videoParts))
+}
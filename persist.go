@@ -0,0 +1,49 @@
+package alaitube
+
+import "context"
+
+// Persister receives the resources the client fetches from the API, for write-through storage
+// and later offline analysis. Results served from the cache are not passed again.
+type Persister interface {
+	// SaveVideos stores video details fetched by GetVideos, FindTags, and playlist lookups.
+	SaveVideos(ctx context.Context, videos []*Video) error
+	// SaveChannels stores channels fetched by GetChannelInfo.
+	SaveChannels(ctx context.Context, channels []*Item) error
+	// SavePlaylist stores the videos of a playlist fetched by GetChannelPlaylist.
+	SavePlaylist(ctx context.Context, playlistId string, results *VideoResults) error
+}
+
+// WithPersister writes every fetched video, channel, and playlist through to p.
+// Persistence failures are logged and don't fail the request.
+func WithPersister(p Persister) Option {
+	return func(yt *YoutubeApi) {
+		yt.persister = p
+	}
+}
+
+func (yt *YoutubeApi) persistVideos(ctx context.Context, results *VideoResults) {
+	if yt.persister == nil || results == nil || len(results.Items) == 0 {
+		return
+	}
+	if err := yt.persister.SaveVideos(ctx, results.Items); err != nil {
+		yt.logger.Warn("failed persisting videos", Field{"count", len(results.Items)}, Field{"error", err})
+	}
+}
+
+func (yt *YoutubeApi) persistChannels(ctx context.Context, info *ChannelInfo) {
+	if yt.persister == nil || info == nil || len(info.Items) == 0 {
+		return
+	}
+	if err := yt.persister.SaveChannels(ctx, info.Items); err != nil {
+		yt.logger.Warn("failed persisting channels", Field{"count", len(info.Items)}, Field{"error", err})
+	}
+}
+
+func (yt *YoutubeApi) persistPlaylist(ctx context.Context, playlistId string, results *VideoResults) {
+	if yt.persister == nil || results == nil {
+		return
+	}
+	if err := yt.persister.SavePlaylist(ctx, playlistId, results); err != nil {
+		yt.logger.Warn("failed persisting playlist", Field{"playlistId", playlistId}, Field{"error", err})
+	}
+}
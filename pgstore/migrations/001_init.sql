@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS channels (
+    id                  TEXT PRIMARY KEY,
+    title               TEXT NOT NULL DEFAULT '',
+    description         TEXT NOT NULL DEFAULT '',
+    custom_url          TEXT NOT NULL DEFAULT '',
+    country             TEXT NOT NULL DEFAULT '',
+    published_at        TIMESTAMPTZ,
+    view_count          BIGINT NOT NULL DEFAULT 0,
+    subscriber_count    BIGINT NOT NULL DEFAULT 0,
+    video_count         BIGINT NOT NULL DEFAULT 0,
+    uploads_playlist_id TEXT NOT NULL DEFAULT '',
+    fetched_at          TIMESTAMPTZ NOT NULL,
+    raw                 JSONB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS videos (
+    id               TEXT PRIMARY KEY,
+    channel_id       TEXT NOT NULL DEFAULT '',
+    channel_title    TEXT NOT NULL DEFAULT '',
+    title            TEXT NOT NULL DEFAULT '',
+    description      TEXT NOT NULL DEFAULT '',
+    category_id      TEXT NOT NULL DEFAULT '',
+    published_at     TIMESTAMPTZ,
+    duration_seconds BIGINT NOT NULL DEFAULT 0,
+    view_count       BIGINT NOT NULL DEFAULT 0,
+    like_count       BIGINT NOT NULL DEFAULT 0,
+    comment_count    BIGINT NOT NULL DEFAULT 0,
+    fetched_at       TIMESTAMPTZ NOT NULL,
+    raw              JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS videos_channel_id_idx ON videos (channel_id);
+CREATE INDEX IF NOT EXISTS videos_published_at_idx ON videos (published_at);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id   BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS video_tags (
+    video_id TEXT NOT NULL REFERENCES videos (id) ON DELETE CASCADE,
+    tag_id   BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (video_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS video_tags_tag_id_idx ON video_tags (tag_id);
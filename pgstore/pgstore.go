@@ -0,0 +1,400 @@
+// Package pgstore stores fetched YouTube metadata in PostgreSQL tables, so crawled videos,
+// channels, and tags can be analysed with SQL. It uses database/sql with any PostgreSQL
+// driver, e.g. github.com/jackc/pgx/v5/stdlib:
+//
+//	db, err := sql.Open("pgx", "postgres://localhost/youtube")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	store := pgstore.New(db)
+//	if err := store.Migrate(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+//	yt := alaitube.NewClient(alaitube.WithApiKey(key), alaitube.WithPersister(store))
+package pgstore
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/josephalai/alaitube"
+)
+
+// DefaultBatchSize is the number of rows written per INSERT statement.
+const DefaultBatchSize = 500
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Store writes videos, channels, and their tags to PostgreSQL with batched upserts.
+type Store struct {
+	db        *sql.DB
+	batchSize int
+	now       func() time.Time
+}
+
+// Option configures a Store created with New.
+type Option func(*Store)
+
+// WithBatchSize sets the number of rows written per INSERT statement.
+func WithBatchSize(n int) Option {
+	return func(s *Store) {
+		if n > 0 {
+			s.batchSize = n
+		}
+	}
+}
+
+// New creates a Store using db. Call Migrate before storing anything.
+func New(db *sql.DB, opts ...Option) *Store {
+	s := &Store{db: db, batchSize: DefaultBatchSize, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Migrate applies the schema migrations that haven't been applied yet, each in its own transaction.
+// Applied versions are recorded in the schema_migrations table.
+func (s *Store) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed creating schema_migrations: %w", err)
+	}
+	applied := make(map[int]bool)
+	rows, err := s.db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return fmt.Errorf("failed reading schema_migrations: %w", err)
+	}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed reading schema_migrations: %w", err)
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed reading schema_migrations: %w", err)
+	}
+
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		base := strings.TrimPrefix(name, "migrations/")
+		version, err := strconv.Atoi(base[:strings.IndexByte(base, '_')])
+		if err != nil {
+			return fmt.Errorf("invalid migration name %s: %w", base, err)
+		}
+		if applied[version] {
+			continue
+		}
+		script, err := migrations.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		err = s.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed applying migration %s: %w", base, err)
+		}
+	}
+	return nil
+}
+
+// inTx runs fn in a transaction, committing when it succeeds.
+func (s *Store) inTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// StoreVideoResults upserts the videos of results and replaces their tags, in one transaction.
+func (s *Store) StoreVideoResults(ctx context.Context, results *alaitube.VideoResults) error {
+	if results == nil {
+		return nil
+	}
+	return s.storeVideos(ctx, results.Items)
+}
+
+// StoreChannel upserts the channels of info.
+func (s *Store) StoreChannel(ctx context.Context, info *alaitube.ChannelInfo) error {
+	if info == nil {
+		return nil
+	}
+	return s.storeChannels(ctx, info.Items)
+}
+
+// SaveVideos implements alaitube.Persister.
+func (s *Store) SaveVideos(ctx context.Context, videos []*alaitube.Video) error {
+	return s.storeVideos(ctx, videos)
+}
+
+// SaveChannels implements alaitube.Persister.
+func (s *Store) SaveChannels(ctx context.Context, channels []*alaitube.Item) error {
+	return s.storeChannels(ctx, channels)
+}
+
+// SavePlaylist implements alaitube.Persister by storing the playlist's videos.
+func (s *Store) SavePlaylist(ctx context.Context, playlistId string, results *alaitube.VideoResults) error {
+	return s.StoreVideoResults(ctx, results)
+}
+
+const videoColumns = 13
+
+const upsertVideos = `INSERT INTO videos (id, channel_id, channel_title, title, description, category_id,
+	published_at, duration_seconds, view_count, like_count, comment_count, fetched_at, raw)
+VALUES %s
+ON CONFLICT (id) DO UPDATE SET
+	channel_id = EXCLUDED.channel_id,
+	channel_title = EXCLUDED.channel_title,
+	title = EXCLUDED.title,
+	description = EXCLUDED.description,
+	category_id = EXCLUDED.category_id,
+	published_at = EXCLUDED.published_at,
+	duration_seconds = EXCLUDED.duration_seconds,
+	view_count = EXCLUDED.view_count,
+	like_count = EXCLUDED.like_count,
+	comment_count = EXCLUDED.comment_count,
+	fetched_at = EXCLUDED.fetched_at,
+	raw = EXCLUDED.raw`
+
+const channelColumns = 12
+
+const upsertChannels = `INSERT INTO channels (id, title, description, custom_url, country, published_at,
+	view_count, subscriber_count, video_count, uploads_playlist_id, fetched_at, raw)
+VALUES %s
+ON CONFLICT (id) DO UPDATE SET
+	title = EXCLUDED.title,
+	description = EXCLUDED.description,
+	custom_url = EXCLUDED.custom_url,
+	country = EXCLUDED.country,
+	published_at = EXCLUDED.published_at,
+	view_count = EXCLUDED.view_count,
+	subscriber_count = EXCLUDED.subscriber_count,
+	video_count = EXCLUDED.video_count,
+	uploads_playlist_id = EXCLUDED.uploads_playlist_id,
+	fetched_at = EXCLUDED.fetched_at,
+	raw = EXCLUDED.raw`
+
+func (s *Store) storeVideos(ctx context.Context, videos []*alaitube.Video) error {
+	var rows [][]interface{}
+	var tagRows [][]interface{}
+	tags := make(map[string]bool)
+	var ids []interface{}
+	now := s.now()
+	for _, v := range dedupe(videos, func(v *alaitube.Video) string { return v.Id }) {
+		row, err := videoRow(v, now)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+		ids = append(ids, v.Id)
+		if v.Snippet == nil {
+			continue
+		}
+		seen := make(map[string]bool)
+		for i, tag := range v.Snippet.Tags {
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags[tag] = true
+			tagRows = append(tagRows, []interface{}{v.Id, i, tag})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.insertBatches(ctx, tx, upsertVideos, videoColumns, "", rows); err != nil {
+			return fmt.Errorf("failed upserting videos: %w", err)
+		}
+		if err := s.deleteVideoTags(ctx, tx, ids); err != nil {
+			return fmt.Errorf("failed replacing video tags: %w", err)
+		}
+		var names [][]interface{}
+		for tag := range tags {
+			names = append(names, []interface{}{tag})
+		}
+		// Sorted so concurrent writers lock tag rows in the same order.
+		sort.Slice(names, func(i, j int) bool { return names[i][0].(string) < names[j][0].(string) })
+		if err := s.insertBatches(ctx, tx, `INSERT INTO tags (name) VALUES %s ON CONFLICT (name) DO NOTHING`, 1, "", names); err != nil {
+			return fmt.Errorf("failed upserting tags: %w", err)
+		}
+		const insertVideoTags = `INSERT INTO video_tags (video_id, tag_id, position)
+SELECT v.video_id, t.id, v.position FROM (VALUES %s) AS v (video_id, position, name)
+JOIN tags t ON t.name = v.name
+ON CONFLICT (video_id, tag_id) DO NOTHING`
+		if err := s.insertBatches(ctx, tx, insertVideoTags, 3, "::text,%s::int,%s::text", tagRows); err != nil {
+			return fmt.Errorf("failed inserting video tags: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *Store) storeChannels(ctx context.Context, channels []*alaitube.Item) error {
+	var rows [][]interface{}
+	now := s.now()
+	for _, c := range dedupe(channels, func(c *alaitube.Item) string { return c.Id }) {
+		row, err := channelRow(c, now)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.insertBatches(ctx, tx, upsertChannels, channelColumns, "", rows); err != nil {
+			return fmt.Errorf("failed upserting channels: %w", err)
+		}
+		return nil
+	})
+}
+
+// deleteVideoTags removes the tags of the given videos so they can be replaced.
+func (s *Store) deleteVideoTags(ctx context.Context, tx *sql.Tx, ids []interface{}) error {
+	for start := 0; start < len(ids); start += s.batchSize {
+		end := min(start+s.batchSize, len(ids))
+		placeholders := make([]string, end-start)
+		for i := range placeholders {
+			placeholders[i] = "$" + strconv.Itoa(i+1)
+		}
+		query := `DELETE FROM video_tags WHERE video_id IN (` + strings.Join(placeholders, ",") + `)`
+		if _, err := tx.ExecContext(ctx, query, ids[start:end]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertBatches executes query, whose %s is replaced by the VALUES tuples of up to batchSize
+// rows of columns values each. casts, when set, is a format of the placeholders after the
+// first column, e.g. "::text,%s::int" to type the columns of a VALUES list used in a SELECT.
+func (s *Store) insertBatches(ctx context.Context, tx *sql.Tx, query string, columns int, casts string, rows [][]interface{}) error {
+	for start := 0; start < len(rows); start += s.batchSize {
+		end := min(start+s.batchSize, len(rows))
+		tuples := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*columns)
+		for _, row := range rows[start:end] {
+			placeholders := make([]string, columns)
+			for i := range placeholders {
+				placeholders[i] = "$" + strconv.Itoa(len(args)+i+1)
+			}
+			if casts != "" {
+				rest := make([]interface{}, columns-1)
+				for i := range rest {
+					rest[i] = placeholders[i+1]
+				}
+				tuples = append(tuples, "("+placeholders[0]+fmt.Sprintf(casts, rest...)+")")
+			} else {
+				tuples = append(tuples, "("+strings.Join(placeholders, ",")+")")
+			}
+			args = append(args, row...)
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(query, strings.Join(tuples, ",")), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dedupe drops nil items, items without an ID, and all but the last item of each ID,
+// since one INSERT ... ON CONFLICT statement can't update the same row twice.
+func dedupe[T any](items []*T, id func(*T) string) []*T {
+	index := make(map[string]int)
+	var out []*T
+	for _, item := range items {
+		if item == nil || id(item) == "" {
+			continue
+		}
+		if i, ok := index[id(item)]; ok {
+			out[i] = item
+			continue
+		}
+		index[id(item)] = len(out)
+		out = append(out, item)
+	}
+	return out
+}
+
+func videoRow(v *alaitube.Video, fetchedAt time.Time) ([]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal video %s: %w", v.Id, err)
+	}
+	var channelId, channelTitle, title, description, categoryId string
+	var publishedAt time.Time
+	if v.Snippet != nil {
+		channelId, channelTitle = v.Snippet.ChannelId, v.Snippet.ChannelTitle
+		title, description, categoryId = v.Snippet.Title, v.Snippet.Description, v.Snippet.CategoryId
+		publishedAt = v.Snippet.PublishedAt
+	}
+	var views, likes, comments int64
+	if v.Statistics != nil {
+		views, likes, comments = int64(v.Statistics.ViewCount), int64(v.Statistics.LikeCount), int64(v.Statistics.CommentCount)
+	}
+	return []interface{}{v.Id, channelId, channelTitle, title, description, categoryId,
+		nullTime(publishedAt), v.DurationSeconds(), views, likes, comments, fetchedAt, string(raw)}, nil
+}
+
+func channelRow(c *alaitube.Item, fetchedAt time.Time) ([]interface{}, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal channel %s: %w", c.Id, err)
+	}
+	var title, description, customUrl, country, uploads string
+	var publishedAt time.Time
+	if c.Snippet != nil {
+		title, description, customUrl, country = c.Snippet.Title, c.Snippet.Description, c.Snippet.CustomUrl, c.Snippet.Country
+		publishedAt = c.Snippet.PublishedAt
+	}
+	var views, subscribers, videos int64
+	if c.Statistics != nil {
+		views, subscribers, videos = int64(c.Statistics.ViewCount), int64(c.Statistics.SubscriberCount), int64(c.Statistics.VideoCount)
+	}
+	if c.ContentDetails != nil && c.ContentDetails.RelatedPlaylists != nil {
+		uploads = c.ContentDetails.RelatedPlaylists.Uploads
+	}
+	return []interface{}{c.Id, title, description, customUrl, country, nullTime(publishedAt),
+		views, subscribers, videos, uploads, fetchedAt, string(raw)}, nil
+}
+
+// nullTime maps the zero time to NULL.
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+var _ alaitube.Persister = (*Store)(nil)
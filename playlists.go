@@ -0,0 +1,280 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const ListPlaylists = "https://www.googleapis.com/youtube/v3/playlists?part=snippet,contentDetails&maxResults=50&channelId=%s&key=%s%s"
+const InsertPlaylist = "https://www.googleapis.com/youtube/v3/playlists?part=snippet,status&key=%s"
+const InsertPlaylistItem = "https://www.googleapis.com/youtube/v3/playlistItems?part=snippet,contentDetails&key=%s"
+const UpdatePlaylistItem = "https://www.googleapis.com/youtube/v3/playlistItems?part=snippet,contentDetails&key=%s"
+const DeletePlaylistItem = "https://www.googleapis.com/youtube/v3/playlistItems?id=%s&key=%s"
+
+// Privacy statuses accepted by YouTube for playlists and videos.
+const (
+	PrivacyPublic   = "public"
+	PrivacyPrivate  = "private"
+	PrivacyUnlisted = "unlisted"
+)
+
+// Playlist represents a YouTube playlist.
+type Playlist struct {
+	Id      string `bson:"id,omitempty" json:"id,omitempty"`
+	Snippet *struct {
+		PublishedAt     time.Time  `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+		ChannelId       string     `bson:"channelId,omitempty" json:"channelId,omitempty"`
+		ChannelTitle    string     `bson:"channelTitle,omitempty" json:"channelTitle,omitempty"`
+		Title           string     `bson:"title,omitempty" json:"title,omitempty"`
+		Description     string     `bson:"description,omitempty" json:"description,omitempty"`
+		Thumbnails      Thumbnails `bson:"thumbnails,omitempty" json:"thumbnails,omitempty"`
+		Tags            []string   `bson:"tags,omitempty" json:"tags,omitempty"`
+		DefaultLanguage string     `bson:"defaultLanguage,omitempty" json:"defaultLanguage,omitempty"`
+	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
+	Status *struct {
+		PrivacyStatus string `bson:"privacyStatus,omitempty" json:"privacyStatus,omitempty"`
+	} `bson:"status,omitempty" json:"status,omitempty"`
+	ContentDetails *struct {
+		ItemCount int `bson:"itemCount,omitempty" json:"itemCount,omitempty"`
+	} `bson:"contentDetails,omitempty" json:"contentDetails,omitempty"`
+}
+
+// PlaylistResults contains the playlists retrieved for a channel.
+type PlaylistResults struct {
+	Items         []*Playlist `bson:"items,omitempty" json:"items,omitempty"`
+	NextPageToken string      `bson:"nextPageToken,omitempty" json:"nextPageToken,omitempty"`
+}
+
+// PlaylistItem represents a single entry of a playlist.
+type PlaylistItem struct {
+	Id      string `bson:"id,omitempty" json:"id,omitempty"`
+	Snippet *struct {
+		PublishedAt  time.Time   `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+		ChannelId    string      `bson:"channelId,omitempty" json:"channelId,omitempty"`
+		ChannelTitle string      `bson:"channelTitle,omitempty" json:"channelTitle,omitempty"`
+		Title        string      `bson:"title,omitempty" json:"title,omitempty"`
+		Description  string      `bson:"description,omitempty" json:"description,omitempty"`
+		Thumbnails   Thumbnails  `bson:"thumbnails,omitempty" json:"thumbnails,omitempty"`
+		PlaylistId   string      `bson:"playlistId,omitempty" json:"playlistId,omitempty"`
+		Position     int         `bson:"position" json:"position"`
+		ResourceId   *ResourceId `bson:"resourceId,omitempty" json:"resourceId,omitempty"`
+	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
+	ContentDetails *struct {
+		VideoId          string    `bson:"videoId,omitempty" json:"videoId,omitempty"`
+		VideoPublishedAt time.Time `bson:"videoPublishedAt,omitempty" json:"videoPublishedAt,omitempty"`
+	} `bson:"contentDetails,omitempty" json:"contentDetails,omitempty"`
+}
+
+// ResourceId identifies the video, channel, or playlist a resource refers to.
+type ResourceId struct {
+	Kind       string `bson:"kind,omitempty" json:"kind,omitempty"`
+	VideoId    string `bson:"videoId,omitempty" json:"videoId,omitempty"`
+	ChannelId  string `bson:"channelId,omitempty" json:"channelId,omitempty"`
+	PlaylistId string `bson:"playlistId,omitempty" json:"playlistId,omitempty"`
+}
+
+// PlaylistInput holds the metadata of a playlist to create.
+type PlaylistInput struct {
+	Title       string
+	Description string
+	Tags        []string
+	// PrivacyStatus is one of PrivacyPublic, PrivacyPrivate (the default), or PrivacyUnlisted.
+	PrivacyStatus   string
+	DefaultLanguage string
+}
+
+// CreatePlaylist creates a playlist owned by the authenticated user. Requires OAuth2 credentials.
+func (yt *YoutubeApi) CreatePlaylist(input PlaylistInput) (*Playlist, error) {
+	return yt.CreatePlaylistContext(context.Background(), input)
+}
+
+// CreatePlaylistContext is like CreatePlaylist but uses ctx for the underlying API request.
+func (yt *YoutubeApi) CreatePlaylistContext(ctx context.Context, input PlaylistInput) (*Playlist, error) {
+	if input.PrivacyStatus == "" {
+		input.PrivacyStatus = PrivacyPrivate
+	}
+	body := map[string]interface{}{
+		"snippet": map[string]interface{}{
+			"title":           input.Title,
+			"description":     input.Description,
+			"tags":            input.Tags,
+			"defaultLanguage": input.DefaultLanguage,
+		},
+		"status": map[string]interface{}{
+			"privacyStatus": input.PrivacyStatus,
+		},
+	}
+	playlist := &Playlist{}
+	if err := yt.userJSONRequest(ctx, http.MethodPost, fmt.Sprintf(InsertPlaylist, yt.apiKey), body, playlist); err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// AddVideoToPlaylist appends a video to the end of a playlist. Requires OAuth2 credentials.
+func (yt *YoutubeApi) AddVideoToPlaylist(playlistId, videoId string) (*PlaylistItem, error) {
+	return yt.AddVideoToPlaylistContext(context.Background(), playlistId, videoId)
+}
+
+// AddVideoToPlaylistContext is like AddVideoToPlaylist but uses ctx for the underlying API request.
+func (yt *YoutubeApi) AddVideoToPlaylistContext(ctx context.Context, playlistId, videoId string) (*PlaylistItem, error) {
+	body := map[string]interface{}{
+		"snippet": map[string]interface{}{
+			"playlistId": playlistId,
+			"resourceId": &ResourceId{Kind: "youtube#video", VideoId: videoId},
+		},
+	}
+	item := &PlaylistItem{}
+	if err := yt.userJSONRequest(ctx, http.MethodPost, fmt.Sprintf(InsertPlaylistItem, yt.apiKey), body, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// AddVideosToPlaylist appends every video of results, in order, to a playlist,
+// e.g. to build a curated playlist from FindTags results. It stops at the first failure
+// and returns the items added so far.
+func (yt *YoutubeApi) AddVideosToPlaylist(ctx context.Context, playlistId string, results *VideoResults) ([]*PlaylistItem, error) {
+	var items []*PlaylistItem
+	for _, v := range results.Items {
+		item, err := yt.AddVideoToPlaylistContext(ctx, playlistId, v.Id)
+		if err != nil {
+			return items, fmt.Errorf("failed adding video %s to playlist %s: %w", v.Id, playlistId, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// RemovePlaylistItem deletes a playlist item by its item ID (not the video ID). Requires OAuth2 credentials.
+func (yt *YoutubeApi) RemovePlaylistItem(itemId string) error {
+	return yt.RemovePlaylistItemContext(context.Background(), itemId)
+}
+
+// RemovePlaylistItemContext is like RemovePlaylistItem but uses ctx for the underlying API request.
+func (yt *YoutubeApi) RemovePlaylistItemContext(ctx context.Context, itemId string) error {
+	return yt.userJSONRequest(ctx, http.MethodDelete, fmt.Sprintf(DeletePlaylistItem, url.QueryEscape(itemId), yt.apiKey), nil, nil)
+}
+
+// ReorderPlaylistItem moves an existing playlist item to the zero-based position. The item must
+// carry its snippet, as returned by AddVideoToPlaylist or a playlist listing. Requires OAuth2 credentials.
+func (yt *YoutubeApi) ReorderPlaylistItem(item *PlaylistItem, position int) (*PlaylistItem, error) {
+	return yt.ReorderPlaylistItemContext(context.Background(), item, position)
+}
+
+// ReorderPlaylistItemContext is like ReorderPlaylistItem but uses ctx for the underlying API request.
+func (yt *YoutubeApi) ReorderPlaylistItemContext(ctx context.Context, item *PlaylistItem, position int) (*PlaylistItem, error) {
+	if item == nil || item.Snippet == nil || item.Snippet.ResourceId == nil {
+		return nil, fmt.Errorf("playlist item is missing its snippet or resourceId")
+	}
+	body := map[string]interface{}{
+		"id": item.Id,
+		"snippet": map[string]interface{}{
+			"playlistId": item.Snippet.PlaylistId,
+			"resourceId": item.Snippet.ResourceId,
+			"position":   position,
+		},
+	}
+	updated := &PlaylistItem{}
+	if err := yt.userJSONRequest(ctx, http.MethodPut, fmt.Sprintf(UpdatePlaylistItem, yt.apiKey), body, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetChannelPlaylists retrieves every public playlist created by a channel, with its title,
+// thumbnails, and item count. The uploads playlist isn't listed; use GetChannelPlaylist for it.
+// Results are not cached, since they are cheap to list and change with every edit.
+func (yt *YoutubeApi) GetChannelPlaylists(channelId string) (*PlaylistResults, error) {
+	return yt.GetChannelPlaylistsContext(context.Background(), channelId)
+}
+
+// GetChannelPlaylistsContext is like GetChannelPlaylists but uses ctx for every page request.
+func (yt *YoutubeApi) GetChannelPlaylistsContext(ctx context.Context, channelId string) (_ *PlaylistResults, err error) {
+	ctx, span := yt.startOperation(ctx, "GetChannelPlaylists")
+	defer func() { endSpan(span, err) }()
+
+	results := &PlaylistResults{}
+	nextPage := ""
+	for page := 0; ; page++ {
+		nextPageStr := ""
+		if nextPage != "" {
+			nextPageStr = fmt.Sprintf("&pageToken=%v", nextPage)
+		}
+		apiUrl := fmt.Sprintf(ListPlaylists, url.QueryEscape(channelId), yt.apiKey, nextPageStr)
+		body, err := yt.sendRequest(withSpanAttributes(ctx, AttrPage.Int(page)), http.MethodGet, apiUrl, nil, authAuto)
+		if err != nil {
+			return nil, err
+		}
+		res := &PlaylistResults{}
+		if err := json.Unmarshal(body, res); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal playlists: %w", err)
+		}
+		results.Items = append(results.Items, res.Items...)
+
+		if res.NextPageToken == "" || res.NextPageToken == nextPage {
+			break
+		}
+		nextPage = res.NextPageToken
+	}
+	return results, nil
+}
+
+// GetPlaylistVideos retrieves the first n videos of any playlist, with their details, in playlist
+// order. Results are cached.
+func (yt *YoutubeApi) GetPlaylistVideos(playlistId string, n int) (*VideoResults, error) {
+	return yt.GetPlaylistVideosContext(context.Background(), playlistId, n)
+}
+
+// GetPlaylistVideosContext is like GetPlaylistVideos but uses ctx for every page and video request.
+func (yt *YoutubeApi) GetPlaylistVideosContext(ctx context.Context, playlistId string, n int) (*VideoResults, error) {
+	return yt.GetPlaylistVideosFromContext(ctx, playlistId, "", n)
+}
+
+// GetPlaylistVideosFrom is like GetPlaylistVideos but starts at the page of pageToken, the
+// NextPageToken of earlier results, so a long walk of a playlist can be resumed after a restart.
+// The NextPageToken of the results continues the playlist after them, unless they were cut short
+// to n videos in the middle of a page. An empty pageToken starts at the first page.
+func (yt *YoutubeApi) GetPlaylistVideosFrom(playlistId, pageToken string, n int) (*VideoResults, error) {
+	return yt.GetPlaylistVideosFromContext(context.Background(), playlistId, pageToken, n)
+}
+
+// GetPlaylistVideosFromContext is like GetPlaylistVideosFrom but uses ctx for every page and video request.
+func (yt *YoutubeApi) GetPlaylistVideosFromContext(ctx context.Context, playlistId, pageToken string, n int) (_ *VideoResults, err error) {
+	ctx, span := yt.startOperation(ctx, "GetPlaylistVideos")
+	defer func() { endSpan(span, err) }()
+
+	cacheKey := playlistCacheKey(playlistId, pageToken, n)
+	if v := yt.Cache.GetPlaylist(cacheKey); v != nil {
+		span.SetAttributes(AttrCacheHit.Bool(true))
+		return v.shallowCopy(), nil
+	}
+	span.SetAttributes(AttrCacheHit.Bool(false))
+
+	v, err := yt.coalesce(ctx, RegionPlaylists, cacheKey, func() (interface{}, error) {
+		results, err := yt.getChannelPlaylist(ctx, playlistId, pageToken, n)
+		if err != nil {
+			return nil, err
+		}
+		nextPage := results.NextPageToken
+		if len(results.Items) > n {
+			// The videos after the first n are skipped, so the token would resume past them.
+			nextPage = ""
+		}
+		// Copied, since results may be shared with the video details cache.
+		results = &VideoResults{Items: append([]*Video(nil), results.Items[:min(max(n, 0), len(results.Items))]...), NextPageToken: nextPage, Unavailable: results.Unavailable}
+		yt.Cache.SetPlaylist(cacheKey, results)
+		if pageToken == "" {
+			yt.persistPlaylist(ctx, playlistId, results)
+		}
+		return results, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*VideoResults).shallowCopy(), nil
+}
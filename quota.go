@@ -0,0 +1,60 @@
+package alaitube
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Quota costs of YouTube Data API v3 operations, in units of the daily quota.
+const (
+	QuotaCostRead            = 1
+	QuotaCostWrite           = 50
+	QuotaCostSearch          = 100
+	QuotaCostCaptionDownload = 200
+	QuotaCostVideoUpload     = 1600
+)
+
+// endpointName returns the short name of the API endpoint rawUrl points to, such as "videos",
+// "search", or "upload/videos", for labelling metrics and estimating quota.
+// Requests outside the Data API, like timedtext transcripts, are named after their last path segment.
+func endpointName(u *url.URL) string {
+	path := strings.Trim(u.Path, "/")
+	upload := strings.HasPrefix(path, "upload/")
+	path = strings.TrimPrefix(path, "upload/")
+	if !strings.HasPrefix(path, "youtube/v3/") {
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	path = strings.TrimPrefix(path, "youtube/v3/")
+	if strings.HasPrefix(path, "captions/") {
+		// captions/{id} downloads a caption track; the ID is not part of the endpoint.
+		path = "captions/download"
+	}
+	if upload {
+		path = "upload/" + path
+	}
+	return path
+}
+
+// QuotaCost returns the quota units a request to endpoint with the given HTTP method consumes.
+// Endpoint names are those reported in RequestInfo.Endpoint. Requests that don't touch the
+// Data API, such as transcript downloads and upload chunks, cost nothing.
+func QuotaCost(method, endpoint string) int {
+	switch {
+	case endpoint == "search":
+		return QuotaCostSearch
+	case endpoint == "captions/download":
+		return QuotaCostCaptionDownload
+	case endpoint == "upload/videos":
+		// Only the request starting a resumable upload is charged, not the chunks that follow it.
+		if method == http.MethodPost {
+			return QuotaCostVideoUpload
+		}
+		return 0
+	case endpoint == "timedtext":
+		return 0
+	case method == http.MethodGet:
+		return QuotaCostRead
+	}
+	return QuotaCostWrite
+}
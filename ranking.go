@@ -0,0 +1,129 @@
+package alaitube
+
+import (
+	"context"
+	"time"
+)
+
+// Ranking is the position of a video or channel in the search results of a query.
+type Ranking struct {
+	Query string `bson:"query" json:"query"`
+	// VideoId is the video found, which for channel rankings is the channel's best ranked video.
+	VideoId   string `bson:"videoId,omitempty" json:"videoId,omitempty"`
+	ChannelId string `bson:"channelId,omitempty" json:"channelId,omitempty"`
+	// Position is the 1-based position of the video in the results, or 0 when it wasn't found.
+	Position int `bson:"position" json:"position"`
+	// Page is the 1-based search page the video was found on, or 0 when it wasn't found.
+	Page int `bson:"page" json:"page"`
+	// Scanned is the number of results searched through.
+	Scanned   int       `bson:"scanned" json:"scanned"`
+	CheckedAt time.Time `bson:"checkedAt" json:"checkedAt"`
+}
+
+// Found reports whether the video or channel was found in the results.
+func (r *Ranking) Found() bool {
+	return r.Position > 0
+}
+
+// RankForQuery searches query and returns the position of videoId in the results over the first
+// maxPages pages of 50 results. The search stops at the page the video is found on, so each page
+// searched costs QuotaCostSearch units. Results are ordered by relevance, like on YouTube, unless
+// the optional SearchOptions set an Order. Rankings aren't cached.
+func (yt *YoutubeApi) RankForQuery(videoId, query string, maxPages int, opts ...SearchOptions) (*Ranking, error) {
+	return yt.RankForQueryContext(context.Background(), videoId, query, maxPages, opts...)
+}
+
+// RankForQueryContext is like RankForQuery but uses ctx for every page request.
+func (yt *YoutubeApi) RankForQueryContext(ctx context.Context, videoId, query string, maxPages int, opts ...SearchOptions) (_ *Ranking, err error) {
+	ctx, span := yt.startOperation(ctx, "RankForQuery")
+	defer func() { endSpan(span, err) }()
+
+	return yt.rank(ctx, query, maxPages, firstSearchOptions(opts), func(id, _ string) bool { return id == videoId })
+}
+
+// RankChannelForQuery is like RankForQuery but returns the position of the best ranked video of
+// channelId.
+func (yt *YoutubeApi) RankChannelForQuery(channelId, query string, maxPages int, opts ...SearchOptions) (*Ranking, error) {
+	return yt.RankChannelForQueryContext(context.Background(), channelId, query, maxPages, opts...)
+}
+
+// RankChannelForQueryContext is like RankChannelForQuery but uses ctx for every page request.
+func (yt *YoutubeApi) RankChannelForQueryContext(ctx context.Context, channelId, query string, maxPages int, opts ...SearchOptions) (_ *Ranking, err error) {
+	ctx, span := yt.startOperation(ctx, "RankChannelForQuery")
+	defer func() { endSpan(span, err) }()
+
+	return yt.rank(ctx, query, maxPages, firstSearchOptions(opts), func(_, channel string) bool { return channel == channelId })
+}
+
+// RankForQueries tracks videoId across many keywords, returning one Ranking per query in order.
+// When a search fails, the rankings of the previous queries are returned along with the error.
+func (yt *YoutubeApi) RankForQueries(videoId string, queries []string, maxPages int, opts ...SearchOptions) ([]*Ranking, error) {
+	return yt.RankForQueriesContext(context.Background(), videoId, queries, maxPages, opts...)
+}
+
+// RankForQueriesContext is like RankForQueries but uses ctx for every request.
+func (yt *YoutubeApi) RankForQueriesContext(ctx context.Context, videoId string, queries []string, maxPages int, opts ...SearchOptions) ([]*Ranking, error) {
+	return rankQueries(queries, func(query string) (*Ranking, error) {
+		return yt.RankForQueryContext(ctx, videoId, query, maxPages, opts...)
+	})
+}
+
+// RankChannelForQueries tracks channelId across many keywords, like RankForQueries.
+func (yt *YoutubeApi) RankChannelForQueries(channelId string, queries []string, maxPages int, opts ...SearchOptions) ([]*Ranking, error) {
+	return yt.RankChannelForQueriesContext(context.Background(), channelId, queries, maxPages, opts...)
+}
+
+// RankChannelForQueriesContext is like RankChannelForQueries but uses ctx for every request.
+func (yt *YoutubeApi) RankChannelForQueriesContext(ctx context.Context, channelId string, queries []string, maxPages int, opts ...SearchOptions) ([]*Ranking, error) {
+	return rankQueries(queries, func(query string) (*Ranking, error) {
+		return yt.RankChannelForQueryContext(ctx, channelId, query, maxPages, opts...)
+	})
+}
+
+// rankQueries ranks every query with rank, stopping at the first error.
+func rankQueries(queries []string, rank func(query string) (*Ranking, error)) ([]*Ranking, error) {
+	rankings := make([]*Ranking, 0, len(queries))
+	for _, query := range queries {
+		ranking, err := rank(query)
+		if err != nil {
+			return rankings, err
+		}
+		rankings = append(rankings, ranking)
+	}
+	return rankings, nil
+}
+
+// rank searches query page by page until a result of the given video and channel IDs matches.
+func (yt *YoutubeApi) rank(ctx context.Context, query string, maxPages int, opts SearchOptions, match func(videoId, channelId string) bool) (*Ranking, error) {
+	if opts.Order == "" {
+		opts.Order = OrderRelevance
+	}
+	ranking := &Ranking{Query: query, CheckedAt: time.Now()}
+	nextPage := ""
+	for i := 0; i < maxPages; i++ {
+		res, err := yt.searchPage(withSpanAttributes(ctx, AttrPage.Int(i)), query, nextPage, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range res.Items {
+			if item.Id == nil || item.Id.VideoId == "" {
+				continue
+			}
+			ranking.Scanned++
+			channelId := ""
+			if item.Snippet != nil {
+				channelId = item.Snippet.ChannelId
+			}
+			if match(item.Id.VideoId, channelId) {
+				ranking.VideoId, ranking.ChannelId = item.Id.VideoId, channelId
+				ranking.Position, ranking.Page = ranking.Scanned, i+1
+				return ranking, nil
+			}
+		}
+		nextPage = res.NextPageToken
+		if nextPage == "" {
+			break
+		}
+	}
+	return ranking, nil
+}
@@ -0,0 +1,48 @@
+package alaitube
+
+import (
+	"context"
+	"net/url"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit caps outbound requests of the client to rps per second, allowing bursts of up to burst
+// requests. Every attempt counts, retries included. Calls wait for a token or until their context ends.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(yt *YoutubeApi) {
+		yt.limiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+}
+
+// WithRateLimiter shares limiter between clients, so several clients using the same key
+// stay within a single budget.
+func WithRateLimiter(limiter *rate.Limiter) Option {
+	return func(yt *YoutubeApi) {
+		yt.limiter = limiter
+	}
+}
+
+// WithEndpointRateLimit caps requests to a single endpoint, such as "search", on top of the
+// client-wide limit. Endpoint names are those reported in RequestInfo.Endpoint.
+func WithEndpointRateLimit(endpoint string, rps float64, burst int) Option {
+	return func(yt *YoutubeApi) {
+		if yt.endpointLimiters == nil {
+			yt.endpointLimiters = make(map[string]*rate.Limiter)
+		}
+		yt.endpointLimiters[endpoint] = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+}
+
+// waitRateLimit blocks until the client-wide and endpoint limiters allow a request to u.
+func (yt *YoutubeApi) waitRateLimit(ctx context.Context, u *url.URL) error {
+	if l, ok := yt.endpointLimiters[endpointName(u)]; ok {
+		if err := l.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if yt.limiter != nil {
+		return yt.limiter.Wait(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package alaitube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const GetVideoRating = "https://www.googleapis.com/youtube/v3/videos/getRating?id=%s&key=%s"
+const RateVideo = "https://www.googleapis.com/youtube/v3/videos/rate?id=%s&rating=%s&key=%s"
+
+// Ratings accepted by RateVideo and reported by GetVideoRatings.
+const (
+	RatingLike    = "like"
+	RatingDislike = "dislike"
+	RatingNone    = "none"
+)
+
+// VideoRating is the authenticated user's rating of a video.
+type VideoRating struct {
+	VideoId string `bson:"videoId,omitempty" json:"videoId,omitempty"`
+	// Rating is RatingLike, RatingDislike, or RatingNone, or "unspecified" when YouTube doesn't know.
+	Rating string `bson:"rating,omitempty" json:"rating,omitempty"`
+}
+
+// GetVideoRatings returns the authenticated user's rating of each video, keyed by video ID.
+// Requires OAuth2 credentials. Ratings are not cached since they change with every RateVideo call.
+func (yt *YoutubeApi) GetVideoRatings(videoIds []string) (map[string]string, error) {
+	return yt.GetVideoRatingsContext(context.Background(), videoIds)
+}
+
+// GetVideoRatingsContext is like GetVideoRatings but uses ctx for every batch request.
+func (yt *YoutubeApi) GetVideoRatingsContext(ctx context.Context, videoIds []string) (_ map[string]string, err error) {
+	ctx, span := yt.startOperation(ctx, "GetVideoRatings")
+	defer func() { endSpan(span, err) }()
+
+	ratings := make(map[string]string, len(videoIds))
+	for _, batch := range batchIteration(uniqueIds(videoIds)) {
+		res := &struct {
+			Items []*VideoRating `json:"items"`
+		}{}
+		apiUrl := fmt.Sprintf(GetVideoRating, url.QueryEscape(batch), yt.apiKey)
+		if err := yt.userJSONRequest(ctx, http.MethodGet, apiUrl, nil, res); err != nil {
+			return nil, err
+		}
+		for _, item := range res.Items {
+			ratings[item.VideoId] = item.Rating
+		}
+	}
+	return ratings, nil
+}
+
+// RateVideo likes or dislikes a video on behalf of the authenticated user, or removes their
+// rating with RatingNone. Requires OAuth2 credentials.
+func (yt *YoutubeApi) RateVideo(videoId, rating string) error {
+	return yt.RateVideoContext(context.Background(), videoId, rating)
+}
+
+// RateVideoContext is like RateVideo but uses ctx for the underlying API request.
+func (yt *YoutubeApi) RateVideoContext(ctx context.Context, videoId, rating string) error {
+	switch rating {
+	case RatingLike, RatingDislike, RatingNone:
+	default:
+		return fmt.Errorf("invalid rating %q", rating)
+	}
+	apiUrl := fmt.Sprintf(RateVideo, url.QueryEscape(videoId), rating, yt.apiKey)
+	return yt.userJSONRequest(ctx, http.MethodPost, apiUrl, nil, nil)
+}
+
+// RateVideos rates every video of results, in order, e.g. to like the videos surfaced by FindTags.
+// It stops at the first failure and returns how many videos were rated.
+func (yt *YoutubeApi) RateVideos(ctx context.Context, results *VideoResults, rating string) (int, error) {
+	for i, v := range results.Items {
+		if err := yt.RateVideoContext(ctx, v.Id, rating); err != nil {
+			return i, fmt.Errorf("failed rating video %s: %w", v.Id, err)
+		}
+	}
+	return len(results.Items), nil
+}
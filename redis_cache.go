@@ -0,0 +1,348 @@
+package alaitube
+
+import (
+	"encoding/json"
+	"github.com/go-redis/redis"
+	"time"
+)
+
+// DefaultRedisTTL is the expiration applied to Redis entries when no TTL is configured for a region.
+const DefaultRedisTTL = 24 * time.Hour
+
+// DefaultRedisKeyPrefix is prepended to every key written by a RedisCache.
+const DefaultRedisKeyPrefix = "alaitube:"
+
+// RedisCache is a Cache backed by Redis, so several service instances can share cached results.
+// Values are stored as JSON under "<prefix><region>:<key>".
+type RedisCache struct {
+	client Redis
+	prefix string
+	ttls   map[CacheRegion]time.Duration
+	logger Logger
+	negTTL time.Duration
+	hits   hitCounters
+}
+
+// RedisCacheOption configures a RedisCache created with NewRedisCache.
+type RedisCacheOption func(*RedisCache)
+
+// WithKeyPrefix sets the prefix prepended to every Redis key. Without a prefix, the cache shares
+// the keyspace with the rest of the database, and PurgeAll only removes the keys of its regions.
+func WithKeyPrefix(prefix string) RedisCacheOption {
+	return func(c *RedisCache) {
+		c.prefix = prefix
+	}
+}
+
+// WithRedisTTL sets the expiration used for every region.
+// A zero TTL stores entries without expiration.
+func WithRedisTTL(ttl time.Duration) RedisCacheOption {
+	return func(c *RedisCache) {
+		for _, region := range cacheRegions {
+			c.ttls[region] = ttl
+		}
+	}
+}
+
+// WithRedisRegionTTL sets the expiration used for a single region.
+func WithRedisRegionTTL(region CacheRegion, ttl time.Duration) RedisCacheOption {
+	return func(c *RedisCache) {
+		c.ttls[region] = ttl
+	}
+}
+
+// WithRedisNegativeTTL sets how long not-found results are remembered.
+func WithRedisNegativeTTL(ttl time.Duration) RedisCacheOption {
+	return func(c *RedisCache) {
+		c.negTTL = ttl
+	}
+}
+
+// WithRedisLogger sets the Logger Redis failures are reported to.
+// A nil logger is ignored and the default slog adapter is kept.
+func WithRedisLogger(logger Logger) RedisCacheOption {
+	return func(c *RedisCache) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// NewRedisCache creates a RedisCache using the given client, typically a *redis.Client.
+func NewRedisCache(client Redis, opts ...RedisCacheOption) *RedisCache {
+	c := &RedisCache{
+		client: client,
+		prefix: DefaultRedisKeyPrefix,
+		ttls:   make(map[CacheRegion]time.Duration),
+		logger: NewSlogLogger(nil),
+		negTTL: DefaultNegativeTTL,
+		hits:   newHitCounters(),
+	}
+	for _, region := range cacheRegions {
+		c.ttls[region] = DefaultRedisTTL
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *RedisCache) key(region CacheRegion, key string) string {
+	return c.prefix + string(region) + ":" + key
+}
+
+// get loads the entry for key in region into v, reporting whether it was found.
+func (c *RedisCache) get(region CacheRegion, key string, v interface{}) (found bool) {
+	defer func() { c.hits.record(region, found) }()
+	data, err := c.client.Get(c.key(region, key)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			c.logger.Warn("redis cache get failed", Field{"region", region}, Field{"error", err})
+		}
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		c.logger.Warn("redis cache decode failed", Field{"region", region}, Field{"error", err})
+		return false
+	}
+	return true
+}
+
+// set stores v under key in region with the region's TTL.
+func (c *RedisCache) set(region CacheRegion, key string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		c.logger.Warn("redis cache encode failed", Field{"region", region}, Field{"error", err})
+		return
+	}
+	if err := c.client.Set(c.key(region, key), data, c.ttls[region]).Err(); err != nil {
+		c.logger.Warn("redis cache set failed", Field{"region", region}, Field{"error", err})
+	}
+}
+
+// notFoundKey is the Redis key of the negative record of key in region, kept apart from
+// the value so a later successful fetch simply takes precedence.
+func (c *RedisCache) notFoundKey(region CacheRegion, key string) string {
+	return c.prefix + "notfound:" + string(region) + ":" + key
+}
+
+// SetNotFound records key as missing in region for the negative TTL.
+func (c *RedisCache) SetNotFound(region CacheRegion, key string) {
+	if c.negTTL <= 0 {
+		return
+	}
+	if err := c.client.Set(c.notFoundKey(region, key), "1", c.negTTL).Err(); err != nil {
+		c.logger.Warn("redis cache set failed", Field{"region", region}, Field{"error", err})
+	}
+}
+
+// IsNotFound reports whether key is recorded as missing in region.
+func (c *RedisCache) IsNotFound(region CacheRegion, key string) bool {
+	err := c.client.Get(c.notFoundKey(region, key)).Err()
+	if err != nil && err != redis.Nil {
+		c.logger.Warn("redis cache get failed", Field{"region", region}, Field{"error", err})
+	}
+	return err == nil
+}
+
+// Delete removes the entry for key in region, together with its not-found record.
+func (c *RedisCache) Delete(region CacheRegion, key string) {
+	if err := c.client.Del(c.key(region, key), c.notFoundKey(region, key)).Err(); err != nil {
+		c.logger.Warn("redis cache delete failed", Field{"region", region}, Field{"error", err})
+	}
+}
+
+// PurgeRegion removes every entry of region. Keys are found with SCAN, so purging doesn't
+// block Redis, but entries written during the purge may survive it.
+func (c *RedisCache) PurgeRegion(region CacheRegion) {
+	c.deleteMatching(c.key(region, "*"))
+	c.deleteMatching(c.notFoundKey(region, "*"))
+}
+
+// PurgeAll removes every entry written with the cache's key prefix, or the entries of every
+// region when the prefix is empty, rather than the whole database.
+func (c *RedisCache) PurgeAll() {
+	if c.prefix == "" {
+		for _, region := range cacheRegions {
+			c.PurgeRegion(region)
+		}
+		return
+	}
+	c.deleteMatching(c.prefix + "*")
+}
+
+// Stats returns the lookups made through c and the number of entries of every region, counted
+// with SCAN, which takes a while on large databases. Bytes is unknown.
+func (c *RedisCache) Stats() CacheStats {
+	stats := c.hits.stats(c.GetServiceName())
+	for region, r := range stats.Regions {
+		entries, err := countMatching(c.client, c.key(region, "*"))
+		if err == nil {
+			var notFound int64
+			notFound, err = countMatching(c.client, c.notFoundKey(region, "*"))
+			entries += notFound
+		}
+		if err != nil {
+			c.logger.Warn("redis cache scan failed", Field{"region", region}, Field{"error", err})
+			continue
+		}
+		r.Entries = entries
+		stats.Regions[region] = r
+	}
+	return stats
+}
+
+// countMatching counts the keys matching the SCAN pattern.
+func countMatching(client Redis, pattern string) (int64, error) {
+	var count int64
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(cursor, pattern, 500).Result()
+		if err != nil {
+			return 0, err
+		}
+		count += int64(len(keys))
+		if next == 0 {
+			return count, nil
+		}
+		cursor = next
+	}
+}
+
+// deleteMatching deletes every key matching the SCAN pattern.
+func (c *RedisCache) deleteMatching(pattern string) {
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(cursor, pattern, 500).Result()
+		if err != nil {
+			c.logger.Warn("redis cache scan failed", Field{"pattern", pattern}, Field{"error", err})
+			return
+		}
+		if len(keys) > 0 {
+			if err := c.client.Del(keys...).Err(); err != nil {
+				c.logger.Warn("redis cache delete failed", Field{"pattern", pattern}, Field{"error", err})
+				return
+			}
+		}
+		if next == 0 {
+			return
+		}
+		cursor = next
+	}
+}
+
+// GetVideo retrieves a video from Cache.
+func (c *RedisCache) GetVideo(key string) *VideoResults {
+	v := &VideoResults{}
+	if !c.get(RegionVideos, key, v) {
+		return nil
+	}
+	return v
+}
+
+// SetVideo stores a video to Cache.
+func (c *RedisCache) SetVideo(key string, video *VideoResults) {
+	if video != nil {
+		c.set(RegionVideos, key, video)
+	}
+}
+
+// GetChannel retrieves a channel from Cache.
+func (c *RedisCache) GetChannel(key string) *ChannelInfo {
+	v := &ChannelInfo{}
+	if !c.get(RegionChannels, key, v) {
+		return nil
+	}
+	return v
+}
+
+// SetChannel stores a channel to Cache.
+func (c *RedisCache) SetChannel(key string, channel *ChannelInfo) {
+	if channel != nil {
+		c.set(RegionChannels, key, channel)
+	}
+}
+
+// GetPlaylist retrieves a playlist from Cache.
+func (c *RedisCache) GetPlaylist(key string) *VideoResults {
+	v := &VideoResults{}
+	if !c.get(RegionPlaylists, key, v) {
+		return nil
+	}
+	return v
+}
+
+// SetPlaylist stores a playlist to Cache.
+func (c *RedisCache) SetPlaylist(key string, playlist *VideoResults) {
+	if playlist != nil {
+		c.set(RegionPlaylists, key, playlist)
+	}
+}
+
+// GetVideoDetail retrieves a VideoDetail from Cache.
+func (c *RedisCache) GetVideoDetail(key string) *VideoResults {
+	v := &VideoResults{}
+	if !c.get(RegionVideoDetails, key, v) {
+		return nil
+	}
+	return v
+}
+
+// SetVideoDetail stores a VideoDetail to Cache.
+func (c *RedisCache) SetVideoDetail(key string, detail *VideoResults) {
+	if detail != nil {
+		c.set(RegionVideoDetails, key, detail)
+	}
+}
+
+// GetCommentThreads retrieves comment threads from Cache.
+func (c *RedisCache) GetCommentThreads(key string) *CommentThreadResults {
+	v := &CommentThreadResults{}
+	if !c.get(RegionComments, key, v) {
+		return nil
+	}
+	return v
+}
+
+// SetCommentThreads stores comment threads to Cache.
+func (c *RedisCache) SetCommentThreads(key string, threads *CommentThreadResults) {
+	if threads != nil {
+		c.set(RegionComments, key, threads)
+	}
+}
+
+// GetCategories retrieves video categories from Cache.
+func (c *RedisCache) GetCategories(key string) *VideoCategoryResults {
+	v := &VideoCategoryResults{}
+	if !c.get(RegionCategories, key, v) {
+		return nil
+	}
+	return v
+}
+
+// SetCategories stores video categories to Cache.
+func (c *RedisCache) SetCategories(key string, categories *VideoCategoryResults) {
+	if categories != nil {
+		c.set(RegionCategories, key, categories)
+	}
+}
+
+// GetSubscriptions retrieves subscriptions from Cache.
+func (c *RedisCache) GetSubscriptions(key string) *SubscriptionResults {
+	v := &SubscriptionResults{}
+	if !c.get(RegionSubscriptions, key, v) {
+		return nil
+	}
+	return v
+}
+
+// SetSubscriptions stores subscriptions to Cache.
+func (c *RedisCache) SetSubscriptions(key string, subscriptions *SubscriptionResults) {
+	if subscriptions != nil {
+		c.set(RegionSubscriptions, key, subscriptions)
+	}
+}
+
+func (c *RedisCache) GetServiceName() string {
+	return "redis-cache"
+}
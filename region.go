@@ -0,0 +1,82 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Region is a typed view of one region of a CacheV2 backend. Values are stored as JSON, so a
+// new cached resource only needs a region name and a type, not a change to every backend:
+//
+//	captions := alaitube.NewRegion[*alaitube.CaptionTracks](store, "captions", alaitube.WithRegionTTL(time.Hour))
+//	tracks, ok, err := captions.Get(ctx, videoId)
+//
+// The Cache interface keeps its typed Get and Set pairs for the resources cached by the client.
+type Region[T any] struct {
+	backend CacheV2
+	name    CacheRegion
+	ttl     time.Duration
+}
+
+// RegionOption configures a Region created with NewRegion.
+type RegionOption func(*regionConfig)
+
+type regionConfig struct {
+	ttl time.Duration
+}
+
+// WithRegionTTL sets the expiration of values written through the region.
+// When unset, the backend's TTL for the region is used.
+func WithRegionTTL(ttl time.Duration) RegionOption {
+	return func(c *regionConfig) {
+		c.ttl = ttl
+	}
+}
+
+// NewRegion returns a typed view of the region name of backend.
+func NewRegion[T any](backend CacheV2, name CacheRegion, opts ...RegionOption) *Region[T] {
+	cfg := regionConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Region[T]{backend: backend, name: name, ttl: cfg.ttl}
+}
+
+// Name returns the name of the region.
+func (r *Region[T]) Name() CacheRegion {
+	return r.name
+}
+
+// Get returns the value stored under key and whether it was found.
+func (r *Region[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var v T
+	data, ok, err := r.backend.Get(ctx, r.name, key)
+	if err != nil || !ok {
+		return v, false, err
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, false, fmt.Errorf("failed to decode %s cache entry: %w", r.name, err)
+	}
+	return v, true, nil
+}
+
+// Set stores value under key.
+func (r *Region[T]) Set(ctx context.Context, key string, value T) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s cache entry: %w", r.name, err)
+	}
+	return r.backend.Set(ctx, r.name, key, data, r.ttl)
+}
+
+// Delete removes the value stored under key.
+func (r *Region[T]) Delete(ctx context.Context, key string) error {
+	return r.backend.Delete(ctx, r.name, key)
+}
+
+// Purge removes every value of the region.
+func (r *Region[T]) Purge(ctx context.Context) error {
+	return r.backend.PurgeRegion(ctx, r.name)
+}
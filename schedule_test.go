@@ -0,0 +1,115 @@
+package alaitube_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/josephalai/alaitube"
+)
+
+func TestScheduleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, time.UTC)
+	}
+	// 2026-10-17 is a Saturday.
+	tests := []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{name: "step over every value", spec: "*/15 * * * *", after: utc(10, 17, 10, 7), want: utc(10, 17, 10, 15)},
+		{name: "step over a range", spec: "0-30/10 9 * * *", after: utc(10, 17, 9, 25), want: utc(10, 17, 9, 30)},
+		{name: "step past the end of its range", spec: "0-30/10 9 * * *", after: utc(10, 17, 9, 30), want: utc(10, 18, 9, 0)},
+		{name: "list", spec: "5,45 * * * *", after: utc(10, 17, 10, 5), want: utc(10, 17, 10, 45)},
+		{name: "strictly after", spec: "0 12 * * *", after: utc(10, 17, 12, 0), want: utc(10, 18, 12, 0)},
+		{name: "day of month only", spec: "0 0 1 * *", after: utc(10, 17, 0, 0), want: utc(11, 1, 0, 0)},
+		{name: "day of week only", spec: "0 0 * * 1", after: utc(10, 17, 0, 0), want: utc(10, 19, 0, 0)},
+		{name: "either day field, week first", spec: "0 0 1 * 1", after: utc(10, 17, 0, 0), want: utc(10, 19, 0, 0)},
+		{name: "either day field, month first", spec: "0 0 1 * 1", after: utc(10, 26, 0, 0), want: utc(11, 1, 0, 0)},
+		// A day field starting with "*" is unrestricted, so both must match: Sunday, November 1st.
+		{name: "day of month with stepped day of week", spec: "0 0 1 * */7", after: utc(10, 17, 0, 0), want: utc(11, 1, 0, 0)},
+		{name: "7 is Sunday", spec: "0 12 * * 7", after: utc(10, 17, 0, 0), want: utc(10, 18, 12, 0)},
+		{name: "range ending on 7", spec: "0 12 * * 5-7", after: utc(10, 17, 13, 0), want: utc(10, 18, 12, 0)},
+		{name: "weekly descriptor", spec: "@weekly", after: utc(10, 17, 0, 0), want: utc(10, 18, 0, 0)},
+		{name: "fixed interval", spec: "@every 90m", after: utc(10, 17, 10, 0), want: utc(10, 17, 11, 30)},
+		{name: "date that never occurs", spec: "0 0 30 2 *", after: utc(10, 17, 0, 0), want: time.Time{}},
+		{
+			name:  "time skipped by the clocks turning forward",
+			spec:  "30 2 * * *",
+			after: time.Date(2026, 3, 8, 0, 0, 0, 0, newYork),
+			want:  time.Date(2026, 3, 9, 2, 30, 0, 0, newYork),
+		},
+		{
+			name:  "hourly across the clocks turning forward",
+			spec:  "0 * * * *",
+			after: time.Date(2026, 3, 8, 1, 30, 0, 0, newYork),
+			want:  time.Date(2026, 3, 8, 3, 0, 0, 0, newYork),
+		},
+		{
+			name:  "time repeated by the clocks turning back runs once",
+			spec:  "30 1 * * *",
+			after: time.Date(2026, 11, 1, 1, 30, 0, 0, newYork), // EDT, the first 1:30.
+			want:  time.Date(2026, 11, 2, 1, 30, 0, 0, newYork),
+		},
+		{
+			name:  "hourly across the clocks turning back",
+			spec:  "0 * * * *",
+			after: time.Date(2026, 11, 1, 1, 0, 0, 0, newYork), // EDT, the first 1:00.
+			want:  time.Date(2026, 11, 1, 1, 0, 0, 0, newYork).Add(time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := alaitube.ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q): %v", tt.spec, err)
+			}
+			if got := schedule.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 0s",
+		"@every -1m",
+		"@every soon",
+	} {
+		if _, err := alaitube.ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestAddScheduleInterval(t *testing.T) {
+	s := alaitube.NewClient(alaitube.WithApiKey("test")).NewScheduler()
+	task := func(context.Context) error { return nil }
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		if err := s.AddSchedule("job", alaitube.Every(interval), task); err == nil {
+			t.Errorf("AddSchedule(Every(%s)) succeeded, want an error", interval)
+		}
+	}
+	if err := s.AddSchedule("job", alaitube.Every(time.Minute), task); err != nil {
+		t.Errorf("AddSchedule(Every(1m)): %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+package alaitube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ScheduledJob describes a job of a Scheduler.
+type ScheduledJob struct {
+	Name string
+	// Next is the time the job runs next, and Last the start of its last run.
+	Next time.Time
+	Last time.Time
+	// LastErr is the error returned by the last run.
+	LastErr error
+	Running bool
+	// Skipped counts the runs skipped because the previous run was still going.
+	Skipped int
+}
+
+// scheduledJob is a job registered with a Scheduler.
+type scheduledJob struct {
+	ScheduledJob
+	schedule Schedule
+	task     func(context.Context) error
+}
+
+// Scheduler runs recurring jobs, such as refreshing queries hourly or snapshotting channels
+// daily, on cron-like schedules. A job whose previous run is still going when it is due again
+// skips that run, so slow jobs never overlap. The Refresh helpers re-fetch results into the
+// client's cache, keeping it warm for the code reading from it.
+type Scheduler struct {
+	yt       *YoutubeApi
+	location *time.Location
+	onError  func(name string, err error)
+
+	mu     sync.Mutex
+	jobs   map[string]*scheduledJob
+	wake   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+	tasks  sync.WaitGroup
+}
+
+// SchedulerOption configures a Scheduler created with NewScheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithSchedulerLocation sets the time zone cron specs are interpreted in. The default is time.Local.
+func WithSchedulerLocation(loc *time.Location) SchedulerOption {
+	return func(s *Scheduler) {
+		if loc != nil {
+			s.location = loc
+		}
+	}
+}
+
+// WithSchedulerErrorHandler sets the function job errors are reported to.
+// By default errors are reported to the client's Logger.
+func WithSchedulerErrorHandler(fn func(name string, err error)) SchedulerOption {
+	return func(s *Scheduler) {
+		if fn != nil {
+			s.onError = fn
+		}
+	}
+}
+
+// NewScheduler creates a Scheduler without jobs. Register them with Add, then call Run.
+func (yt *YoutubeApi) NewScheduler(opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		yt:       yt,
+		location: time.Local,
+		onError: func(name string, err error) {
+			yt.logger.Error("scheduled job failed", Field{"job", name}, Field{"error", err})
+		},
+		jobs: make(map[string]*scheduledJob),
+		wake: make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Add registers task to run on the cron spec, as parsed by ParseSchedule, under a unique name.
+func (s *Scheduler) Add(name, spec string, task func(context.Context) error) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+	return s.AddSchedule(name, schedule, task)
+}
+
+// AddSchedule registers task to run on schedule under a unique name. Jobs can be added while
+// the scheduler runs. A schedule whose next run isn't after the current time, such as Every with
+// an interval of zero or less, is rejected, since the job would run continuously.
+func (s *Scheduler) AddSchedule(name string, schedule Schedule, task func(context.Context) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("scheduled job %q already exists", name)
+	}
+	now := time.Now().In(s.location)
+	next := schedule.Next(now)
+	if !next.IsZero() && !next.After(now) {
+		return fmt.Errorf("invalid schedule for job %q: next run %s is not after the current time", name, next.Format(time.RFC3339))
+	}
+	job := &scheduledJob{ScheduledJob: ScheduledJob{Name: name}, schedule: schedule, task: task}
+	job.Next = next
+	s.jobs[name] = job
+	s.notify()
+	return nil
+}
+
+// Remove unregisters a job. A run in progress is allowed to finish.
+func (s *Scheduler) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, name)
+	s.notify()
+}
+
+// notify wakes Run up to recompute its next deadline. s.mu must be held.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Jobs returns the state of every job, sorted by name.
+func (s *Scheduler) Jobs() []ScheduledJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]ScheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job.ScheduledJob)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// Run starts the jobs when they are due until ctx is cancelled or Stop is called. Jobs run under
+// a context that outlives cancellation, so runs in progress finish before Run returns nil.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.done != nil {
+		s.mu.Unlock()
+		return errors.New("scheduler is already running")
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	done := s.done
+	s.mu.Unlock()
+
+	defer func() {
+		s.tasks.Wait()
+		s.mu.Lock()
+		s.cancel()
+		s.done = nil
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	for {
+		timer := time.NewTimer(s.startDue(ctx))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// idleWait is how long Run sleeps when no job is scheduled.
+const idleWait = time.Hour
+
+// startDue starts the jobs that are due and returns the time until the next one.
+func (s *Scheduler) startDue(ctx context.Context) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().In(s.location)
+	wait := idleWait
+	for _, job := range s.jobs {
+		if job.Next.IsZero() {
+			continue
+		}
+		if !job.Next.After(now) {
+			if job.Running {
+				job.Skipped++
+			} else {
+				s.start(context.WithoutCancel(ctx), job, now)
+			}
+			job.Next = job.schedule.Next(now)
+			if job.Next.IsZero() {
+				continue
+			}
+		}
+		wait = min(wait, job.Next.Sub(now))
+	}
+	return wait
+}
+
+// start runs job in its own goroutine. s.mu must be held.
+func (s *Scheduler) start(ctx context.Context, job *scheduledJob, now time.Time) {
+	job.Running, job.Last = true, now
+	s.tasks.Add(1)
+	go func() {
+		defer s.tasks.Done()
+		err := job.task(ctx)
+		s.mu.Lock()
+		job.Running, job.LastErr = false, err
+		s.mu.Unlock()
+		if err != nil {
+			s.onError(job.Name, err)
+		}
+	}()
+}
+
+// Stop ends a running Run call and waits for it to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if done == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// RefreshQuery registers a job re-running FindTags for query on the cron spec. The cached results
+// are dropped first, so each run fetches fresh results and caches them for the next readers.
+// callback, when not nil, receives the fresh results.
+func (s *Scheduler) RefreshQuery(name, spec, query string, numPages int, opts SearchOptions, callback func(context.Context, *VideoResults) error) error {
+	return s.Add(name, spec, func(ctx context.Context) error {
+		s.yt.Cache.Delete(RegionVideos, opts.cacheKey(query, numPages))
+		results, err := s.yt.FindTagsContext(ctx, query, numPages, opts)
+		if err != nil || callback == nil {
+			return err
+		}
+		return callback(ctx, results)
+	})
+}
+
+// RefreshChannels registers a job re-fetching channels on the cron spec. The cached channels are
+// dropped first, so each run fetches fresh channels and caches them for the next readers.
+// callback, when not nil, receives the fresh channels keyed by ID.
+func (s *Scheduler) RefreshChannels(name, spec string, channelIds []string, callback func(context.Context, map[string]*Item) error) error {
+	return s.Add(name, spec, func(ctx context.Context) error {
+		for _, id := range channelIds {
+			s.yt.Cache.Delete(RegionChannels, channelCacheKey(id, ""))
+		}
+		channels, err := s.yt.GetChannelsContext(ctx, channelIds)
+		if err != nil || callback == nil {
+			return err
+		}
+		return callback(ctx, channels)
+	})
+}
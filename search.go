@@ -0,0 +1,260 @@
+package alaitube
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const SearchEndpoint = "https://www.googleapis.com/youtube/v3/search"
+
+// Search orderings accepted by SearchOptions.Order.
+const (
+	OrderDate      = "date"
+	OrderRelevance = "relevance"
+	OrderViewCount = "viewCount"
+	OrderRating    = "rating"
+	OrderTitle     = "title"
+)
+
+// Video durations accepted by SearchOptions.VideoDuration.
+const (
+	DurationAny    = "any"
+	DurationShort  = "short"
+	DurationMedium = "medium"
+	DurationLong   = "long"
+)
+
+// Safe search levels accepted by SearchOptions.SafeSearch.
+const (
+	SafeSearchNone     = "none"
+	SafeSearchModerate = "moderate"
+	SafeSearchStrict   = "strict"
+)
+
+// SearchOptions refines the search requests made by Search, FindTags, and FindTagsStream.
+// Zero values keep YouTube's defaults, except Order and RelevanceLanguage which default
+// to OrderDate and "en" to match the package's historical behaviour.
+type SearchOptions struct {
+	Order             string
+	PublishedAfter    time.Time
+	PublishedBefore   time.Time
+	VideoDuration     string
+	RegionCode        string
+	RelevanceLanguage string
+	SafeSearch        string
+	// ChannelId restricts results to videos of a single channel.
+	ChannelId string
+	// Sort orders the aggregated FindTags results. It does not change the search request itself.
+	Sort SortOrder
+	// Limit trims the aggregated FindTags results to at most Limit videos. Zero keeps every video.
+	Limit int
+	// Shorts keeps or drops the Shorts of the results, classified with Video.IsShort. Like Sort
+	// and Limit, it doesn't change the search request itself.
+	Shorts ShortsFilter
+	// Languages keeps only the videos in one of a comma-separated list of ISO 639-1 codes, such as
+	// "en,es". The language declared by the uploader is used when there is one, otherwise it is
+	// detected from the title and description with the client's LanguageDetector. Unlike
+	// RelevanceLanguage, which only hints the search, it drops every other video.
+	Languages string
+	// Parts selects the video parts and fields fetched for the results, such as PartsMinimal.
+	Parts Parts
+	// PageToken starts the search at a page returned in the NextPageToken of an earlier search
+	// with the same query and options, so a long search can be resumed after a restart.
+	PageToken string
+}
+
+// values builds the query parameters of a search request for query.
+func (o SearchOptions) values(query string) url.Values {
+	v := url.Values{}
+	v.Set("part", "snippet")
+	v.Set("maxResults", "50")
+	v.Set("type", "video")
+	v.Set("q", query)
+	v.Set("order", OrderDate)
+	v.Set("relevanceLanguage", "en")
+	if o.Order != "" {
+		v.Set("order", o.Order)
+	}
+	if o.RelevanceLanguage != "" {
+		v.Set("relevanceLanguage", o.RelevanceLanguage)
+	}
+	if !o.PublishedAfter.IsZero() {
+		v.Set("publishedAfter", o.PublishedAfter.UTC().Format(time.RFC3339))
+	}
+	if !o.PublishedBefore.IsZero() {
+		v.Set("publishedBefore", o.PublishedBefore.UTC().Format(time.RFC3339))
+	}
+	if o.VideoDuration != "" {
+		v.Set("videoDuration", o.VideoDuration)
+	}
+	if o.RegionCode != "" {
+		v.Set("regionCode", o.RegionCode)
+	}
+	if o.SafeSearch != "" {
+		v.Set("safeSearch", o.SafeSearch)
+	}
+	if o.ChannelId != "" {
+		v.Set("channelId", o.ChannelId)
+	}
+	if o.PageToken != "" {
+		v.Set("pageToken", o.PageToken)
+	}
+	return v
+}
+
+// cacheKey returns the cache key of a FindTags search for query over numPages pages.
+func (o SearchOptions) cacheKey(query string, numPages int) string {
+	v := o.keyValues(query)
+	v.Set("pages", strconv.Itoa(numPages))
+	return CacheKey("search", v)
+}
+
+// keyValues returns the parameters identifying a search for query in a cache key: those of the
+// search request and the parts of the video details. Sort, Limit, Shorts, and Languages are
+// applied after the cache, so they don't take part in the key.
+func (o SearchOptions) keyValues(query string) url.Values {
+	v := o.values(query)
+	if o.Parts != (Parts{}) {
+		o.Parts.keyValues(v, "videos.")
+	}
+	return v
+}
+
+// markKey extends key with the options that differ from the defaults. It keys the marks of an
+// IncrementalCrawler, which are persisted and so keep the format of earlier releases.
+func (o SearchOptions) markKey(key string) string {
+	parts := o.Parts
+	o.Sort, o.Limit, o.Shorts, o.Languages, o.Parts = "", 0, ShortsAny, "", Parts{}
+	if parts != (Parts{}) && parts != PartsStandard {
+		key += "|" + parts.Part + "|" + parts.Fields
+		if parts.Hl != "" {
+			key += "|hl=" + parts.Hl
+		}
+	}
+	if o == (SearchOptions{}) {
+		return key
+	}
+	v := o.values("")
+	v.Del("q")
+	return key + "?" + v.Encode()
+}
+
+// arrange returns a copy of results, filtered, sorted, and trimmed when Shorts, Languages, Sort,
+// or Limit are set, leaving the cached results untouched. Languages are detected with d.
+func (o SearchOptions) arrange(results *VideoResults, d LanguageDetector) *VideoResults {
+	if o.Sort == "" && o.Limit <= 0 && o.Shorts == ShortsAny && o.Languages == "" {
+		return results.shallowCopy()
+	}
+	arranged := results.Filter(o.Shorts.keep)
+	if o.Languages != "" {
+		arranged = arranged.FilterLanguages(d, o.languageCodes()...)
+	}
+	return arranged.Sort(o.Sort).Limit(o.Limit)
+}
+
+// languageCodes returns the codes listed in Languages.
+func (o SearchOptions) languageCodes() []string {
+	return strings.FieldsFunc(o.Languages, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// firstSearchOptions returns the first of the optional SearchOptions, or the zero value.
+func firstSearchOptions(opts []SearchOptions) SearchOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return SearchOptions{}
+}
+
+// Search returns the raw search results for query over up to numPages pages, without
+// fetching video details or statistics. It is the cheap building block behind FindTags.
+func (yt *YoutubeApi) Search(query string, numPages int, opts SearchOptions) (*TagSearchResults, error) {
+	return yt.SearchContext(context.Background(), query, numPages, opts)
+}
+
+// SearchContext is like Search but uses ctx for every page request.
+func (yt *YoutubeApi) SearchContext(ctx context.Context, query string, numPages int, opts SearchOptions) (*TagSearchResults, error) {
+	results := &TagSearchResults{}
+	nextPage := opts.PageToken
+	for i := 0; i < numPages; i++ {
+		res, err := yt.searchPage(ctx, query, nextPage, opts)
+		if err != nil {
+			return nil, err
+		}
+		results.Items = append(results.Items, res.Items...)
+
+		nextPage = res.NextPageToken
+		if nextPage == "" {
+			break
+		}
+	}
+	results.NextPageToken = nextPage
+	return results, nil
+}
+
+// searchUrl builds the URL of a single search page.
+func (yt *YoutubeApi) searchUrl(query, pageToken string, opts SearchOptions) string {
+	v := opts.values(query)
+	v.Set("key", yt.apiKey)
+	if pageToken != "" {
+		v.Set("pageToken", pageToken)
+	}
+	return SearchEndpoint + "?" + v.Encode()
+}
+
+// MaxChannelSearchPages bounds the search pages requested by SearchChannelVideos. YouTube returns
+// at most about 500 results per search, and each page costs QuotaCostSearch units.
+const MaxChannelSearchPages = 10
+
+// SearchChannelVideos returns the videos of a channel matching query, with their details, in
+// search order. Unlike FindTags it doesn't drop videos with few views, and it follows every
+// search page up to MaxChannelSearchPages, so finding a channel's videos about a topic doesn't
+// require downloading its whole uploads playlist. opts.ChannelId is replaced by channelId.
+func (yt *YoutubeApi) SearchChannelVideos(channelId, query string, opts SearchOptions) (*VideoResults, error) {
+	return yt.SearchChannelVideosContext(context.Background(), channelId, query, opts)
+}
+
+// SearchChannelVideosContext is like SearchChannelVideos but uses ctx for every request.
+func (yt *YoutubeApi) SearchChannelVideosContext(ctx context.Context, channelId, query string, opts SearchOptions) (_ *VideoResults, err error) {
+	ctx, span := yt.startOperation(ctx, "SearchChannelVideos")
+	defer func() { endSpan(span, err) }()
+
+	opts.ChannelId = channelId
+	// Named apart so the entry isn't shared with a FindTags search using the same options.
+	cacheKey := CacheKey("channelSearch", opts.keyValues(query))
+	if v := yt.Cache.GetVideo(cacheKey); v != nil {
+		span.SetAttributes(AttrCacheHit.Bool(true))
+		return opts.arrange(v, yt.languages), nil
+	}
+	span.SetAttributes(AttrCacheHit.Bool(false))
+
+	v, err := yt.coalesce(ctx, RegionVideos, cacheKey, func() (interface{}, error) {
+		found, err := yt.SearchContext(ctx, query, MaxChannelSearchPages, opts)
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, item := range found.Items {
+			if item.Id != nil && item.Id.VideoId != "" {
+				ids = append(ids, item.Id.VideoId)
+			}
+		}
+		results := &VideoResults{NextPageToken: found.NextPageToken}
+		if len(ids) > 0 {
+			details, err := yt.GetVideosWithParts(ctx, ids, opts.Parts)
+			if err != nil {
+				return nil, err
+			}
+			// Copied, since details may be shared with the video details cache.
+			results.Items = append(results.Items, details.Items...)
+		}
+		yt.Cache.SetVideo(cacheKey, results)
+		return results, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return opts.arrange(v.(*VideoResults), yt.languages), nil
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/josephalai/alaitube"
+)
+
+// ErrorBody is the body of the JSON error envelope, {"error": ErrorBody}.
+type ErrorBody struct {
+	Code    int    `json:"code"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// requestError is a failure answered with a specific status and reason.
+type requestError struct {
+	status  int
+	reason  string
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// invalidParameter reports an invalid query or path parameter.
+func invalidParameter(name, problem string) *requestError {
+	return &requestError{http.StatusBadRequest, "invalidParameter", name + " " + problem}
+}
+
+// errorBody maps err to the status and reason it is answered with. Errors of the YouTube API
+// caused by the server's own configuration, such as an invalid key, are reported as a bad gateway.
+func errorBody(err error) ErrorBody {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return ErrorBody{reqErr.status, reqErr.reason, reqErr.message}
+	}
+	body := ErrorBody{Message: err.Error()}
+	switch {
+	case errors.Is(err, alaitube.ErrNotFound):
+		body.Code, body.Reason = http.StatusNotFound, "notFound"
+	case errors.Is(err, alaitube.ErrQuotaExceeded):
+		body.Code, body.Reason = http.StatusTooManyRequests, "quotaExceeded"
+	case errors.Is(err, alaitube.ErrRateLimited):
+		body.Code, body.Reason = http.StatusTooManyRequests, "rateLimited"
+	case errors.Is(err, context.DeadlineExceeded):
+		body.Code, body.Reason = http.StatusGatewayTimeout, "timeout"
+	case errors.Is(err, context.Canceled):
+		// The client went away; the status is only logged.
+		body.Code, body.Reason = 499, "canceled"
+	default:
+		var apiErr *alaitube.ApiError
+		if errors.As(err, &apiErr) {
+			body.Code, body.Reason = http.StatusBadGateway, "upstreamError"
+		} else {
+			body.Code, body.Reason = http.StatusInternalServerError, "internalError"
+		}
+	}
+	return body
+}
+
+// writeError answers with the JSON error envelope of err, logging server-side failures.
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	body := errorBody(err)
+	if body.Code >= http.StatusInternalServerError {
+		s.logger.Error("request failed",
+			alaitube.Field{Key: "path", Value: r.URL.Path},
+			alaitube.Field{Key: "status", Value: body.Code},
+			alaitube.Field{Key: "error", Value: err.Error()})
+	}
+	if body.Code == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", "60")
+	}
+	writeJSON(w, body.Code, struct {
+		Error ErrorBody `json:"error"`
+	}{body})
+}
@@ -0,0 +1,335 @@
+// Package server exposes a YoutubeApi client as a JSON REST service, so applications written in
+// other languages can share its cache, quota handling, and retries:
+//
+//	yt := alaitube.NewClient(alaitube.WithApiKey(key), alaitube.WithCache(cache))
+//	srv := server.New(yt)
+//	if err := srv.ListenAndServe(ctx, ":8080"); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// The routes are:
+//
+//	GET /search?q=QUERY                  videos matching a query, with their details
+//	GET /videos/{id}                     a video
+//	GET /channels/{id}                   a channel
+//	GET /channels/{id}/uploads?n=50      the latest uploads of a channel
+//
+// /search accepts the pages, order, region, language, duration, published_after,
+// published_before, and limit parameters, mapped to alaitube.SearchOptions.
+//
+// Failures are answered with a JSON envelope shaped like YouTube's own:
+//
+//	{"error": {"code": 404, "reason": "notFound", "message": "video abc not found"}}
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/josephalai/alaitube"
+)
+
+// Defaults of a Server created without options.
+const (
+	DefaultMaxSearchPages  = 5
+	DefaultMaxUploads      = 500
+	DefaultUploads         = 50
+	DefaultShutdownTimeout = 10 * time.Second
+)
+
+// Backend is the part of the client used by the server. *alaitube.YoutubeApi implements it.
+type Backend interface {
+	FindTagsContext(ctx context.Context, input string, numPages int, opts ...alaitube.SearchOptions) (*alaitube.VideoResults, error)
+	GetVideosContext(ctx context.Context, videoIds []string) (*alaitube.VideoResults, error)
+	GetChannelInfoContext(ctx context.Context, channelId string) (*alaitube.ChannelInfo, error)
+	GetChannelPlaylistContext(ctx context.Context, item *alaitube.Item, vidCount int) (*alaitube.VideoResults, error)
+}
+
+var _ Backend = (*alaitube.YoutubeApi)(nil)
+
+// Server is an http.Handler serving the REST routes of the package.
+type Server struct {
+	yt              Backend
+	maxPages        int
+	maxUploads      int
+	shutdownTimeout time.Duration
+	logger          alaitube.Logger
+}
+
+// Option configures a Server created with New.
+type Option func(*Server)
+
+// WithMaxSearchPages bounds the pages parameter of /search, since each page costs 100 quota units.
+func WithMaxSearchPages(n int) Option {
+	return func(s *Server) {
+		s.maxPages = n
+	}
+}
+
+// WithMaxUploads bounds the n parameter of /channels/{id}/uploads.
+func WithMaxUploads(n int) Option {
+	return func(s *Server) {
+		s.maxUploads = n
+	}
+}
+
+// WithShutdownTimeout bounds how long ListenAndServe waits for requests in flight once its
+// context is done.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout = d
+	}
+}
+
+// WithLogger sets the Logger failed requests are reported to.
+// A nil logger is ignored and the default slog adapter is kept.
+func WithLogger(logger alaitube.Logger) Option {
+	return func(s *Server) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
+
+// New creates a Server answering requests with yt.
+func New(yt Backend, opts ...Option) *Server {
+	s := &Server{
+		yt:              yt,
+		maxPages:        DefaultMaxSearchPages,
+		maxUploads:      DefaultMaxUploads,
+		shutdownTimeout: DefaultShutdownTimeout,
+		logger:          alaitube.NewSlogLogger(nil),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ListenAndServe serves the routes on addr until ctx is done, then stops accepting connections
+// and waits up to the shutdown timeout for requests in flight to complete.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve is like ListenAndServe but accepts connections on ln.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed shutting down server: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP routes a request to its handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		s.writeError(w, r, &requestError{http.StatusMethodNotAllowed, "methodNotAllowed", "method " + r.Method + " not allowed"})
+		return
+	}
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(path) == 1 && path[0] == "search":
+		s.search(w, r)
+	case len(path) == 2 && path[0] == "videos":
+		s.video(w, r, path[1])
+	case len(path) == 2 && path[0] == "channels":
+		s.channel(w, r, path[1])
+	case len(path) == 3 && path[0] == "channels" && path[2] == "uploads":
+		s.uploads(w, r, path[1])
+	default:
+		s.writeError(w, r, &requestError{http.StatusNotFound, "notFound", "no route for " + r.URL.Path})
+	}
+}
+
+var (
+	videoIdPattern   = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	channelIdPattern = regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`)
+)
+
+// search answers /search.
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		s.writeError(w, r, invalidParameter("q", "is required"))
+		return
+	}
+	pages, err := intParam(q.Get("pages"), "pages", 1, 1, s.maxPages)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	opts, err := searchOptions(q)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	results, err := s.yt.FindTagsContext(r.Context(), query, pages, opts)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// searchOptions builds the SearchOptions of the /search parameters.
+func searchOptions(q url.Values) (alaitube.SearchOptions, error) {
+	get := func(key string) string { return strings.TrimSpace(q.Get(key)) }
+	opts := alaitube.SearchOptions{
+		Order:             get("order"),
+		RegionCode:        strings.ToUpper(get("region")),
+		RelevanceLanguage: get("language"),
+		VideoDuration:     get("duration"),
+	}
+	switch opts.Order {
+	case "", alaitube.OrderDate, alaitube.OrderRelevance, alaitube.OrderViewCount, alaitube.OrderRating, alaitube.OrderTitle:
+	default:
+		return opts, invalidParameter("order", "must be date, relevance, viewCount, rating, or title")
+	}
+	switch opts.VideoDuration {
+	case "", alaitube.DurationAny, alaitube.DurationShort, alaitube.DurationMedium, alaitube.DurationLong:
+	default:
+		return opts, invalidParameter("duration", "must be any, short, medium, or long")
+	}
+	if opts.RegionCode != "" && len(opts.RegionCode) != 2 {
+		return opts, invalidParameter("region", "must be an ISO 3166-1 alpha-2 code")
+	}
+	var err error
+	if opts.PublishedAfter, err = timeParam(get("published_after"), "published_after"); err != nil {
+		return opts, err
+	}
+	if opts.PublishedBefore, err = timeParam(get("published_before"), "published_before"); err != nil {
+		return opts, err
+	}
+	if opts.Limit, err = intParam(get("limit"), "limit", 0, 0, 1<<20); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// video answers /videos/{id}.
+func (s *Server) video(w http.ResponseWriter, r *http.Request, id string) {
+	if !videoIdPattern.MatchString(id) {
+		s.writeError(w, r, invalidParameter("id", "is not a video ID"))
+		return
+	}
+	results, err := s.yt.GetVideosContext(r.Context(), []string{id})
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	if len(results.Items) == 0 {
+		s.writeError(w, r, fmt.Errorf("video %s: %w", id, alaitube.ErrNotFound))
+		return
+	}
+	writeJSON(w, http.StatusOK, results.Items[0])
+}
+
+// channel answers /channels/{id}.
+func (s *Server) channel(w http.ResponseWriter, r *http.Request, id string) {
+	item, err := s.channelItem(r.Context(), id)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+// uploads answers /channels/{id}/uploads.
+func (s *Server) uploads(w http.ResponseWriter, r *http.Request, id string) {
+	n, err := intParam(r.URL.Query().Get("n"), "n", DefaultUploads, 1, s.maxUploads)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	item, err := s.channelItem(r.Context(), id)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	results, err := s.yt.GetChannelPlaylistContext(r.Context(), item, n)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// channelItem validates a channel ID and returns its channel.
+func (s *Server) channelItem(ctx context.Context, id string) (*alaitube.Item, error) {
+	if !channelIdPattern.MatchString(id) {
+		return nil, invalidParameter("id", "is not a channel ID")
+	}
+	info, err := s.yt.GetChannelInfoContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(info.Items) == 0 {
+		return nil, fmt.Errorf("channel %s: %w", id, alaitube.ErrNotFound)
+	}
+	return info.Items[0], nil
+}
+
+// intParam parses an integer parameter between lo and hi, returning def when it is empty.
+func intParam(value, name string, def, lo, hi int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < lo || n > hi {
+		return 0, invalidParameter(name, fmt.Sprintf("must be an integer between %d and %d", lo, hi))
+	}
+	return n, nil
+}
+
+// timeParam parses an RFC 3339 time parameter, returning the zero time when it is empty.
+func timeParam(value, name string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, invalidParameter(name, "must be an RFC 3339 time")
+	}
+	return t, nil
+}
+
+// writeJSON answers with v as JSON.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
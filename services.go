@@ -0,0 +1,142 @@
+package alaitube
+
+import (
+	"context"
+	"io"
+)
+
+// SearchService covers keyword searches and tag retrieval. Code depending on it instead of
+// *YoutubeApi can substitute a fake in tests.
+type SearchService interface {
+	Search(query string, numPages int, opts SearchOptions) (*TagSearchResults, error)
+	SearchContext(ctx context.Context, query string, numPages int, opts SearchOptions) (*TagSearchResults, error)
+	FindTags(input string, numPages int, opts ...SearchOptions) (*VideoResults, error)
+	FindTagsContext(ctx context.Context, input string, numPages int, opts ...SearchOptions) (*VideoResults, error)
+	FindTagsStream(ctx context.Context, input string, numPages int, opts ...SearchOptions) (<-chan *Video, <-chan error)
+	FindTagsStreamWithToken(ctx context.Context, input string, numPages int, opts ...SearchOptions) (<-chan *Video, <-chan StreamEnd)
+	SearchAndRetrieveTags(search string, pages ...int) (*VideoResults, error)
+	SearchAndRetrieveTagsContext(ctx context.Context, search string, pages ...int) (*VideoResults, error)
+	SearchAndRetrieveTagsWithOptions(ctx context.Context, search string, opts SearchOptions, pages ...int) (*VideoResults, error)
+	SearchChannelVideos(channelId, query string, opts SearchOptions) (*VideoResults, error)
+	SearchChannelVideosContext(ctx context.Context, channelId, query string, opts SearchOptions) (*VideoResults, error)
+	Discover(seedIds []string, opts ...DiscoveryOption) (*DiscoveryGraph, error)
+	DiscoverContext(ctx context.Context, seedIds []string, opts ...DiscoveryOption) (*DiscoveryGraph, error)
+	SuggestTags(keyword string, opts ...TagSuggestionOption) ([]TagSuggestion, error)
+	SuggestTagsContext(ctx context.Context, keyword string, opts ...TagSuggestionOption) ([]TagSuggestion, error)
+	RankForQuery(videoId, query string, maxPages int, opts ...SearchOptions) (*Ranking, error)
+	RankForQueryContext(ctx context.Context, videoId, query string, maxPages int, opts ...SearchOptions) (*Ranking, error)
+	RankChannelForQuery(channelId, query string, maxPages int, opts ...SearchOptions) (*Ranking, error)
+	RankChannelForQueryContext(ctx context.Context, channelId, query string, maxPages int, opts ...SearchOptions) (*Ranking, error)
+	RankForQueries(videoId string, queries []string, maxPages int, opts ...SearchOptions) ([]*Ranking, error)
+	RankForQueriesContext(ctx context.Context, videoId string, queries []string, maxPages int, opts ...SearchOptions) ([]*Ranking, error)
+	RankChannelForQueries(channelId string, queries []string, maxPages int, opts ...SearchOptions) ([]*Ranking, error)
+	RankChannelForQueriesContext(ctx context.Context, channelId string, queries []string, maxPages int, opts ...SearchOptions) ([]*Ranking, error)
+}
+
+// VideoService covers video details and the resources attached to a video.
+type VideoService interface {
+	GetVideos(videoIds []string) (*VideoResults, error)
+	GetVideosContext(ctx context.Context, videoIds []string) (*VideoResults, error)
+	GetVideosWithParts(ctx context.Context, videoIds []string, parts Parts) (*VideoResults, error)
+	GetTrending(regionCode, categoryId string, maxResults int) (*VideoResults, error)
+	GetTrendingContext(ctx context.Context, regionCode, categoryId string, maxResults int) (*VideoResults, error)
+	GetVideoRatings(videoIds []string) (map[string]string, error)
+	GetVideoRatingsContext(ctx context.Context, videoIds []string) (map[string]string, error)
+	RateVideo(videoId, rating string) error
+	RateVideoContext(ctx context.Context, videoId, rating string) error
+	RateVideos(ctx context.Context, results *VideoResults, rating string) (int, error)
+	IsShortVideo(videoId string) (bool, error)
+	IsShortVideoContext(ctx context.Context, videoId string) (bool, error)
+	CheckShorts(ctx context.Context, results *VideoResults) (map[string]bool, error)
+	GetVideoCategories(regionCode string) (*VideoCategoryResults, error)
+	GetVideoCategoriesContext(ctx context.Context, regionCode string) (*VideoCategoryResults, error)
+	GetCommentThreads(videoId string, opts CommentThreadOptions) (*CommentThreadResults, error)
+	GetCommentThreadsContext(ctx context.Context, videoId string, opts CommentThreadOptions) (*CommentThreadResults, error)
+	PostComment(videoId, text string) (*CommentThread, error)
+	PostCommentContext(ctx context.Context, videoId, text string) (*CommentThread, error)
+	ReplyToComment(parentId, text string) (*Comment, error)
+	ReplyToCommentContext(ctx context.Context, parentId, text string) (*Comment, error)
+	MarkCommentsAsSpam(commentIds ...string) error
+	MarkCommentsAsSpamContext(ctx context.Context, commentIds ...string) error
+	SetCommentModerationStatus(commentIds []string, status string, banAuthor bool) error
+	SetCommentModerationStatusContext(ctx context.Context, commentIds []string, status string, banAuthor bool) error
+	ListCaptionTracks(videoId string) (*CaptionTracks, error)
+	ListCaptionTracksContext(ctx context.Context, videoId string) (*CaptionTracks, error)
+	DownloadCaption(track *CaptionTrack, format string) (*Caption, error)
+	DownloadCaptionContext(ctx context.Context, track *CaptionTrack, format string) (*Caption, error)
+	GetTranscript(videoId string, opts TranscriptOptions) (*Transcript, error)
+	GetTranscriptContext(ctx context.Context, videoId string, opts TranscriptOptions) (*Transcript, error)
+}
+
+// ChannelService covers channel information and channel feeds.
+type ChannelService interface {
+	GetChannelInfo(channelId string) (*ChannelInfo, error)
+	GetChannelInfoContext(ctx context.Context, channelId string) (*ChannelInfo, error)
+	GetChannels(channelIds []string) (map[string]*Item, error)
+	GetChannelsContext(ctx context.Context, channelIds []string) (map[string]*Item, error)
+	GetChannelsLocalized(ctx context.Context, channelIds []string, language string) (map[string]*Item, error)
+	GetUploadCadence(channelId string, maxVideos int) (*UploadCadence, error)
+	GetUploadCadenceContext(ctx context.Context, channelId string, maxVideos int) (*UploadCadence, error)
+	SnapshotChannel(channelId string, maxVideos int) (*ChannelSnapshot, error)
+	SnapshotChannelContext(ctx context.Context, channelId string, maxVideos int) (*ChannelSnapshot, error)
+	EnrichWithChannelStats(results *VideoResults) error
+	EnrichWithChannelStatsContext(ctx context.Context, results *VideoResults) error
+	GetChannelPlaylist(item *Item, vidCount int) (*VideoResults, error)
+	GetChannelPlaylistContext(ctx context.Context, item *Item, vidCount int) (*VideoResults, error)
+	GetChannelPlaylists(channelId string) (*PlaylistResults, error)
+	GetChannelPlaylistsContext(ctx context.Context, channelId string) (*PlaylistResults, error)
+	GetChannelSections(channelId string) (*ChannelSectionResults, error)
+	GetChannelSectionsContext(ctx context.Context, channelId string) (*ChannelSectionResults, error)
+	GetVideoCount(item *Item) (int, error)
+	GetActivities(channelId string, opts ActivityOptions) (*ActivityResults, error)
+	GetActivitiesContext(ctx context.Context, channelId string, opts ActivityOptions) (*ActivityResults, error)
+}
+
+// PlaylistService covers playlist management. Every method requires OAuth2 credentials.
+type PlaylistService interface {
+	GetPlaylistVideos(playlistId string, n int) (*VideoResults, error)
+	GetPlaylistVideosContext(ctx context.Context, playlistId string, n int) (*VideoResults, error)
+	CreatePlaylist(input PlaylistInput) (*Playlist, error)
+	CreatePlaylistContext(ctx context.Context, input PlaylistInput) (*Playlist, error)
+	AddVideoToPlaylist(playlistId, videoId string) (*PlaylistItem, error)
+	AddVideoToPlaylistContext(ctx context.Context, playlistId, videoId string) (*PlaylistItem, error)
+	AddVideosToPlaylist(ctx context.Context, playlistId string, results *VideoResults) ([]*PlaylistItem, error)
+	RemovePlaylistItem(itemId string) error
+	RemovePlaylistItemContext(ctx context.Context, itemId string) error
+	ReorderPlaylistItem(item *PlaylistItem, position int) (*PlaylistItem, error)
+	ReorderPlaylistItemContext(ctx context.Context, item *PlaylistItem, position int) (*PlaylistItem, error)
+}
+
+// SubscriptionService covers channel subscriptions.
+type SubscriptionService interface {
+	GetSubscriptions(channelId string, maxResults int) (*SubscriptionResults, error)
+	GetSubscriptionsContext(ctx context.Context, channelId string, maxResults int) (*SubscriptionResults, error)
+	GetMySubscriptions(maxResults int) (*SubscriptionResults, error)
+	GetMySubscriptionsContext(ctx context.Context, maxResults int) (*SubscriptionResults, error)
+	Subscribe(channelId string) (*Subscription, error)
+	SubscribeContext(ctx context.Context, channelId string) (*Subscription, error)
+	Unsubscribe(subscriptionId string) error
+	UnsubscribeContext(ctx context.Context, subscriptionId string) error
+}
+
+// UploadService covers resumable video uploads.
+type UploadService interface {
+	UploadVideo(r io.ReaderAt, size int64, opts UploadOptions) (*Video, error)
+	UploadVideoContext(ctx context.Context, r io.ReaderAt, size int64, opts UploadOptions) (*Video, error)
+	StartUpload(size int64, opts UploadOptions) (*UploadSession, error)
+	StartUploadContext(ctx context.Context, size int64, opts UploadOptions) (*UploadSession, error)
+	ResumeUpload(session *UploadSession, r io.ReaderAt, opts UploadOptions) (*Video, error)
+	ResumeUploadContext(ctx context.Context, session *UploadSession, r io.ReaderAt, opts UploadOptions) (*Video, error)
+}
+
+// Service is the full API surface of YoutubeApi.
+type Service interface {
+	SearchService
+	VideoService
+	ChannelService
+	PlaylistService
+	SubscriptionService
+	UploadService
+}
+
+var _ Service = (*YoutubeApi)(nil)
@@ -0,0 +1,140 @@
+package alaitube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const ShortsUrl = "https://www.youtube.com/shorts/%s"
+
+// MaxShortDuration is the longest a Short can be. Videos up to a minute long are almost always
+// Shorts, while longer ones up to MaxShortDuration need another hint.
+const MaxShortDuration = 3 * time.Minute
+
+// ShortsFilter selects whether SearchOptions keeps or drops Shorts.
+type ShortsFilter string
+
+const (
+	// ShortsAny keeps every video.
+	ShortsAny ShortsFilter = ""
+	// ShortsOnly keeps only the videos classified as Shorts.
+	ShortsOnly ShortsFilter = "only"
+	// ShortsExclude drops the videos classified as Shorts.
+	ShortsExclude ShortsFilter = "exclude"
+)
+
+// IsShort guesses whether the video is a Short from its metadata alone: it must be at most
+// MaxShortDuration long and not a live broadcast, and either last at most a minute, have a
+// vertical thumbnail, or mention #shorts. It is false when contentDetails was not fetched.
+// CheckShorts confirms the guess with YouTube.
+func (v *Video) IsShort() bool {
+	d := v.Duration()
+	if d <= 0 || d > MaxShortDuration || v.BroadcastKind() != BroadcastVOD {
+		return false
+	}
+	return d <= time.Minute || v.hasVerticalThumbnail() || v.mentionsShorts()
+}
+
+// hasVerticalThumbnail reports whether any thumbnail of the video is taller than it is wide.
+func (v *Video) hasVerticalThumbnail() bool {
+	if v.Snippet == nil {
+		return false
+	}
+	t := v.Snippet.Thumbnails
+	for _, thumb := range []*Thumbnail{t.Default, t.Medium, t.High, t.Standard, t.Maxres} {
+		if thumb != nil && thumb.Height > thumb.Width {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsShorts reports whether the title, description, or tags of the video contain #shorts.
+func (v *Video) mentionsShorts() bool {
+	if v.Snippet == nil {
+		return false
+	}
+	text := strings.ToLower(v.Snippet.Title + " " + v.Snippet.Description)
+	if strings.Contains(text, "#shorts") {
+		return true
+	}
+	for _, tag := range v.Snippet.Tags {
+		if strings.EqualFold(strings.TrimPrefix(tag, "#"), "shorts") {
+			return true
+		}
+	}
+	return false
+}
+
+// keep reports whether f keeps v.
+func (f ShortsFilter) keep(v *Video) bool {
+	switch f {
+	case ShortsOnly:
+		return v.IsShort()
+	case ShortsExclude:
+		return !v.IsShort()
+	}
+	return true
+}
+
+// PartitionShorts splits the results into Shorts and long-form videos, classified with IsShort.
+func (r *VideoResults) PartitionShorts() (shorts, longForm *VideoResults) {
+	return r.Filter(ShortsOnly.keep), r.Filter(ShortsExclude.keep)
+}
+
+// IsShortVideo asks YouTube whether a video is a Short: its /shorts/ URL is served as is for
+// Shorts and redirects to the regular watch page for every other video. The check costs no quota.
+func (yt *YoutubeApi) IsShortVideo(videoId string) (bool, error) {
+	return yt.IsShortVideoContext(context.Background(), videoId)
+}
+
+// IsShortVideoContext is like IsShortVideo but uses ctx for the underlying request.
+func (yt *YoutubeApi) IsShortVideoContext(ctx context.Context, videoId string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, fmt.Sprintf(ShortsUrl, url.PathEscape(videoId)), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed creating request, error: %w", redactError(err))
+	}
+	if yt.userAgent != "" {
+		req.Header.Set("User-Agent", yt.userAgent)
+	}
+	client := *yt.httpClient
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed HTTP request, error: %w", redactError(err))
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return true, nil
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return false, nil
+	}
+	return false, statusError(resp.StatusCode, nil)
+}
+
+// CheckShorts classifies the videos of results, keyed by ID. Videos too long to be Shorts, live
+// broadcasts, and videos of at most a minute are classified from their metadata; the others are
+// confirmed with IsShortVideo, one request each.
+func (yt *YoutubeApi) CheckShorts(ctx context.Context, results *VideoResults) (map[string]bool, error) {
+	shorts := make(map[string]bool, len(results.Items))
+	for _, v := range results.Items {
+		d := v.Duration()
+		if d <= time.Minute || d > MaxShortDuration || v.BroadcastKind() != BroadcastVOD {
+			shorts[v.Id] = v.IsShort()
+			continue
+		}
+		short, err := yt.IsShortVideoContext(ctx, v.Id)
+		if err != nil {
+			return shorts, fmt.Errorf("failed checking video %s: %w", v.Id, err)
+		}
+		shorts[v.Id] = short
+	}
+	return shorts, nil
+}
@@ -0,0 +1,293 @@
+package alaitube
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshotFormat identifies the header line of a cache snapshot.
+const snapshotFormat = "alaitube-cache-snapshot"
+
+const snapshotVersion = 1
+
+// SnapshotEntry is a single cache entry in a snapshot.
+type SnapshotEntry struct {
+	Region CacheRegion `json:"region"`
+	Key    string      `json:"key"`
+	// Value holds the JSON encoding of the cached value; Data holds values that aren't JSON.
+	Value json.RawMessage `json:"value,omitempty"`
+	Data  []byte          `json:"data,omitempty"`
+	// NotFound marks a negative cache entry.
+	NotFound  bool       `json:"notFound,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Snapshotter is implemented by caches whose entries can be enumerated and restored,
+// so they can be checkpointed with ExportSnapshot and warmed with ImportSnapshot.
+type Snapshotter interface {
+	// Snapshot calls fn with every live entry, stopping at the first error.
+	Snapshot(fn func(SnapshotEntry) error) error
+	// Restore stores an entry read from a snapshot.
+	Restore(entry SnapshotEntry) error
+}
+
+type snapshotHeader struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ExportSnapshot writes every live entry of s to w as JSON lines, preceded by a header line.
+func ExportSnapshot(w io.Writer, s Snapshotter) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := enc.Encode(snapshotHeader{Format: snapshotFormat, Version: snapshotVersion, CreatedAt: time.Now()}); err != nil {
+		return fmt.Errorf("failed writing snapshot: %w", err)
+	}
+	err := s.Snapshot(func(entry SnapshotEntry) error {
+		return enc.Encode(entry)
+	})
+	if err != nil {
+		return fmt.Errorf("failed writing snapshot: %w", err)
+	}
+	return bw.Flush()
+}
+
+// ImportSnapshot restores the entries of a snapshot written by ExportSnapshot into s.
+// Entries that expired since the snapshot was taken are skipped.
+func ImportSnapshot(r io.Reader, s Snapshotter) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	header := snapshotHeader{}
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("failed reading snapshot header: %w", err)
+	}
+	if header.Format != snapshotFormat {
+		return errors.New("not a cache snapshot")
+	}
+	if header.Version != snapshotVersion {
+		return fmt.Errorf("unsupported cache snapshot version %d", header.Version)
+	}
+	now := time.Now()
+	for {
+		entry := SnapshotEntry{}
+		err := dec.Decode(&entry)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed reading snapshot: %w", err)
+		}
+		if entry.ExpiresAt != nil && now.After(*entry.ExpiresAt) {
+			continue
+		}
+		if err := s.Restore(entry); err != nil {
+			return fmt.Errorf("failed restoring %s entry %q: %w", entry.Region, entry.Key, err)
+		}
+	}
+}
+
+// bytes returns the raw value of an entry written by a byte-oriented store.
+func (e SnapshotEntry) bytes() []byte {
+	if e.Value != nil {
+		return e.Value
+	}
+	return e.Data
+}
+
+// ttl returns the remaining lifetime of an entry, zero when it doesn't expire.
+func (e SnapshotEntry) ttl() time.Duration {
+	if e.ExpiresAt == nil {
+		return 0
+	}
+	// Never zero, so an entry about to expire isn't restored without expiration.
+	return max(time.Until(*e.ExpiresAt), time.Millisecond)
+}
+
+// byteEntry builds the snapshot entry of a value stored by a byte-oriented store.
+func byteEntry(region CacheRegion, key string, value []byte, expiresAt time.Time) SnapshotEntry {
+	entry := SnapshotEntry{Region: region, Key: key}
+	if json.Valid(value) {
+		entry.Value = value
+	} else {
+		entry.Data = value
+	}
+	if !expiresAt.IsZero() {
+		entry.ExpiresAt = &expiresAt
+	}
+	return entry
+}
+
+// newRegionValue returns a pointer to a zero value of the type cached in region.
+func newRegionValue(region CacheRegion) (interface{}, error) {
+	switch region {
+	case RegionVideos, RegionPlaylists, RegionVideoDetails:
+		return &VideoResults{}, nil
+	case RegionChannels:
+		return &ChannelInfo{}, nil
+	case RegionComments:
+		return &CommentThreadResults{}, nil
+	case RegionCategories:
+		return &VideoCategoryResults{}, nil
+	case RegionSubscriptions:
+		return &SubscriptionResults{}, nil
+	}
+	return nil, fmt.Errorf("unknown cache region %q", region)
+}
+
+// Snapshot calls fn with every live entry, least recently used first, so restoring a
+// snapshot keeps the LRU order.
+func (c *MemoryCache) Snapshot(fn func(SnapshotEntry) error) error {
+	type item struct {
+		region CacheRegion
+		entry  memoryEntry
+	}
+	var items []item
+	c.Lock()
+	now := c.now()
+	for _, region := range cacheRegions {
+		r := c.regions[region]
+		for el := r.order.Back(); el != nil; el = el.Prev() {
+			entry := el.Value.(*memoryEntry)
+			if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+				continue
+			}
+			items = append(items, item{region: region, entry: *entry})
+		}
+	}
+	c.Unlock()
+
+	for _, it := range items {
+		entry := SnapshotEntry{Region: it.region, Key: it.entry.key}
+		if !it.entry.expiresAt.IsZero() {
+			entry.ExpiresAt = &it.entry.expiresAt
+		}
+		switch v := it.entry.value.(type) {
+		case notFoundEntry:
+			entry.NotFound = true
+		case nil:
+			continue
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			if string(data) == "null" {
+				continue
+			}
+			entry.Value = data
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore stores an entry read from a snapshot, keeping its expiration time.
+func (c *MemoryCache) Restore(entry SnapshotEntry) error {
+	if entry.NotFound {
+		if _, ok := c.regions[entry.Region]; !ok {
+			return fmt.Errorf("unknown cache region %q", entry.Region)
+		}
+		c.setWithTTL(entry.Region, entry.Key, notFoundEntry{}, entry.ttl())
+		return nil
+	}
+	v, err := newRegionValue(entry.Region)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(entry.Value, v); err != nil {
+		return err
+	}
+	c.setWithTTL(entry.Region, entry.Key, v, entry.ttl())
+	return nil
+}
+
+// ExportSnapshot writes every live entry to w. See ExportSnapshot.
+func (c *MemoryCache) ExportSnapshot(w io.Writer) error {
+	return ExportSnapshot(w, c)
+}
+
+// ImportSnapshot restores the entries of a snapshot written by ExportSnapshot.
+func (c *MemoryCache) ImportSnapshot(r io.Reader) error {
+	return ImportSnapshot(r, c)
+}
+
+// Snapshot calls fn with every live entry.
+func (s *MemoryStore) Snapshot(fn func(SnapshotEntry) error) error {
+	var entries []SnapshotEntry
+	s.Lock()
+	now := s.now()
+	for region, items := range s.regions {
+		for key, item := range items {
+			if !item.expiresAt.IsZero() && now.After(item.expiresAt) {
+				continue
+			}
+			entries = append(entries, byteEntry(region, key, item.value, item.expiresAt))
+		}
+	}
+	s.Unlock()
+	for _, entry := range entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore stores an entry read from a snapshot, keeping its expiration time.
+// Entries without expiration get the store's TTL.
+func (s *MemoryStore) Restore(entry SnapshotEntry) error {
+	return s.Set(context.Background(), entry.Region, entry.Key, entry.bytes(), entry.ttl())
+}
+
+// Snapshot calls fn with every live entry.
+func (s *FileStore) Snapshot(fn func(SnapshotEntry) error) error {
+	dirs, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		region, err := url.PathUnescape(d.Name())
+		if err != nil {
+			continue
+		}
+		files, err := filepath.Glob(filepath.Join(s.dir, d.Name(), "*.json"))
+		if err != nil {
+			return err
+		}
+		for _, path := range files {
+			entry, err := readFileEntry(path)
+			if err != nil || (entry.ExpiresAt != nil && now.After(*entry.ExpiresAt)) {
+				continue
+			}
+			snap := SnapshotEntry{Region: CacheRegion(region), Key: entry.Key, Value: entry.Value, Data: entry.Data, ExpiresAt: entry.ExpiresAt}
+			if err := fn(snap); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Restore stores an entry read from a snapshot, keeping its expiration time.
+// Entries without expiration get the region's TTL.
+func (s *FileStore) Restore(entry SnapshotEntry) error {
+	return s.Set(context.Background(), entry.Region, entry.Key, entry.bytes(), entry.ttl())
+}
+
+var _ Snapshotter = (*MemoryCache)(nil)
+var _ Snapshotter = (*MemoryStore)(nil)
+var _ Snapshotter = (*FileStore)(nil)
@@ -0,0 +1,59 @@
+package alaitube
+
+import "sort"
+
+// SortOrder selects how aggregated video results are ordered by SearchOptions.Sort and VideoResults.Sort.
+type SortOrder string
+
+const (
+	// SortRelevance keeps the order YouTube returned the videos in.
+	SortRelevance  SortOrder = "relevance"
+	SortViews      SortOrder = "views"
+	SortLikes      SortOrder = "likes"
+	SortDate       SortOrder = "date"
+	SortEngagement SortOrder = "engagement"
+	// SortViewsPerDay favours videos gaining views quickly over old videos with large totals.
+	SortViewsPerDay SortOrder = "viewsPerDay"
+)
+
+// Sort orders the videos in place, highest or newest first, and returns r for chaining.
+// Videos that compare equal keep their relative order. An unknown order leaves r unchanged.
+func (r *VideoResults) Sort(order SortOrder) *VideoResults {
+	var key func(v *Video) float64
+	switch order {
+	case SortViews:
+		key = func(v *Video) float64 {
+			if v.Statistics == nil {
+				return 0
+			}
+			return float64(v.Statistics.ViewCount)
+		}
+	case SortLikes:
+		key = func(v *Video) float64 {
+			if v.Statistics == nil {
+				return 0
+			}
+			return float64(v.Statistics.LikeCount)
+		}
+	case SortDate:
+		key = func(v *Video) float64 { return float64(v.PublishedAt().Unix()) }
+	case SortEngagement:
+		key = (*Video).EngagementRate
+	case SortViewsPerDay:
+		key = (*Video).ViewsPerDay
+	default:
+		return r
+	}
+	sort.SliceStable(r.Items, func(i, j int) bool {
+		return key(r.Items[i]) > key(r.Items[j])
+	})
+	return r
+}
+
+// Limit trims the results to at most n videos and returns r for chaining. An n of zero or less keeps every video.
+func (r *VideoResults) Limit(n int) *VideoResults {
+	if n > 0 && len(r.Items) > n {
+		r.Items = r.Items[:n]
+	}
+	return r
+}
@@ -0,0 +1,38 @@
+package alaitube
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Count is a statistic such as a view or subscriber count. YouTube encodes counts as
+// JSON strings; Count decodes both strings and numbers into an int64, so callers no
+// longer need to convert them. Missing, empty, or hidden counts decode as zero.
+type Count int64
+
+// UnmarshalJSON accepts "123", 123, "", and null.
+func (c *Count) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*c = 0
+		return nil
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid count %q: %w", data, err)
+	}
+	*c = Count(n)
+	return nil
+}
+
+// MarshalJSON encodes the count as a JSON number.
+func (c Count) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(c))
+}
+
+// Int64 returns the count as an int64.
+func (c Count) Int64() int64 {
+	return int64(c)
+}
@@ -0,0 +1,452 @@
+package alaitube
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTrackInterval is how often a StatsTracker takes snapshots when no interval is configured.
+const DefaultTrackInterval = time.Hour
+
+// Kinds of resources tracked by a StatsTracker.
+const (
+	StatsKindVideo   = "video"
+	StatsKindChannel = "channel"
+)
+
+// ErrNotEnoughSnapshots is returned by the StatsTracker queries when fewer than two snapshots of a
+// resource were taken within the window.
+var ErrNotEnoughSnapshots = errors.New("not enough snapshots")
+
+// statsParts restricts the video requests of a StatsTracker to the counts it records.
+var statsParts = Parts{Part: "statistics", Fields: "items(id,statistics),nextPageToken"}
+
+// StatsSnapshot holds the counts of a video or channel at a point in time. Likes and Comments are
+// only set for videos, Subscribers and Videos only for channels.
+type StatsSnapshot struct {
+	Id          string    `bson:"id" json:"id"`
+	Kind        string    `bson:"kind" json:"kind"`
+	At          time.Time `bson:"at" json:"at"`
+	Views       int64     `bson:"views" json:"views"`
+	Likes       int64     `bson:"likes,omitempty" json:"likes,omitempty"`
+	Comments    int64     `bson:"comments,omitempty" json:"comments,omitempty"`
+	Subscribers int64     `bson:"subscribers,omitempty" json:"subscribers,omitempty"`
+	Videos      int64     `bson:"videos,omitempty" json:"videos,omitempty"`
+}
+
+// StatsStore persists the snapshots taken by a StatsTracker.
+type StatsStore interface {
+	SaveSnapshots(snapshots []StatsSnapshot) error
+	// LoadSnapshots returns the snapshots of id taken between from and to, inclusive, oldest first.
+	LoadSnapshots(id string, from, to time.Time) ([]StatsSnapshot, error)
+}
+
+// MemoryStatsStore keeps snapshots in memory. It is the default store and does not survive restarts.
+type MemoryStatsStore struct {
+	snapshots map[string][]StatsSnapshot
+	sync.Mutex
+}
+
+// NewMemoryStatsStore creates an empty MemoryStatsStore.
+func NewMemoryStatsStore() *MemoryStatsStore {
+	return &MemoryStatsStore{snapshots: make(map[string][]StatsSnapshot)}
+}
+
+func (s *MemoryStatsStore) SaveSnapshots(snapshots []StatsSnapshot) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, snap := range snapshots {
+		s.snapshots[snap.Id] = append(s.snapshots[snap.Id], snap)
+	}
+	return nil
+}
+
+func (s *MemoryStatsStore) LoadSnapshots(id string, from, to time.Time) ([]StatsSnapshot, error) {
+	s.Lock()
+	defer s.Unlock()
+	return snapshotsBetween(s.snapshots[id], from, to), nil
+}
+
+// FileStatsStore appends snapshots to a file as JSON lines, so the history of every tracked
+// resource can also be loaded by other tools.
+type FileStatsStore struct {
+	path string
+	sync.Mutex
+}
+
+// NewFileStatsStore creates a FileStatsStore backed by path. The file is created on the first save.
+func NewFileStatsStore(path string) *FileStatsStore {
+	return &FileStatsStore{path: path}
+}
+
+func (s *FileStatsStore) SaveSnapshots(snapshots []StatsSnapshot) error {
+	s.Lock()
+	defer s.Unlock()
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed opening stats snapshots: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, snap := range snapshots {
+		if err := enc.Encode(snap); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to marshal stats snapshot: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed writing stats snapshots: %w", err)
+	}
+	return f.Close()
+}
+
+func (s *FileStatsStore) LoadSnapshots(id string, from, to time.Time) ([]StatsSnapshot, error) {
+	s.Lock()
+	defer s.Unlock()
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading stats snapshots: %w", err)
+	}
+	defer f.Close()
+
+	var snapshots []StatsSnapshot
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var snap StatsSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stats snapshot: %w", err)
+		}
+		if snap.Id == id {
+			snapshots = append(snapshots, snap)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading stats snapshots: %w", err)
+	}
+	return snapshotsBetween(snapshots, from, to), nil
+}
+
+// snapshotsBetween returns the snapshots taken between from and to, inclusive, oldest first.
+func snapshotsBetween(snapshots []StatsSnapshot, from, to time.Time) []StatsSnapshot {
+	var kept []StatsSnapshot
+	for _, snap := range snapshots {
+		if !snap.At.Before(from) && !snap.At.After(to) {
+			kept = append(kept, snap)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].At.Before(kept[j].At) })
+	return kept
+}
+
+// StatsGrowth is the change of the counts of a video or channel between two snapshots.
+type StatsGrowth struct {
+	Id          string        `bson:"id" json:"id"`
+	Kind        string        `bson:"kind" json:"kind"`
+	From        time.Time     `bson:"from" json:"from"`
+	To          time.Time     `bson:"to" json:"to"`
+	Elapsed     time.Duration `bson:"elapsed" json:"elapsed"`
+	Views       int64         `bson:"views" json:"views"`
+	Likes       int64         `bson:"likes,omitempty" json:"likes,omitempty"`
+	Comments    int64         `bson:"comments,omitempty" json:"comments,omitempty"`
+	Subscribers int64         `bson:"subscribers,omitempty" json:"subscribers,omitempty"`
+	Videos      int64         `bson:"videos,omitempty" json:"videos,omitempty"`
+}
+
+// growthBetween computes the growth from one snapshot to a later one.
+func growthBetween(from, to StatsSnapshot) *StatsGrowth {
+	return &StatsGrowth{
+		Id:          to.Id,
+		Kind:        to.Kind,
+		From:        from.At,
+		To:          to.At,
+		Elapsed:     to.At.Sub(from.At),
+		Views:       to.Views - from.Views,
+		Likes:       to.Likes - from.Likes,
+		Comments:    to.Comments - from.Comments,
+		Subscribers: to.Subscribers - from.Subscribers,
+		Videos:      to.Videos - from.Videos,
+	}
+}
+
+// ViewsPerHour returns the views gained per hour, or zero when no time elapsed.
+func (g *StatsGrowth) ViewsPerHour() float64 {
+	if g.Elapsed <= 0 {
+		return 0
+	}
+	return float64(g.Views) / g.Elapsed.Hours()
+}
+
+// StatsTracker periodically snapshots the view, like, and comment counts of a watched set of
+// videos, and the view, subscriber, and video counts of a watched set of channels, into a
+// StatsStore. Its queries compute the growth and velocity of each resource over a window, on
+// which trending detection can be built.
+type StatsTracker struct {
+	yt       *YoutubeApi
+	interval time.Duration
+	store    StatsStore
+	onError  func(error)
+
+	mu       sync.Mutex
+	videos   map[string]bool
+	channels map[string]bool
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// StatsTrackerOption configures a StatsTracker created with NewStatsTracker.
+type StatsTrackerOption func(*StatsTracker)
+
+// WithTrackInterval sets the time between snapshots.
+func WithTrackInterval(interval time.Duration) StatsTrackerOption {
+	return func(t *StatsTracker) {
+		if interval > 0 {
+			t.interval = interval
+		}
+	}
+}
+
+// WithStatsStore sets the store snapshots are saved to and loaded from.
+// A nil store is ignored and the default MemoryStatsStore is kept.
+func WithStatsStore(store StatsStore) StatsTrackerOption {
+	return func(t *StatsTracker) {
+		if store != nil {
+			t.store = store
+		}
+	}
+}
+
+// WithTrackErrorHandler sets the function snapshot errors are reported to.
+// By default errors are reported to the client's Logger and tracking continues.
+func WithTrackErrorHandler(fn func(error)) StatsTrackerOption {
+	return func(t *StatsTracker) {
+		if fn != nil {
+			t.onError = fn
+		}
+	}
+}
+
+// NewStatsTracker creates a StatsTracker taking a snapshot every DefaultTrackInterval into a
+// MemoryStatsStore. Add resources with TrackVideos and TrackChannels.
+func (yt *YoutubeApi) NewStatsTracker(opts ...StatsTrackerOption) *StatsTracker {
+	t := &StatsTracker{
+		yt:       yt,
+		interval: DefaultTrackInterval,
+		store:    NewMemoryStatsStore(),
+		onError: func(err error) {
+			yt.logger.Error("stats tracker snapshot failed", Field{"error", err})
+		},
+		videos:   make(map[string]bool),
+		channels: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// TrackVideos adds videos to the watched set.
+func (t *StatsTracker) TrackVideos(videoIds ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range videoIds {
+		t.videos[id] = true
+	}
+}
+
+// TrackChannels adds channels to the watched set.
+func (t *StatsTracker) TrackChannels(channelIds ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range channelIds {
+		t.channels[id] = true
+	}
+}
+
+// Untrack removes videos or channels from the watched set. Their snapshots are kept.
+func (t *StatsTracker) Untrack(ids ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range ids {
+		delete(t.videos, id)
+		delete(t.channels, id)
+	}
+}
+
+// tracked returns the sorted IDs of the watched videos and channels.
+func (t *StatsTracker) tracked() (videos, channels []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id := range t.videos {
+		videos = append(videos, id)
+	}
+	for id := range t.channels {
+		channels = append(channels, id)
+	}
+	sort.Strings(videos)
+	sort.Strings(channels)
+	return videos, channels
+}
+
+// Run takes a snapshot every interval until ctx is cancelled or Stop is called. A snapshot in
+// progress is allowed to finish, after which Run returns nil.
+func (t *StatsTracker) Run(ctx context.Context) error {
+	t.mu.Lock()
+	if t.done != nil {
+		t.mu.Unlock()
+		return errors.New("stats tracker is already running")
+	}
+	ctx, t.cancel = context.WithCancel(ctx)
+	t.done = make(chan struct{})
+	done := t.done
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		t.cancel()
+		t.done = nil
+		t.mu.Unlock()
+		close(done)
+	}()
+
+	for {
+		if _, err := t.Snapshot(ctx); err != nil && ctx.Err() == nil {
+			t.onError(err)
+		}
+		timer := time.NewTimer(t.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// Stop ends a running Run call and waits for it to return.
+func (t *StatsTracker) Stop() {
+	t.mu.Lock()
+	cancel, done := t.cancel, t.done
+	t.mu.Unlock()
+	if done == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// Snapshot fetches the current counts of every watched resource, bypassing the cache, and saves
+// them to the store. Videos cost a unit per 50 videos, and so do channels.
+func (t *StatsTracker) Snapshot(ctx context.Context) (_ []StatsSnapshot, err error) {
+	ctx, span := t.yt.startOperation(ctx, "StatsTracker.Snapshot")
+	defer func() { endSpan(span, err) }()
+
+	videoIds, channelIds := t.tracked()
+	now := time.Now()
+	var snapshots []StatsSnapshot
+	if len(videoIds) > 0 {
+		results, err := t.yt.fetchVideos(ctx, videoIds, statsParts)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range results.Items {
+			snap := StatsSnapshot{Id: v.Id, Kind: StatsKindVideo, At: now}
+			if v.Statistics != nil {
+				snap.Views = v.Statistics.ViewCount.Int64()
+				snap.Likes = v.Statistics.LikeCount.Int64()
+				snap.Comments = v.Statistics.CommentCount.Int64()
+			}
+			snapshots = append(snapshots, snap)
+		}
+	}
+	for page, batch := range batchIteration(channelIds) {
+		body, err := t.yt.httpGetRequest(withSpanAttributes(ctx, AttrPage.Int(page)), fmt.Sprintf(GetChannelVideos, batch, t.yt.apiKey))
+		if err != nil {
+			return nil, err
+		}
+		res := &ChannelInfo{}
+		if err := json.Unmarshal(body, res); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal channels: %w", err)
+		}
+		for _, item := range res.Items {
+			if item == nil {
+				continue
+			}
+			snap := StatsSnapshot{Id: item.Id, Kind: StatsKindChannel, At: now}
+			if item.Statistics != nil {
+				snap.Views = item.Statistics.ViewCount.Int64()
+				snap.Subscribers = item.Statistics.SubscriberCount.Int64()
+				snap.Videos = item.Statistics.VideoCount.Int64()
+			}
+			snapshots = append(snapshots, snap)
+		}
+	}
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+	if err := t.store.SaveSnapshots(snapshots); err != nil {
+		return nil, fmt.Errorf("failed saving stats snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// History returns the snapshots of a video or channel taken within window of now, oldest first.
+func (t *StatsTracker) History(id string, window time.Duration) ([]StatsSnapshot, error) {
+	now := time.Now()
+	return t.store.LoadSnapshots(id, now.Add(-window), now)
+}
+
+// Growth returns the change of the counts of a video or channel between its oldest and newest
+// snapshots within window of now. It returns ErrNotEnoughSnapshots when fewer than two were taken.
+func (t *StatsTracker) Growth(id string, window time.Duration) (*StatsGrowth, error) {
+	snapshots, err := t.History(id, window)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) < 2 {
+		return nil, fmt.Errorf("%s: %w", id, ErrNotEnoughSnapshots)
+	}
+	return growthBetween(snapshots[0], snapshots[len(snapshots)-1]), nil
+}
+
+// Velocity returns the views per hour gained by a video or channel within window of now.
+func (t *StatsTracker) Velocity(id string, window time.Duration) (float64, error) {
+	growth, err := t.Growth(id, window)
+	if err != nil {
+		return 0, err
+	}
+	return growth.ViewsPerHour(), nil
+}
+
+// Trending returns the growth within window of the n watched videos gaining views the fastest,
+// fastest first. Videos with fewer than two snapshots in the window are skipped. An n of zero or
+// less returns every video.
+func (t *StatsTracker) Trending(window time.Duration, n int) ([]*StatsGrowth, error) {
+	videoIds, _ := t.tracked()
+	var trending []*StatsGrowth
+	for _, id := range videoIds {
+		growth, err := t.Growth(id, window)
+		if errors.Is(err, ErrNotEnoughSnapshots) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		trending = append(trending, growth)
+	}
+	sort.SliceStable(trending, func(i, j int) bool { return trending[i].ViewsPerHour() > trending[j].ViewsPerHour() })
+	if n > 0 && len(trending) > n {
+		trending = trending[:n]
+	}
+	return trending, nil
+}
@@ -0,0 +1,90 @@
+package alaitube
+
+import "context"
+
+// FindTagsStream searches like FindTags but emits each video on the returned channel as soon as
+// its search page has been resolved, instead of aggregating every page in memory.
+// A numPages of zero or less walks every page YouTube returns. SearchOptions.Shorts and Languages
+// drop videos as they arrive; Sort and Limit, which need every video, are ignored.
+//
+// The video channel is closed when the search ends. At most one error is delivered on the error
+// channel, which is closed after the video channel; cancelling ctx stops the search and reports ctx.Err().
+// Unlike FindTags, the aggregated search result is not stored in the video cache.
+// FindTagsStreamWithToken also reports the token resuming the search.
+func (yt *YoutubeApi) FindTagsStream(ctx context.Context, input string, numPages int, opts ...SearchOptions) (<-chan *Video, <-chan error) {
+	out := make(chan *Video)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(out)
+		if _, err := yt.streamTags(ctx, input, numPages, firstSearchOptions(opts), out); err != nil {
+			errc <- err
+		}
+	}()
+
+	return out, errc
+}
+
+// StreamEnd reports how a search of FindTagsStreamWithToken ended.
+type StreamEnd struct {
+	// NextPageToken resumes the search when given as SearchOptions.PageToken. It is empty when
+	// YouTube has no more results. After an error it is the token of the page that failed, so
+	// resuming may emit again the videos of that page delivered before the error.
+	NextPageToken string
+	Err           error
+}
+
+// FindTagsStreamWithToken is like FindTagsStream but delivers a single StreamEnd on its second
+// channel when the search ends, carrying the error, if any, and the token resuming the search.
+func (yt *YoutubeApi) FindTagsStreamWithToken(ctx context.Context, input string, numPages int, opts ...SearchOptions) (<-chan *Video, <-chan StreamEnd) {
+	out := make(chan *Video)
+	endc := make(chan StreamEnd, 1)
+
+	go func() {
+		defer close(endc)
+		defer close(out)
+		nextPage, err := yt.streamTags(ctx, input, numPages, firstSearchOptions(opts), out)
+		endc <- StreamEnd{NextPageToken: nextPage, Err: err}
+	}()
+
+	return out, endc
+}
+
+// streamTags runs the searches of a stream, sending the videos found on out, and returns the
+// token of the page following the last one searched, or of the page that failed.
+func (yt *YoutubeApi) streamTags(ctx context.Context, input string, numPages int, searchOpts SearchOptions, out chan<- *Video) (string, error) {
+	languages := searchOpts.languageCodes()
+	nextPage := searchOpts.PageToken
+	for i := 0; numPages <= 0 || i < numPages; i++ {
+		res, err := yt.searchPage(ctx, input, nextPage, searchOpts)
+		if err != nil {
+			return nextPage, err
+		}
+
+		vidIds := make(map[string]vidSnippetInfo)
+		videos := collectSearchResults(res, nil, vidIds)
+		if len(videos) > 0 {
+			details, err := yt.GetVideosWithParts(ctx, videos, searchOpts.Parts)
+			if err != nil {
+				return nextPage, err
+			}
+			for _, v := range filterSearchVideos(details.Items, vidIds) {
+				if !searchOpts.Shorts.keep(v) || len(languages) > 0 && !v.inLanguages(yt.languages, languages) {
+					continue
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return nextPage, ctx.Err()
+				}
+			}
+		}
+
+		nextPage = res.NextPageToken
+		if nextPage == "" {
+			break
+		}
+	}
+	return nextPage, nil
+}
@@ -0,0 +1,108 @@
+package alaitube_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/josephalai/alaitube"
+	"github.com/josephalai/alaitube/testutil"
+)
+
+// streamVideo returns a popular video titled "cats" lasting duration, an ISO 8601 duration, and
+// declared in language when it is set.
+func streamVideo(t *testing.T, id, duration, language string) *alaitube.Video {
+	t.Helper()
+	var v alaitube.Video
+	data := fmt.Sprintf(`{"id":%q,"snippet":{"title":"cats %s","defaultLanguage":%q},"statistics":{"viewCount":"5000"},"contentDetails":{"duration":%q}}`, id, id, language, duration)
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("decoding video %s: %v", id, err)
+	}
+	return &v
+}
+
+func TestFindTagsStream(t *testing.T) {
+	srv := testutil.NewServer()
+	defer srv.Close()
+	srv.SetPageSize(2)
+	srv.AddVideo(streamVideo(t, "short1", "PT30S", "en"))
+	srv.AddVideo(streamVideo(t, "long1", "PT10M", "es-419"))
+	srv.AddVideo(streamVideo(t, "short2", "PT45S", "en-GB"))
+	srv.AddVideo(streamVideo(t, "long2", "PT1H", ""))
+	yt := alaitube.NewClient(alaitube.WithApiKey("test"), alaitube.WithHttpClient(srv.Client()))
+
+	tests := []struct {
+		name     string
+		numPages int
+		opts     alaitube.SearchOptions
+		want     []string
+	}{
+		{name: "every page", want: []string{"short1", "long1", "short2", "long2"}},
+		{name: "first page", numPages: 1, want: []string{"short1", "long1"}},
+		{name: "shorts only", opts: alaitube.SearchOptions{Shorts: alaitube.ShortsOnly}, want: []string{"short1", "short2"}},
+		{name: "shorts excluded", opts: alaitube.SearchOptions{Shorts: alaitube.ShortsExclude}, want: []string{"long1", "long2"}},
+		{name: "one language", opts: alaitube.SearchOptions{Languages: "es"}, want: []string{"long1"}},
+		{name: "languages list", opts: alaitube.SearchOptions{Languages: "en, es"}, want: []string{"short1", "long1", "short2"}},
+		{name: "shorts in a language", opts: alaitube.SearchOptions{Shorts: alaitube.ShortsExclude, Languages: "en,es"}, want: []string{"long1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos, errc := yt.FindTagsStream(context.Background(), "cats", tt.numPages, tt.opts)
+			var got []string
+			for v := range videos {
+				got = append(got, v.Id)
+			}
+			if err := <-errc; err != nil {
+				t.Fatalf("FindTagsStream: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("videos = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindTagsStreamWithToken(t *testing.T) {
+	srv := testutil.NewServer()
+	defer srv.Close()
+	srv.SetPageSize(2)
+	for i := 0; i < 5; i++ {
+		srv.AddVideo(streamVideo(t, fmt.Sprintf("cat%d", i), "PT10M", ""))
+	}
+	yt := alaitube.NewClient(alaitube.WithApiKey("test"), alaitube.WithHttpClient(srv.Client()))
+
+	tests := []struct {
+		name      string
+		pageToken string
+		numPages  int
+		want      []string
+		wantToken string
+	}{
+		{name: "first page", numPages: 1, want: []string{"cat0", "cat1"}, wantToken: "page-2"},
+		{name: "resumed", pageToken: "page-2", numPages: 1, want: []string{"cat2", "cat3"}, wantToken: "page-4"},
+		{name: "last page", pageToken: "page-4", numPages: 2, want: []string{"cat4"}},
+		{name: "every page", want: []string{"cat0", "cat1", "cat2", "cat3", "cat4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := alaitube.SearchOptions{PageToken: tt.pageToken}
+			videos, endc := yt.FindTagsStreamWithToken(context.Background(), "cats", tt.numPages, opts)
+			var got []string
+			for v := range videos {
+				got = append(got, v.Id)
+			}
+			end := <-endc
+			if end.Err != nil {
+				t.Fatalf("FindTagsStreamWithToken: %v", end.Err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("videos = %v, want %v", got, tt.want)
+			}
+			if end.NextPageToken != tt.wantToken {
+				t.Errorf("NextPageToken = %q, want %q", end.NextPageToken, tt.wantToken)
+			}
+		})
+	}
+}
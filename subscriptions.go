@@ -0,0 +1,143 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const ListSubscriptions = "https://www.googleapis.com/youtube/v3/subscriptions?part=snippet,contentDetails&maxResults=50&%s&key=%s%s"
+const InsertSubscription = "https://www.googleapis.com/youtube/v3/subscriptions?part=snippet&key=%s"
+const DeleteSubscription = "https://www.googleapis.com/youtube/v3/subscriptions?id=%s&key=%s"
+
+// SubscriptionResults contains the subscriptions retrieved for a channel.
+type SubscriptionResults struct {
+	Items         []*Subscription `bson:"items,omitempty" json:"items,omitempty"`
+	NextPageToken string          `bson:"nextPageToken,omitempty" json:"nextPageToken,omitempty"`
+}
+
+// Subscription is a channel's subscription to another channel.
+// Snippet.ResourceId.ChannelId identifies the channel subscribed to.
+type Subscription struct {
+	Id      string `bson:"id,omitempty" json:"id,omitempty"`
+	Snippet *struct {
+		PublishedAt time.Time   `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+		Title       string      `bson:"title,omitempty" json:"title,omitempty"`
+		Description string      `bson:"description,omitempty" json:"description,omitempty"`
+		ResourceId  *ResourceId `bson:"resourceId,omitempty" json:"resourceId,omitempty"`
+		ChannelId   string      `bson:"channelId,omitempty" json:"channelId,omitempty"`
+		Thumbnails  Thumbnails  `bson:"thumbnails,omitempty" json:"thumbnails,omitempty"`
+	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
+	ContentDetails *struct {
+		TotalItemCount int    `bson:"totalItemCount,omitempty" json:"totalItemCount,omitempty"`
+		NewItemCount   int    `bson:"newItemCount,omitempty" json:"newItemCount,omitempty"`
+		ActivityType   string `bson:"activityType,omitempty" json:"activityType,omitempty"`
+	} `bson:"contentDetails,omitempty" json:"contentDetails,omitempty"`
+}
+
+// GetSubscriptions retrieves up to maxResults public subscriptions of a channel.
+// Channels that keep their subscriptions private return ErrForbidden. Results are cached.
+func (yt *YoutubeApi) GetSubscriptions(channelId string, maxResults int) (*SubscriptionResults, error) {
+	return yt.GetSubscriptionsContext(context.Background(), channelId, maxResults)
+}
+
+// GetSubscriptionsContext is like GetSubscriptions but uses ctx for every page request.
+func (yt *YoutubeApi) GetSubscriptionsContext(ctx context.Context, channelId string, maxResults int) (*SubscriptionResults, error) {
+	cacheKey := CacheKey("subscriptions", url.Values{"channelId": {channelId}, "maxResults": {strconv.Itoa(maxResults)}})
+	if v := yt.Cache.GetSubscriptions(cacheKey); v != nil {
+		return v, nil
+	}
+
+	// Private or missing channels are recorded once, whatever the number of results asked for.
+	notFoundKey := CacheKey("subscriptions", url.Values{"channelId": {channelId}})
+	if err := yt.cachedNotFound(RegionSubscriptions, notFoundKey, channelId); err != nil {
+		return nil, err
+	}
+
+	results, err := yt.listSubscriptions(ctx, "channelId="+url.QueryEscape(channelId), maxResults, authAuto)
+	if err != nil {
+		yt.rememberNotFound(RegionSubscriptions, notFoundKey, err)
+		return nil, err
+	}
+
+	yt.Cache.SetSubscriptions(cacheKey, results)
+
+	return results, nil
+}
+
+// GetMySubscriptions retrieves up to maxResults subscriptions of the authenticated user.
+// Requires OAuth2 credentials. User-scoped results are not cached since they change with every
+// Subscribe and Unsubscribe call.
+func (yt *YoutubeApi) GetMySubscriptions(maxResults int) (*SubscriptionResults, error) {
+	return yt.GetMySubscriptionsContext(context.Background(), maxResults)
+}
+
+// GetMySubscriptionsContext is like GetMySubscriptions but uses ctx for every page request.
+func (yt *YoutubeApi) GetMySubscriptionsContext(ctx context.Context, maxResults int) (*SubscriptionResults, error) {
+	return yt.listSubscriptions(ctx, "mine=true", maxResults, authUser)
+}
+
+// listSubscriptions pages through subscriptions.list for the given filter parameter.
+// A maxResults of zero or less fetches every page.
+func (yt *YoutubeApi) listSubscriptions(ctx context.Context, filter string, maxResults int, auth authMode) (*SubscriptionResults, error) {
+	results := &SubscriptionResults{}
+	nextPage := ""
+	for maxResults <= 0 || len(results.Items) < maxResults {
+		nextPageStr := ""
+		if nextPage != "" {
+			nextPageStr = fmt.Sprintf("&pageToken=%v", nextPage)
+		}
+		body, err := yt.sendRequest(ctx, http.MethodGet, fmt.Sprintf(ListSubscriptions, filter, yt.apiKey, nextPageStr), nil, auth)
+		if err != nil {
+			return nil, err
+		}
+		page := &SubscriptionResults{}
+		if err := json.Unmarshal(body, page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal subscriptions: %w", err)
+		}
+		results.Items = append(results.Items, page.Items...)
+
+		nextPage = page.NextPageToken
+		if nextPage == "" {
+			break
+		}
+	}
+	if maxResults > 0 && len(results.Items) > maxResults {
+		results.Items = results.Items[:maxResults]
+	}
+	results.NextPageToken = nextPage
+	return results, nil
+}
+
+// Subscribe subscribes the authenticated user to a channel. Requires OAuth2 credentials.
+func (yt *YoutubeApi) Subscribe(channelId string) (*Subscription, error) {
+	return yt.SubscribeContext(context.Background(), channelId)
+}
+
+// SubscribeContext is like Subscribe but uses ctx for the underlying API request.
+func (yt *YoutubeApi) SubscribeContext(ctx context.Context, channelId string) (*Subscription, error) {
+	body := map[string]interface{}{
+		"snippet": map[string]interface{}{
+			"resourceId": &ResourceId{Kind: "youtube#channel", ChannelId: channelId},
+		},
+	}
+	subscription := &Subscription{}
+	if err := yt.userJSONRequest(ctx, http.MethodPost, fmt.Sprintf(InsertSubscription, yt.apiKey), body, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// Unsubscribe deletes a subscription by its subscription ID (not the channel ID). Requires OAuth2 credentials.
+func (yt *YoutubeApi) Unsubscribe(subscriptionId string) error {
+	return yt.UnsubscribeContext(context.Background(), subscriptionId)
+}
+
+// UnsubscribeContext is like Unsubscribe but uses ctx for the underlying API request.
+func (yt *YoutubeApi) UnsubscribeContext(ctx context.Context, subscriptionId string) error {
+	return yt.userJSONRequest(ctx, http.MethodDelete, fmt.Sprintf(DeleteSubscription, url.QueryEscape(subscriptionId), yt.apiKey), nil, nil)
+}
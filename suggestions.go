@@ -0,0 +1,215 @@
+package alaitube
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Defaults of SuggestTags.
+const (
+	DefaultSuggestionPages    = 1
+	DefaultSuggestionVideos   = 25
+	DefaultSuggestionLimit    = 20
+	DefaultSuggestionMinCount = 2
+)
+
+// suggestionEngagementBoost scales the engagement rate into a video's weight, so a video with 5%
+// of its viewers liking or commenting weighs 1.5 times as much as one nobody interacts with.
+const suggestionEngagementBoost = 10
+
+// DefaultNoiseTags are generic tags dropped from suggestions, since they say nothing about a topic.
+var DefaultNoiseTags = []string{
+	"video", "videos", "youtube", "youtuber", "new", "latest", "viral", "trending", "subscribe",
+	"like", "share", "funny", "vlog", "official", "hd", "4k", "shorts", "short", "fyp",
+}
+
+// TagSuggestion is a tag suggested by SuggestTags.
+type TagSuggestion struct {
+	// Tag is the most common original spelling of the tag.
+	Tag string `bson:"tag" json:"tag"`
+	// Key is the normalized form the tag was counted under.
+	Key string `bson:"key" json:"key"`
+	// Score adds up the weight of every video using the tag: the logarithm of its views, boosted
+	// by its engagement rate.
+	Score float64 `bson:"score" json:"score"`
+	// Count is the number of videos using the tag.
+	Count int `bson:"count" json:"count"`
+	// Views is the total view count of the videos using the tag.
+	Views int64 `bson:"views" json:"views"`
+}
+
+// suggestion holds the settings of a SuggestTags call.
+type suggestion struct {
+	pages    int
+	videos   int
+	limit    int
+	minCount int
+	noise    map[string]bool
+	analyzer *TagAnalyzer
+	search   SearchOptions
+}
+
+// TagSuggestionOption configures SuggestTags.
+type TagSuggestionOption func(*suggestion)
+
+// WithSuggestionPages sets how many search pages of 50 results are fetched for the keyword.
+func WithSuggestionPages(pages int) TagSuggestionOption {
+	return func(s *suggestion) {
+		s.pages = pages
+	}
+}
+
+// WithSuggestionVideos sets how many of the most viewed results the tags are taken from. Zero or
+// less uses every result.
+func WithSuggestionVideos(n int) TagSuggestionOption {
+	return func(s *suggestion) {
+		s.videos = n
+	}
+}
+
+// WithSuggestionLimit bounds the number of suggestions. Zero or less returns every suggestion.
+func WithSuggestionLimit(n int) TagSuggestionOption {
+	return func(s *suggestion) {
+		s.limit = n
+	}
+}
+
+// WithSuggestionMinCount drops the tags used by fewer than n videos, such as channel names and
+// other one-off tags.
+func WithSuggestionMinCount(n int) TagSuggestionOption {
+	return func(s *suggestion) {
+		s.minCount = n
+	}
+}
+
+// WithNoiseTags replaces DefaultNoiseTags with tags. Without arguments, no tag is treated as noise.
+func WithNoiseTags(tags ...string) TagSuggestionOption {
+	return func(s *suggestion) {
+		s.noise = make(map[string]bool, len(tags))
+		for _, tag := range tags {
+			s.noise[strings.ToLower(strings.TrimSpace(tag))] = true
+		}
+	}
+}
+
+// WithSuggestionAnalyzer sets the TagAnalyzer whose Normalize merges tags, for instance to enable
+// stemming or stopword removal. By default tags are only case folded.
+func WithSuggestionAnalyzer(a *TagAnalyzer) TagSuggestionOption {
+	return func(s *suggestion) {
+		if a != nil {
+			s.analyzer = a
+		}
+	}
+}
+
+// WithSuggestionSearchOptions sets the options of the keyword search, such as a region or a
+// relevance language. Results are ordered by relevance unless opts.Order is set.
+func WithSuggestionSearchOptions(opts SearchOptions) TagSuggestionOption {
+	return func(s *suggestion) {
+		s.search = opts
+	}
+}
+
+// SuggestTags suggests tags for a video about keyword. It searches for keyword like FindTags,
+// keeps the DefaultSuggestionVideos most viewed results, and ranks their tags by Score, so tags
+// used across many popular and engaging videos come first. The keyword itself, DefaultNoiseTags,
+// numbers, tags of a single character, the channel names of the videos, and tags used by fewer
+// than DefaultSuggestionMinCount videos are left out. At most DefaultSuggestionLimit suggestions
+// are returned; each default can be changed with a TagSuggestionOption.
+func (yt *YoutubeApi) SuggestTags(keyword string, opts ...TagSuggestionOption) ([]TagSuggestion, error) {
+	return yt.SuggestTagsContext(context.Background(), keyword, opts...)
+}
+
+// SuggestTagsContext is like SuggestTags but uses ctx for every request.
+func (yt *YoutubeApi) SuggestTagsContext(ctx context.Context, keyword string, opts ...TagSuggestionOption) (_ []TagSuggestion, err error) {
+	ctx, span := yt.startOperation(ctx, "SuggestTags")
+	defer func() { endSpan(span, err) }()
+
+	s := suggestion{
+		pages:    DefaultSuggestionPages,
+		videos:   DefaultSuggestionVideos,
+		limit:    DefaultSuggestionLimit,
+		minCount: DefaultSuggestionMinCount,
+		analyzer: NewTagAnalyzer(),
+	}
+	WithNoiseTags(DefaultNoiseTags...)(&s)
+	for _, opt := range opts {
+		opt(&s)
+	}
+	if s.search.Order == "" {
+		s.search.Order = OrderRelevance
+	}
+
+	results, err := yt.FindTagsContext(ctx, keyword, s.pages, s.search)
+	if err != nil {
+		return nil, err
+	}
+	return s.rank(keyword, results.Sort(SortViews).Limit(s.videos)), nil
+}
+
+// rank aggregates the tags of results into suggestions for keyword.
+func (s suggestion) rank(keyword string, results *VideoResults) []TagSuggestion {
+	stats := make(map[string]*TagSuggestion)
+	spellings := make(map[string]map[string]int)
+	for _, v := range results.Items {
+		if v.Snippet == nil {
+			continue
+		}
+		var views int64
+		if v.Statistics != nil {
+			views = int64(v.Statistics.ViewCount)
+		}
+		weight := math.Log1p(float64(views)) * (1 + suggestionEngagementBoost*v.EngagementRate())
+		channel := s.analyzer.Normalize(v.Snippet.ChannelTitle)
+
+		seen := make(map[string]bool)
+		for _, tag := range v.Snippet.Tags {
+			key := s.analyzer.Normalize(tag)
+			if key == "" || seen[key] || key == channel {
+				continue
+			}
+			seen[key] = true
+			stat, ok := stats[key]
+			if !ok {
+				stat = &TagSuggestion{Key: key}
+				stats[key] = stat
+				spellings[key] = make(map[string]int)
+			}
+			stat.Count++
+			stat.Views += views
+			stat.Score += weight
+			spellings[key][strings.TrimSpace(tag)]++
+		}
+	}
+
+	keywordKey := s.analyzer.Normalize(keyword)
+	var suggestions []TagSuggestion
+	for key, stat := range stats {
+		if key == keywordKey || stat.Count < s.minCount || s.isNoise(key) {
+			continue
+		}
+		stat.Tag = mostCommon(spellings[key])
+		suggestions = append(suggestions, *stat)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Key < suggestions[j].Key
+	})
+	if s.limit > 0 && len(suggestions) > s.limit {
+		suggestions = suggestions[:s.limit]
+	}
+	return suggestions
+}
+
+// isNoise reports whether the normalized tag key is too generic to be suggested.
+func (s suggestion) isNoise(key string) bool {
+	if s.noise[strings.ToLower(key)] || len([]rune(key)) < 2 {
+		return true
+	}
+	return strings.IndexFunc(key, func(r rune) bool { return !unicode.IsNumber(r) && !unicode.IsSpace(r) }) < 0
+}
@@ -0,0 +1,144 @@
+package alaitube
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxTagsLength is the longest tag list YouTube accepts when uploading or updating a video.
+const DefaultMaxTagsLength = 500
+
+// TagFormatter joins a video's tags into a single string, such as the Snippet.FormattedTags of
+// videos fetched by a client created with WithTagFormatter.
+type TagFormatter struct {
+	separator string
+	hashtags  bool
+	maxLength int
+	dedupe    bool
+}
+
+// TagFormatterOption configures a TagFormatter created with NewTagFormatter.
+type TagFormatterOption func(*TagFormatter)
+
+// WithTagSeparator sets the string tags are joined with. The default is ", ".
+func WithTagSeparator(separator string) TagFormatterOption {
+	return func(f *TagFormatter) {
+		f.separator = separator
+	}
+}
+
+// WithHashtagPrefix formats every tag as a hashtag, e.g. "#golangtutorial" for "golang tutorial".
+// Spaces and punctuation are dropped, since hashtags can't contain them.
+func WithHashtagPrefix(hashtags bool) TagFormatterOption {
+	return func(f *TagFormatter) {
+		f.hashtags = hashtags
+	}
+}
+
+// WithMaxTagsLength drops the tags that would make the formatted string longer than max
+// characters. Zero or less keeps every tag.
+func WithMaxTagsLength(max int) TagFormatterOption {
+	return func(f *TagFormatter) {
+		f.maxLength = max
+	}
+}
+
+// WithTagDedupe sets whether tags differing only by case or surrounding spaces are kept once.
+// It is enabled by default.
+func WithTagDedupe(dedupe bool) TagFormatterOption {
+	return func(f *TagFormatter) {
+		f.dedupe = dedupe
+	}
+}
+
+// NewTagFormatter creates a TagFormatter joining tags with ", ", without duplicates, and within
+// DefaultMaxTagsLength characters.
+func NewTagFormatter(opts ...TagFormatterOption) *TagFormatter {
+	f := &TagFormatter{separator: ", ", maxLength: DefaultMaxTagsLength, dedupe: true}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Format joins tags, keeping their order. Empty tags are skipped.
+func (f *TagFormatter) Format(tags []string) string {
+	var b strings.Builder
+	length := 0
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if f.hashtags {
+			tag = hashtag(tag)
+		}
+		if tag == "" {
+			continue
+		}
+		if f.dedupe {
+			key := strings.ToLower(tag)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		added := utf8.RuneCountInString(tag)
+		if length > 0 {
+			added += utf8.RuneCountInString(f.separator)
+		}
+		if f.maxLength > 0 && length+added > f.maxLength {
+			continue
+		}
+		if length > 0 {
+			b.WriteString(f.separator)
+		}
+		b.WriteString(tag)
+		length += added
+	}
+	return b.String()
+}
+
+// hashtag turns tag into a hashtag, keeping only its letters, digits, and underscores.
+func hashtag(tag string) string {
+	tag = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, tag)
+	if tag == "" {
+		return ""
+	}
+	return "#" + tag
+}
+
+// FormatTags sets Snippet.FormattedTags of every video of results with f.
+func (f *TagFormatter) FormatTags(results *VideoResults) {
+	for _, v := range results.Items {
+		if v.Snippet != nil {
+			v.Snippet.FormattedTags = f.Format(v.Snippet.Tags)
+		}
+	}
+}
+
+// WithTagFormatter populates Snippet.FormattedTags of the videos fetched by GetVideos, FindTags,
+// and the other video lookups with f, before they are cached.
+func WithTagFormatter(f *TagFormatter) Option {
+	return func(yt *YoutubeApi) {
+		yt.tagFormatter = f
+	}
+}
+
+// prepareTags normalizes the tags of results and populates their Snippet.FormattedTags with the
+// client's TagNormalizer and TagFormatter, when set.
+func (yt *YoutubeApi) prepareTags(results *VideoResults) {
+	if results == nil {
+		return
+	}
+	if yt.tagNormalizer != nil {
+		yt.tagNormalizer.NormalizeVideoTags(results)
+	}
+	if yt.tagFormatter != nil {
+		yt.tagFormatter.FormatTags(results)
+	}
+}
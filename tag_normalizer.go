@@ -0,0 +1,126 @@
+package alaitube
+
+import "strings"
+
+// TagTransform rewrites a single tag. Returning "" drops the tag.
+type TagTransform func(tag string) string
+
+// TagNormalizer cleans up the tags of videos: it trims them and collapses their inner spaces,
+// folds their case, removes stopwords, runs custom transforms, and drops empty and duplicate tags.
+// Unlike TagAnalyzer.Normalize, which computes comparison keys, it rewrites the tags themselves.
+type TagNormalizer struct {
+	foldCase   bool
+	dedupe     bool
+	stopwords  map[string]bool
+	transforms []TagTransform
+}
+
+// TagNormalizerOption configures a TagNormalizer created with NewTagNormalizer.
+type TagNormalizerOption func(*TagNormalizer)
+
+// WithNormalizerCaseFolding sets whether tags are lowercased. It is enabled by default.
+func WithNormalizerCaseFolding(fold bool) TagNormalizerOption {
+	return func(n *TagNormalizer) {
+		n.foldCase = fold
+	}
+}
+
+// WithNormalizerDedupe sets whether tags left identical after normalization are kept once. It is
+// enabled by default.
+func WithNormalizerDedupe(dedupe bool) TagNormalizerOption {
+	return func(n *TagNormalizer) {
+		n.dedupe = dedupe
+	}
+}
+
+// WithNormalizerStopwords drops the given words from tags, discarding tags made only of
+// stopwords. Without arguments, DefaultStopwords are used. Repeated options add up, so
+// DefaultStopwords can be extended with a second call listing custom words.
+func WithNormalizerStopwords(words ...string) TagNormalizerOption {
+	return func(n *TagNormalizer) {
+		if len(words) == 0 {
+			words = DefaultStopwords
+		}
+		if n.stopwords == nil {
+			n.stopwords = make(map[string]bool, len(words))
+		}
+		for _, w := range words {
+			n.stopwords[strings.ToLower(w)] = true
+		}
+	}
+}
+
+// WithNormalizerTransforms appends transforms to the pipeline. They run in order after the
+// built-in steps, and before duplicates are dropped.
+func WithNormalizerTransforms(transforms ...TagTransform) TagNormalizerOption {
+	return func(n *TagNormalizer) {
+		n.transforms = append(n.transforms, transforms...)
+	}
+}
+
+// NewTagNormalizer creates a TagNormalizer with case folding and deduplication enabled and no
+// stopword removal.
+func NewTagNormalizer(opts ...TagNormalizerOption) *TagNormalizer {
+	n := &TagNormalizer{foldCase: true, dedupe: true}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Normalize returns the normalized form of tag, or "" when nothing is left of it.
+func (n *TagNormalizer) Normalize(tag string) string {
+	words := strings.Fields(tag)
+	kept := words[:0]
+	for _, w := range words {
+		if n.foldCase {
+			w = strings.ToLower(w)
+		}
+		if n.stopwords[strings.ToLower(w)] {
+			continue
+		}
+		kept = append(kept, w)
+	}
+	tag = strings.Join(kept, " ")
+	for _, t := range n.transforms {
+		if tag == "" {
+			break
+		}
+		tag = t(tag)
+	}
+	return tag
+}
+
+// NormalizeTags returns the normalized tags, in their original order, without empty tags and,
+// unless disabled, without duplicates.
+func (n *TagNormalizer) NormalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = n.Normalize(tag)
+		if tag == "" || (n.dedupe && seen[tag]) {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// NormalizeVideoTags replaces the Snippet.Tags of every video of results with their normalized
+// form. The videos are modified in place.
+func (n *TagNormalizer) NormalizeVideoTags(results *VideoResults) {
+	for _, v := range results.Items {
+		if v.Snippet != nil && len(v.Snippet.Tags) > 0 {
+			v.Snippet.Tags = n.NormalizeTags(v.Snippet.Tags)
+		}
+	}
+}
+
+// WithTagNormalizer normalizes the Snippet.Tags of the videos fetched by GetVideos, FindTags, and
+// the other video lookups with n, before they are cached and before a TagFormatter runs.
+func WithTagNormalizer(n *TagNormalizer) Option {
+	return func(yt *YoutubeApi) {
+		yt.tagNormalizer = n
+	}
+}
@@ -0,0 +1,282 @@
+// Package testutil provides a fake YouTube Data API server for end-to-end tests of code using alaitube.
+//
+//	srv := testutil.NewServer()
+//	defer srv.Close()
+//	srv.AddVideo(video)
+//	client := alaitube.NewClient(alaitube.WithApiKey("test"), alaitube.WithHttpClient(srv.Client()))
+//
+// The server implements the search, videos, channels, and playlistItems endpoints with
+// pagination, and can be told to fail requests to exercise error handling.
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/josephalai/alaitube"
+)
+
+// DefaultPageSize is the number of items per page when a request doesn't ask for fewer.
+const DefaultPageSize = 50
+
+// Server is an httptest-based fake of the YouTube Data API. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	pageSize  int
+	videos    map[string]*alaitube.Video
+	order     []string
+	channels  map[string]*alaitube.Item
+	playlists map[string][]string
+	searches  map[string][]string
+	failures  map[string][]failure
+	requests  map[string]int
+}
+
+// failure is an injected error response.
+type failure struct {
+	status int
+	reason string
+}
+
+// NewServer starts a fake server with no data.
+func NewServer() *Server {
+	s := &Server{
+		pageSize:  DefaultPageSize,
+		videos:    make(map[string]*alaitube.Video),
+		channels:  make(map[string]*alaitube.Item),
+		playlists: make(map[string][]string),
+		searches:  make(map[string][]string),
+		failures:  make(map[string][]failure),
+		requests:  make(map[string]int),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/youtube/v3/search", s.handle("search", s.search))
+	mux.HandleFunc("/youtube/v3/videos", s.handle("videos", s.listVideos))
+	mux.HandleFunc("/youtube/v3/channels", s.handle("channels", s.listChannels))
+	mux.HandleFunc("/youtube/v3/channels/", s.handle("channels", s.listChannels))
+	mux.HandleFunc("/youtube/v3/playlistItems", s.handle("playlistItems", s.listPlaylistItems))
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Client returns an http.Client that sends every request, whatever its host, to the fake server.
+// Pass it to alaitube.WithHttpClient.
+func (s *Server) Client() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{Transport: rewriteTransport{target: target, next: s.Server.Client().Transport}}
+}
+
+// rewriteTransport redirects requests to the fake server while keeping their path and query.
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return t.next.RoundTrip(req)
+}
+
+// SetPageSize caps the number of items returned per page, to exercise pagination with little data.
+func (s *Server) SetPageSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = n
+}
+
+// AddVideo registers a video served by the videos endpoint and matched by searches on its title.
+func (s *Server) AddVideo(v *alaitube.Video) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.videos[v.Id]; !ok {
+		s.order = append(s.order, v.Id)
+	}
+	s.videos[v.Id] = v
+}
+
+// AddChannel registers a channel served by the channels endpoint.
+func (s *Server) AddChannel(c *alaitube.Item) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels[c.Id] = c
+}
+
+// SetPlaylist sets the videos of a playlist, in order, served by the playlistItems endpoint.
+func (s *Server) SetPlaylist(playlistId string, videoIds ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.playlists[playlistId] = videoIds
+}
+
+// SetSearchResults sets the videos returned for a search query, in order.
+// Queries without explicit results match the videos whose title contains the query.
+func (s *Server) SetSearchResults(query string, videoIds ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searches[query] = videoIds
+}
+
+// FailNext makes the next times requests to endpoint ("search", "videos", "channels",
+// or "playlistItems") fail with status and the YouTube error reason, e.g. 403 "quotaExceeded".
+func (s *Server) FailNext(endpoint string, times, status int, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < times; i++ {
+		s.failures[endpoint] = append(s.failures[endpoint], failure{status: status, reason: reason})
+	}
+}
+
+// Requests returns the number of requests received by endpoint, including failed ones.
+func (s *Server) Requests(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[endpoint]
+}
+
+// handle wraps an endpoint handler with request counting, error injection, and JSON encoding.
+func (s *Server) handle(endpoint string, fn func(q url.Values) interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[endpoint]++
+		var fail *failure
+		if pending := s.failures[endpoint]; len(pending) > 0 {
+			fail = &pending[0]
+			s.failures[endpoint] = pending[1:]
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if fail != nil {
+			w.WriteHeader(fail.status)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":    fail.status,
+					"message": "injected " + fail.reason,
+					"errors":  []map[string]string{{"reason": fail.reason, "message": "injected " + fail.reason}},
+				},
+			})
+			return
+		}
+
+		s.mu.Lock()
+		body := fn(r.URL.Query())
+		s.mu.Unlock()
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+// page returns the slice of ids for pageToken and the token of the following page.
+func (s *Server) page(ids []string, q url.Values) ([]string, string) {
+	size := s.pageSize
+	if max, err := strconv.Atoi(q.Get("maxResults")); err == nil && max > 0 && max < size {
+		size = max
+	}
+	start, _ := strconv.Atoi(strings.TrimPrefix(q.Get("pageToken"), "page-"))
+	if start > len(ids) {
+		start = len(ids)
+	}
+	end := start + size
+	if end >= len(ids) {
+		return ids[start:], ""
+	}
+	return ids[start:end], "page-" + strconv.Itoa(end)
+}
+
+func (s *Server) search(q url.Values) interface{} {
+	query := q.Get("q")
+	ids, ok := s.searches[query]
+	if !ok {
+		for _, id := range s.order {
+			v := s.videos[id]
+			if v.Snippet != nil && strings.Contains(strings.ToLower(v.Snippet.Title), strings.ToLower(query)) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	pageIds, next := s.page(ids, q)
+
+	items := make([]map[string]interface{}, 0, len(pageIds))
+	for _, id := range pageIds {
+		item := map[string]interface{}{
+			"id": map[string]string{"kind": "youtube#video", "videoId": id},
+		}
+		if v, ok := s.videos[id]; ok && v.Snippet != nil {
+			item["snippet"] = map[string]interface{}{
+				"channelId":    v.Snippet.ChannelId,
+				"channelTitle": v.Snippet.ChannelTitle,
+				"title":        v.Snippet.Title,
+				"publishedAt":  v.Snippet.PublishedAt,
+				"thumbnails":   v.Snippet.Thumbnails,
+			}
+		} else {
+			item["snippet"] = map[string]interface{}{}
+		}
+		items = append(items, item)
+	}
+	return map[string]interface{}{"items": items, "nextPageToken": next}
+}
+
+func (s *Server) listVideos(q url.Values) interface{} {
+	var ids []string
+	for _, id := range strings.Split(q.Get("id"), ",") {
+		if _, ok := s.videos[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	pageIds, next := s.page(ids, q)
+
+	items := make([]*alaitube.Video, 0, len(pageIds))
+	for _, id := range pageIds {
+		items = append(items, s.videos[id])
+	}
+	return map[string]interface{}{"items": items, "nextPageToken": next}
+}
+
+func (s *Server) listChannels(q url.Values) interface{} {
+	items := make([]*alaitube.Item, 0)
+	for _, id := range strings.Split(q.Get("id"), ",") {
+		if c, ok := s.channels[id]; ok {
+			items = append(items, c)
+		}
+	}
+	return map[string]interface{}{"items": items}
+}
+
+func (s *Server) listPlaylistItems(q url.Values) interface{} {
+	ids := s.playlists[q.Get("playlistId")]
+	pageIds, next := s.page(ids, q)
+
+	items := make([]map[string]interface{}, 0, len(pageIds))
+	for i, id := range pageIds {
+		snippet := map[string]interface{}{}
+		if v, ok := s.videos[id]; ok && v.Snippet != nil {
+			snippet = map[string]interface{}{
+				"title":        v.Snippet.Title,
+				"channelTitle": v.Snippet.ChannelTitle,
+				"publishedAt":  v.Snippet.PublishedAt,
+				"thumbnails":   v.Snippet.Thumbnails,
+			}
+		}
+		items = append(items, map[string]interface{}{
+			"id":             fmt.Sprintf("%s-%d", q.Get("playlistId"), i),
+			"snippet":        snippet,
+			"contentDetails": map[string]string{"videoId": id},
+		})
+	}
+	return map[string]interface{}{
+		"items":         items,
+		"nextPageToken": next,
+		"pageInfo":      map[string]int{"totalResults": len(ids)},
+	}
+}
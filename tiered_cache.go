@@ -0,0 +1,240 @@
+package alaitube
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// TieredCache composes several caches, fastest first, e.g. a MemoryCache in front of a
+// RedisCache or FileCache. Reads try each tier in order and copy a hit into the tiers before
+// it; writes and invalidations go to every tier.
+type TieredCache struct {
+	tiers []Cache
+	stats []tierCounters
+	hits  hitCounters
+}
+
+type tierCounters struct {
+	hits, misses atomic.Int64
+}
+
+// TierStats reports the lookups served by one tier of a TieredCache.
+// A tier only sees the lookups missed by the tiers before it.
+type TierStats struct {
+	Name   string `bson:"name" json:"name"`
+	Hits   int64  `bson:"hits" json:"hits"`
+	Misses int64  `bson:"misses" json:"misses"`
+}
+
+// HitRate returns the fraction of the tier's lookups that were hits.
+func (s TierStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// NewTieredCache creates a TieredCache over tiers, fastest first.
+func NewTieredCache(tiers ...Cache) *TieredCache {
+	return &TieredCache{tiers: tiers, stats: make([]tierCounters, len(tiers)), hits: newHitCounters()}
+}
+
+// Stats returns the lookups of every region, a hit being a lookup served by any tier. Entries
+// and Bytes are those of the last tier, which holds every entry.
+func (c *TieredCache) Stats() CacheStats {
+	stats := c.hits.stats(c.GetServiceName())
+	if len(c.tiers) == 0 {
+		return stats
+	}
+	last := c.tiers[len(c.tiers)-1].Stats()
+	for region, r := range stats.Regions {
+		if l, ok := last.Regions[region]; ok {
+			r.Entries, r.Bytes = l.Entries, l.Bytes
+			stats.Regions[region] = r
+		}
+	}
+	return stats
+}
+
+// TierStats returns the hit statistics of every tier, in tier order.
+func (c *TieredCache) TierStats() []TierStats {
+	stats := make([]TierStats, len(c.tiers))
+	for i, tier := range c.tiers {
+		stats[i] = TierStats{
+			Name:   tier.GetServiceName(),
+			Hits:   c.stats[i].hits.Load(),
+			Misses: c.stats[i].misses.Load(),
+		}
+	}
+	return stats
+}
+
+// tieredGet looks key up tier by tier, populating the tiers that missed once a tier hits.
+func tieredGet[T any](c *TieredCache, region CacheRegion, get func(Cache) *T, set func(Cache, *T)) *T {
+	for i, tier := range c.tiers {
+		v := get(tier)
+		if v == nil {
+			c.stats[i].misses.Add(1)
+			continue
+		}
+		c.stats[i].hits.Add(1)
+		for _, upper := range c.tiers[:i] {
+			set(upper, v)
+		}
+		c.hits.record(region, true)
+		return v
+	}
+	c.hits.record(region, false)
+	return nil
+}
+
+// SetNotFound records key as missing in region in every tier that supports negative caching.
+func (c *TieredCache) SetNotFound(region CacheRegion, key string) {
+	for _, tier := range c.tiers {
+		if nc, ok := tier.(NegativeCache); ok {
+			nc.SetNotFound(region, key)
+		}
+	}
+}
+
+// IsNotFound reports whether any tier records key as missing in region, copying the
+// record into the tiers before it.
+func (c *TieredCache) IsNotFound(region CacheRegion, key string) bool {
+	for i, tier := range c.tiers {
+		nc, ok := tier.(NegativeCache)
+		if !ok || !nc.IsNotFound(region, key) {
+			continue
+		}
+		for _, upper := range c.tiers[:i] {
+			if nc, ok := upper.(NegativeCache); ok {
+				nc.SetNotFound(region, key)
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Delete removes the entry for key in region from every tier.
+func (c *TieredCache) Delete(region CacheRegion, key string) {
+	for _, tier := range c.tiers {
+		tier.Delete(region, key)
+	}
+}
+
+// PurgeRegion removes every entry of region from every tier.
+func (c *TieredCache) PurgeRegion(region CacheRegion) {
+	for _, tier := range c.tiers {
+		tier.PurgeRegion(region)
+	}
+}
+
+// PurgeAll removes every entry from every tier.
+func (c *TieredCache) PurgeAll() {
+	for _, tier := range c.tiers {
+		tier.PurgeAll()
+	}
+}
+
+// GetVideo retrieves a video from Cache.
+func (c *TieredCache) GetVideo(key string) *VideoResults {
+	return tieredGet(c, RegionVideos, func(t Cache) *VideoResults { return t.GetVideo(key) },
+		func(t Cache, v *VideoResults) { t.SetVideo(key, v) })
+}
+
+// SetVideo stores a video to Cache.
+func (c *TieredCache) SetVideo(key string, video *VideoResults) {
+	for _, tier := range c.tiers {
+		tier.SetVideo(key, video)
+	}
+}
+
+// GetChannel retrieves a channel from Cache.
+func (c *TieredCache) GetChannel(key string) *ChannelInfo {
+	return tieredGet(c, RegionChannels, func(t Cache) *ChannelInfo { return t.GetChannel(key) },
+		func(t Cache, v *ChannelInfo) { t.SetChannel(key, v) })
+}
+
+// SetChannel stores a channel to Cache.
+func (c *TieredCache) SetChannel(key string, channel *ChannelInfo) {
+	for _, tier := range c.tiers {
+		tier.SetChannel(key, channel)
+	}
+}
+
+// GetPlaylist retrieves a playlist from Cache.
+func (c *TieredCache) GetPlaylist(key string) *VideoResults {
+	return tieredGet(c, RegionPlaylists, func(t Cache) *VideoResults { return t.GetPlaylist(key) },
+		func(t Cache, v *VideoResults) { t.SetPlaylist(key, v) })
+}
+
+// SetPlaylist stores a playlist to Cache.
+func (c *TieredCache) SetPlaylist(key string, playlist *VideoResults) {
+	for _, tier := range c.tiers {
+		tier.SetPlaylist(key, playlist)
+	}
+}
+
+// GetVideoDetail retrieves a VideoDetail from Cache.
+func (c *TieredCache) GetVideoDetail(key string) *VideoResults {
+	return tieredGet(c, RegionVideoDetails, func(t Cache) *VideoResults { return t.GetVideoDetail(key) },
+		func(t Cache, v *VideoResults) { t.SetVideoDetail(key, v) })
+}
+
+// SetVideoDetail stores a VideoDetail to Cache.
+func (c *TieredCache) SetVideoDetail(key string, detail *VideoResults) {
+	for _, tier := range c.tiers {
+		tier.SetVideoDetail(key, detail)
+	}
+}
+
+// GetCommentThreads retrieves comment threads from Cache.
+func (c *TieredCache) GetCommentThreads(key string) *CommentThreadResults {
+	return tieredGet(c, RegionComments, func(t Cache) *CommentThreadResults { return t.GetCommentThreads(key) },
+		func(t Cache, v *CommentThreadResults) { t.SetCommentThreads(key, v) })
+}
+
+// SetCommentThreads stores comment threads to Cache.
+func (c *TieredCache) SetCommentThreads(key string, threads *CommentThreadResults) {
+	for _, tier := range c.tiers {
+		tier.SetCommentThreads(key, threads)
+	}
+}
+
+// GetCategories retrieves video categories from Cache.
+func (c *TieredCache) GetCategories(key string) *VideoCategoryResults {
+	return tieredGet(c, RegionCategories, func(t Cache) *VideoCategoryResults { return t.GetCategories(key) },
+		func(t Cache, v *VideoCategoryResults) { t.SetCategories(key, v) })
+}
+
+// SetCategories stores video categories to Cache.
+func (c *TieredCache) SetCategories(key string, categories *VideoCategoryResults) {
+	for _, tier := range c.tiers {
+		tier.SetCategories(key, categories)
+	}
+}
+
+// GetSubscriptions retrieves subscriptions from Cache.
+func (c *TieredCache) GetSubscriptions(key string) *SubscriptionResults {
+	return tieredGet(c, RegionSubscriptions, func(t Cache) *SubscriptionResults { return t.GetSubscriptions(key) },
+		func(t Cache, v *SubscriptionResults) { t.SetSubscriptions(key, v) })
+}
+
+// SetSubscriptions stores subscriptions to Cache.
+func (c *TieredCache) SetSubscriptions(key string, subscriptions *SubscriptionResults) {
+	for _, tier := range c.tiers {
+		tier.SetSubscriptions(key, subscriptions)
+	}
+}
+
+// GetServiceName returns the names of the tiers, e.g. "tiered(memory-cache,redis-cache)".
+func (c *TieredCache) GetServiceName() string {
+	names := make([]string, len(c.tiers))
+	for i, tier := range c.tiers {
+		names[i] = tier.GetServiceName()
+	}
+	return "tiered(" + strings.Join(names, ",") + ")"
+}
+
+var _ Cache = (*TieredCache)(nil)
+var _ NegativeCache = (*TieredCache)(nil)
@@ -0,0 +1,99 @@
+package alaitube
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName is the instrumentation scope of the spans created by the package.
+const tracerName = "github.com/josephalai/alaitube"
+
+// Span attribute keys set by the package.
+const (
+	AttrEndpoint   = attribute.Key("youtube.endpoint")
+	AttrPage       = attribute.Key("youtube.page")
+	AttrBatchSize  = attribute.Key("youtube.batch_size")
+	AttrCacheHit   = attribute.Key("youtube.cache.hit")
+	AttrAttempts   = attribute.Key("youtube.attempts")
+	AttrQuotaUnits = attribute.Key("youtube.quota.units")
+	AttrErrReason  = attribute.Key("youtube.error.reason")
+	attrMethod     = attribute.Key("http.request.method")
+	attrStatus     = attribute.Key("http.response.status_code")
+)
+
+// WithTracerProvider enables OpenTelemetry tracing. Every API call gets a span, named after the
+// operation such as "youtube videos.list", which is a child of the span found in the caller's context.
+// Without this option no spans are recorded.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(yt *YoutubeApi) {
+		if tp != nil {
+			yt.tracer = tp.Tracer(tracerName)
+		}
+	}
+}
+
+// defaultTracer records nothing.
+var defaultTracer = noop.NewTracerProvider().Tracer(tracerName)
+
+type spanAttrsKey struct{}
+
+// withSpanAttributes returns a context whose API call spans carry attrs in addition to their own,
+// letting paginating callers annotate the requests they make with a page number or batch size.
+func withSpanAttributes(ctx context.Context, attrs ...attribute.KeyValue) context.Context {
+	existing, _ := ctx.Value(spanAttrsKey{}).([]attribute.KeyValue)
+	merged := append(append([]attribute.KeyValue(nil), existing...), attrs...)
+	return context.WithValue(ctx, spanAttrsKey{}, merged)
+}
+
+// startOperation starts a span around a public operation, such as GetVideos, whose API calls become its children.
+func (yt *YoutubeApi) startOperation(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return yt.tracer.Start(ctx, "youtube "+name, trace.WithAttributes(attrs...))
+}
+
+// operationName maps an endpoint and HTTP method to the Data API operation, such as "videos.list".
+func operationName(endpoint, method string) string {
+	verb := "list"
+	switch method {
+	case http.MethodPost:
+		verb = "insert"
+	case http.MethodPut:
+		verb = "update"
+	case http.MethodDelete:
+		verb = "delete"
+	}
+	return endpoint + "." + verb
+}
+
+// startRequestSpan starts the span of a single API call to apiUrl.
+func (yt *YoutubeApi) startRequestSpan(ctx context.Context, method, apiUrl string) (context.Context, trace.Span) {
+	endpoint := "unknown"
+	if u, err := url.Parse(apiUrl); err == nil {
+		endpoint = endpointName(u)
+	}
+	attrs, _ := ctx.Value(spanAttrsKey{}).([]attribute.KeyValue)
+	attrs = append(attrs,
+		AttrEndpoint.String(endpoint),
+		attrMethod.String(method),
+		AttrQuotaUnits.Int(QuotaCost(method, endpoint)),
+	)
+	return yt.tracer.Start(ctx, "youtube "+operationName(endpoint, method),
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records the outcome of an operation or API call on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		if reason := errorReason(err); reason != "" {
+			span.SetAttributes(AttrErrReason.String(reason))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,98 @@
+package alaitube
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// MaxTrendingResults is the most videos YouTube returns for the mostPopular chart.
+const MaxTrendingResults = 200
+
+// GetTrending returns the most popular videos of a region, optionally restricted to a video
+// category, with the client's default parts. regionCode is an ISO 3166-1 alpha-2 code; an
+// empty regionCode lets YouTube pick the US chart and an empty categoryId covers every
+// category. maxResults is capped at MaxTrendingResults; pages of 50 are requested as needed.
+// Results are cached by region, category, and size.
+func (yt *YoutubeApi) GetTrending(regionCode, categoryId string, maxResults int) (*VideoResults, error) {
+	return yt.GetTrendingContext(context.Background(), regionCode, categoryId, maxResults)
+}
+
+// GetTrendingContext is like GetTrending but uses ctx for every page request.
+func (yt *YoutubeApi) GetTrendingContext(ctx context.Context, regionCode, categoryId string, maxResults int) (_ *VideoResults, err error) {
+	ctx, span := yt.startOperation(ctx, "GetTrending")
+	defer func() { endSpan(span, err) }()
+
+	if maxResults <= 0 || maxResults > MaxTrendingResults {
+		maxResults = MaxTrendingResults
+	}
+	parts := yt.videoParts
+	cacheKey := CacheKey("trending", parts.keyValues(url.Values{
+		"regionCode":      {regionCode},
+		"videoCategoryId": {categoryId},
+		"maxResults":      {strconv.Itoa(maxResults)},
+	}, ""))
+	if v := yt.Cache.GetVideo(cacheKey); v != nil {
+		span.SetAttributes(AttrCacheHit.Bool(true))
+		return v.shallowCopy(), nil
+	}
+	span.SetAttributes(AttrCacheHit.Bool(false))
+
+	v, err := yt.coalesce(ctx, RegionVideos, cacheKey, func() (interface{}, error) {
+		results := &VideoResults{}
+		pageToken := ""
+		for page := 0; len(results.Items) < maxResults; page++ {
+			apiUrl := yt.trendingUrl(regionCode, categoryId, min(maxResults-len(results.Items), 50), parts, pageToken)
+			body, err := yt.httpGetRequest(withSpanAttributes(ctx, AttrPage.Int(page)), apiUrl)
+			if err != nil {
+				return nil, err
+			}
+			res, err := unmarshalResponse(body)
+			if err != nil {
+				return nil, err
+			}
+			results.Items = append(results.Items, res.Items...)
+			if res.NextPageToken == "" || res.NextPageToken == pageToken {
+				break
+			}
+			pageToken = res.NextPageToken
+		}
+		if len(results.Items) > maxResults {
+			results.Items = results.Items[:maxResults]
+		}
+		yt.prepareTags(results)
+		yt.Cache.SetVideo(cacheKey, results)
+		yt.persistVideos(ctx, results)
+		return results, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*VideoResults).shallowCopy(), nil
+}
+
+// trendingUrl builds the URL of a page of the mostPopular chart.
+func (yt *YoutubeApi) trendingUrl(regionCode, categoryId string, maxResults int, parts Parts, pageToken string) string {
+	v := url.Values{}
+	v.Set("key", yt.apiKey)
+	v.Set("chart", "mostPopular")
+	v.Set("part", parts.Part)
+	if parts.Fields != "" {
+		// The chart is paginated, so the partial response must keep the page token.
+		v.Set("fields", parts.Fields+",nextPageToken")
+	}
+	if parts.Hl != "" {
+		v.Set("hl", parts.Hl)
+	}
+	v.Set("maxResults", strconv.Itoa(maxResults))
+	if regionCode != "" {
+		v.Set("regionCode", regionCode)
+	}
+	if categoryId != "" {
+		v.Set("videoCategoryId", categoryId)
+	}
+	if pageToken != "" {
+		v.Set("pageToken", pageToken)
+	}
+	return VideosEndpoint + "?" + v.Encode()
+}
@@ -0,0 +1,49 @@
+package alaitube
+
+// Reasons reported in UnavailableVideo.Reason.
+const (
+	UnavailableDeleted = "deleted"
+	UnavailablePrivate = "private"
+	// UnavailableUnknown is reported for videos YouTube didn't return without saying why, such as
+	// videos removed for a policy violation or requested with a mistyped ID.
+	UnavailableUnknown = "unavailable"
+)
+
+// Placeholder titles YouTube gives the playlist items of deleted and private videos.
+const (
+	deletedVideoTitle = "Deleted video"
+	privateVideoTitle = "Private video"
+)
+
+// UnavailableVideo is a requested video YouTube returned no details for.
+type UnavailableVideo struct {
+	VideoId string `bson:"videoId,omitempty" json:"videoId,omitempty"`
+	// Title is the placeholder title of the playlist item, when the video came from a playlist.
+	Title string `bson:"title,omitempty" json:"title,omitempty"`
+	// Reason is UnavailableDeleted, UnavailablePrivate, or UnavailableUnknown.
+	Reason string `bson:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// unavailableVideos lists the IDs of ids missing from results. titles holds the playlist item
+// titles of the videos, if any, which tell deleted videos from private ones.
+func unavailableVideos(ids []string, results *VideoResults, titles map[string]string) []*UnavailableVideo {
+	found := make(map[string]bool, len(results.Items))
+	for _, v := range results.Items {
+		found[v.Id] = true
+	}
+	var unavailable []*UnavailableVideo
+	for _, id := range uniqueIds(ids) {
+		if found[id] {
+			continue
+		}
+		video := &UnavailableVideo{VideoId: id, Title: titles[id], Reason: UnavailableUnknown}
+		switch video.Title {
+		case deletedVideoTitle:
+			video.Reason = UnavailableDeleted
+		case privateVideoTitle:
+			video.Reason = UnavailablePrivate
+		}
+		unavailable = append(unavailable, video)
+	}
+	return unavailable
+}
@@ -0,0 +1,276 @@
+package alaitube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const UploadVideo = "https://www.googleapis.com/upload/youtube/v3/videos?uploadType=resumable&part=snippet,status&key=%s"
+
+// DefaultUploadChunkSize is the number of bytes sent per request when UploadOptions.ChunkSize is not set.
+// YouTube requires chunk sizes to be multiples of 256 KiB.
+const DefaultUploadChunkSize int64 = 8 << 20
+
+const uploadChunkAlignment int64 = 256 << 10
+
+// UploadOptions holds the metadata and transfer settings of a video upload.
+type UploadOptions struct {
+	Title       string
+	Description string
+	Tags        []string
+	CategoryId  string
+	// PrivacyStatus is one of PrivacyPublic, PrivacyPrivate (the default), or PrivacyUnlisted.
+	PrivacyStatus string
+	MadeForKids   bool
+	// ContentType is the MIME type of the file, "video/*" by default.
+	ContentType string
+	// ChunkSize is rounded down to a multiple of 256 KiB; DefaultUploadChunkSize is used when zero.
+	ChunkSize int64
+	// Progress, when set, is called after every chunk with the bytes confirmed by YouTube so far.
+	Progress func(sent, total int64)
+}
+
+// UploadSession identifies a resumable upload. It can be persisted and passed to ResumeUpload
+// to continue an interrupted upload, even from another process.
+type UploadSession struct {
+	URI  string `bson:"uri" json:"uri"`
+	Size int64  `bson:"size" json:"size"`
+}
+
+// UploadVideo uploads size bytes read from r as a new video owned by the authenticated user,
+// using YouTube's resumable upload protocol. Requires OAuth2 credentials.
+func (yt *YoutubeApi) UploadVideo(r io.ReaderAt, size int64, opts UploadOptions) (*Video, error) {
+	return yt.UploadVideoContext(context.Background(), r, size, opts)
+}
+
+// UploadVideoContext is like UploadVideo but uses ctx for every request of the upload.
+func (yt *YoutubeApi) UploadVideoContext(ctx context.Context, r io.ReaderAt, size int64, opts UploadOptions) (*Video, error) {
+	session, err := yt.StartUploadContext(ctx, size, opts)
+	if err != nil {
+		return nil, err
+	}
+	return yt.ResumeUploadContext(ctx, session, r, opts)
+}
+
+// StartUpload opens a resumable upload session for a file of the given size and metadata.
+func (yt *YoutubeApi) StartUpload(size int64, opts UploadOptions) (*UploadSession, error) {
+	return yt.StartUploadContext(context.Background(), size, opts)
+}
+
+// StartUploadContext is like StartUpload but uses ctx for the underlying API request.
+func (yt *YoutubeApi) StartUploadContext(ctx context.Context, size int64, opts UploadOptions) (*UploadSession, error) {
+	if opts.PrivacyStatus == "" {
+		opts.PrivacyStatus = PrivacyPrivate
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = "video/*"
+	}
+	metadata, err := json.Marshal(map[string]interface{}{
+		"snippet": map[string]interface{}{
+			"title":       opts.Title,
+			"description": opts.Description,
+			"tags":        opts.Tags,
+			"categoryId":  opts.CategoryId,
+		},
+		"status": map[string]interface{}{
+			"privacyStatus":           opts.PrivacyStatus,
+			"selfDeclaredMadeForKids": opts.MadeForKids,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal upload metadata: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(UploadVideo, yt.apiKey), bytes.NewReader(metadata))
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request, error: %w", redactError(err))
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+	req.Header.Set("X-Upload-Content-Type", opts.ContentType)
+	resp, body, err := yt.doUploadRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, uploadStatusError(resp, body)
+	}
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, errors.New("upload session response is missing the Location header")
+	}
+	return &UploadSession{URI: location, Size: size}, nil
+}
+
+// ResumeUpload sends the remaining bytes of an upload session, starting from the offset YouTube
+// has already confirmed. Transient failures are retried according to the client's RetryPolicy;
+// if they persist the session can be resumed again later.
+func (yt *YoutubeApi) ResumeUpload(session *UploadSession, r io.ReaderAt, opts UploadOptions) (*Video, error) {
+	return yt.ResumeUploadContext(context.Background(), session, r, opts)
+}
+
+// ResumeUploadContext is like ResumeUpload but uses ctx for every chunk request.
+func (yt *YoutubeApi) ResumeUploadContext(ctx context.Context, session *UploadSession, r io.ReaderAt, opts UploadOptions) (*Video, error) {
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultUploadChunkSize
+	}
+	if chunkSize = chunkSize / uploadChunkAlignment * uploadChunkAlignment; chunkSize == 0 {
+		chunkSize = uploadChunkAlignment
+	}
+
+	offset, video, err := yt.uploadOffset(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 0; video == nil; {
+		end := offset + chunkSize
+		if end > session.Size {
+			end = session.Size
+		}
+		var next int64
+		next, video, err = yt.putUploadChunk(ctx, session, r, offset, end)
+		if err != nil {
+			attempt++
+			var statusErr *uploadError
+			transient := !errors.As(err, &statusErr) || isRetryableStatus(statusErr.status)
+			if attempt >= yt.retry.MaxAttempts || ctx.Err() != nil || !transient {
+				return nil, err
+			}
+			delay := yt.retry.backoff(attempt - 1)
+			yt.logger.Warn("resuming upload", Field{"delay", delay}, Field{"attempt", attempt}, Field{"error", err})
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, err
+			}
+			if offset, video, err = yt.uploadOffset(ctx, session); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		attempt = 0
+		offset = next
+		if opts.Progress != nil && video == nil {
+			opts.Progress(offset, session.Size)
+		}
+	}
+	if opts.Progress != nil {
+		opts.Progress(session.Size, session.Size)
+	}
+	return video, nil
+}
+
+// uploadOffset asks YouTube how many bytes of the session it has received.
+// It returns the finished video instead when the upload already completed.
+func (yt *YoutubeApi) uploadOffset(ctx context.Context, session *UploadSession) (int64, *Video, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session.URI, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed creating request, error: %w", redactError(err))
+	}
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", session.Size))
+	resp, body, err := yt.doUploadRequest(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	return parseUploadResponse(resp, body)
+}
+
+// putUploadChunk sends bytes [start, end) of the file and returns the next offset to send.
+func (yt *YoutubeApi) putUploadChunk(ctx context.Context, session *UploadSession, r io.ReaderAt, start, end int64) (int64, *Video, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session.URI, io.NewSectionReader(r, start, end-start))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed creating request, error: %w", redactError(err))
+	}
+	req.ContentLength = end - start
+	if end > start {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, session.Size))
+	} else {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", session.Size))
+	}
+	resp, body, err := yt.doUploadRequest(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	return parseUploadResponse(resp, body)
+}
+
+// doUploadRequest authenticates and sends an upload request, returning the read response body.
+func (yt *YoutubeApi) doUploadRequest(req *http.Request) (resp *http.Response, _ []byte, err error) {
+	yt.setEncodingHeaders(req)
+	if err := yt.authorize(req, authUser); err != nil {
+		return nil, nil, err
+	}
+	if err := yt.waitRateLimit(req.Context(), req.URL); err != nil {
+		return nil, nil, err
+	}
+	if yt.observer != nil {
+		start := time.Now()
+		defer func() {
+			info := RequestInfo{Endpoint: endpointName(req.URL), Method: req.Method, Duration: time.Since(start), Err: err}
+			if resp != nil {
+				info.Status = resp.StatusCode
+			}
+			yt.observeRequest(info)
+		}()
+	}
+	resp, err = yt.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed HTTP request, error: %w", redactError(err))
+	}
+	defer resp.Body.Close()
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed reading body, error: %w", err)
+	}
+	return resp, body, nil
+}
+
+// parseUploadResponse interprets a chunk or status response: 308 carries the confirmed
+// byte range, 200/201 carries the created video.
+func parseUploadResponse(resp *http.Response, body []byte) (int64, *Video, error) {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		video := &Video{}
+		if err := json.Unmarshal(body, video); err != nil {
+			return 0, nil, fmt.Errorf("failed to unmarshal uploaded video: %w", err)
+		}
+		return 0, video, nil
+	case http.StatusPermanentRedirect:
+		// Range is "bytes=0-<last received byte>"; it is absent when nothing was received yet.
+		rng := resp.Header.Get("Range")
+		if rng == "" {
+			return 0, nil, nil
+		}
+		last, err := strconv.ParseInt(rng[strings.LastIndex(rng, "-")+1:], 10, 64)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid Range header %q: %w", rng, err)
+		}
+		return last + 1, nil, nil
+	}
+	return 0, nil, uploadStatusError(resp, body)
+}
+
+// uploadError reports an unexpected status during an upload, wrapping the parsed ApiError if any.
+type uploadError struct {
+	status int
+	err    error
+}
+
+func (e *uploadError) Error() string {
+	return fmt.Sprintf("upload failed with HTTP status %d: %v", e.status, e.err)
+}
+
+func (e *uploadError) Unwrap() error {
+	return e.err
+}
+
+func uploadStatusError(resp *http.Response, body []byte) error {
+	return &uploadError{status: resp.StatusCode, err: statusError(resp.StatusCode, body)}
+}
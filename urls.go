@@ -0,0 +1,143 @@
+package alaitube
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidURL is returned when a string is not a recognizable YouTube URL or ID.
+var ErrInvalidURL = errors.New("not a recognizable youtube url")
+
+var videoIdPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+var channelIdPattern = regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`)
+
+// URLInfo holds the identifiers found in a YouTube URL. Fields that don't apply are empty;
+// a watch URL inside a playlist, for example, sets both VideoId and PlaylistId.
+type URLInfo struct {
+	VideoId    string
+	PlaylistId string
+	ChannelId  string
+	// Handle is a channel handle without its leading "@".
+	Handle string
+	// Username is a legacy /user/ name.
+	Username string
+	// CustomName is a legacy /c/ custom URL name.
+	CustomName string
+}
+
+// IsVideoId reports whether s has the shape of a YouTube video ID.
+func IsVideoId(s string) bool {
+	return videoIdPattern.MatchString(s)
+}
+
+// ParseURL extracts video, playlist, and channel identifiers from watch, youtu.be, shorts,
+// embed, live, playlist, and channel URLs. The scheme may be omitted, and a bare video ID
+// is accepted as well.
+func ParseURL(raw string) (*URLInfo, error) {
+	raw = strings.TrimSpace(raw)
+	if IsVideoId(raw) {
+		return &URLInfo{VideoId: raw}, nil
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, ErrInvalidURL
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+	query := u.Query()
+	info := &URLInfo{PlaylistId: query.Get("list")}
+
+	switch host {
+	case "youtu.be":
+		if len(segments) > 0 {
+			info.VideoId = segments[0]
+		}
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		if len(segments) == 0 {
+			break
+		}
+		switch first := segments[0]; {
+		case first == "watch":
+			info.VideoId = query.Get("v")
+		case first == "shorts" || first == "embed" || first == "v" || first == "e" || first == "live":
+			if len(segments) > 1 {
+				info.VideoId = segments[1]
+			}
+		case first == "channel" && len(segments) > 1:
+			info.ChannelId = segments[1]
+		case first == "user" && len(segments) > 1:
+			info.Username = segments[1]
+		case first == "c" && len(segments) > 1:
+			info.CustomName = segments[1]
+		case strings.HasPrefix(first, "@") && len(first) > 1:
+			info.Handle = first[1:]
+		}
+	default:
+		return nil, ErrInvalidURL
+	}
+
+	if info.VideoId != "" && !IsVideoId(info.VideoId) {
+		return nil, ErrInvalidURL
+	}
+	if info.ChannelId != "" && !channelIdPattern.MatchString(info.ChannelId) {
+		return nil, ErrInvalidURL
+	}
+	if *info == (URLInfo{}) {
+		return nil, ErrInvalidURL
+	}
+	return info, nil
+}
+
+// ParseVideoURL returns the video ID referenced by a YouTube URL or bare ID.
+func ParseVideoURL(raw string) (string, error) {
+	info, err := ParseURL(raw)
+	if err != nil {
+		return "", err
+	}
+	if info.VideoId == "" {
+		return "", ErrInvalidURL
+	}
+	return info.VideoId, nil
+}
+
+// VideoIdsFromURLs extracts the video IDs of user-pasted links, ready to pass to GetVideos.
+// Duplicates are dropped and the inputs that could not be parsed are returned separately.
+func VideoIdsFromURLs(inputs []string) (ids []string, invalid []string) {
+	seen := make(map[string]bool)
+	for _, in := range inputs {
+		id, err := ParseVideoURL(in)
+		if err != nil {
+			invalid = append(invalid, in)
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, invalid
+}
+
+// Canonical URLs of YouTube pages.
+const (
+	WatchUrl   = "https://www.youtube.com/watch?v=%s"
+	ChannelUrl = "https://www.youtube.com/channel/%s"
+)
+
+// Url returns the watch page URL of the video.
+func (v *Video) Url() string {
+	return fmt.Sprintf(WatchUrl, url.QueryEscape(v.Id))
+}
+
+// Url returns the page URL of the channel.
+func (item *Item) Url() string {
+	return fmt.Sprintf(ChannelUrl, url.PathEscape(item.Id))
+}
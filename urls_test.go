@@ -0,0 +1,75 @@
+package alaitube_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/josephalai/alaitube"
+)
+
+func TestParseURL(t *testing.T) {
+	const (
+		videoId    = "dQw4w9WgXcQ"
+		channelId  = "UC_x5XG1OV2P6uZZ5FSM9Ttw"
+		playlistId = "PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf"
+	)
+	tests := []struct {
+		in   string
+		want alaitube.URLInfo
+	}{
+		{in: videoId, want: alaitube.URLInfo{VideoId: videoId}},
+		{in: "https://www.youtube.com/watch?v=" + videoId, want: alaitube.URLInfo{VideoId: videoId}},
+		{in: "youtube.com/watch?v=" + videoId + "&t=42s", want: alaitube.URLInfo{VideoId: videoId}},
+		{in: "https://m.youtube.com/watch?v=" + videoId, want: alaitube.URLInfo{VideoId: videoId}},
+		{in: "https://www.youtube.com/watch?v=" + videoId + "&list=" + playlistId, want: alaitube.URLInfo{VideoId: videoId, PlaylistId: playlistId}},
+		{in: "https://youtu.be/" + videoId, want: alaitube.URLInfo{VideoId: videoId}},
+		{in: "  https://youtu.be/" + videoId + "?si=abc  ", want: alaitube.URLInfo{VideoId: videoId}},
+		{in: "https://www.youtube.com/shorts/" + videoId, want: alaitube.URLInfo{VideoId: videoId}},
+		{in: "https://www.youtube.com/embed/" + videoId, want: alaitube.URLInfo{VideoId: videoId}},
+		{in: "https://www.youtube-nocookie.com/embed/" + videoId, want: alaitube.URLInfo{VideoId: videoId}},
+		{in: "https://www.youtube.com/live/" + videoId, want: alaitube.URLInfo{VideoId: videoId}},
+		{in: "https://www.youtube.com/channel/" + channelId, want: alaitube.URLInfo{ChannelId: channelId}},
+		{in: "https://www.youtube.com/channel/" + channelId + "/videos", want: alaitube.URLInfo{ChannelId: channelId}},
+		{in: "https://www.youtube.com/@GoogleDevelopers", want: alaitube.URLInfo{Handle: "GoogleDevelopers"}},
+		{in: "https://www.youtube.com/@GoogleDevelopers/shorts", want: alaitube.URLInfo{Handle: "GoogleDevelopers"}},
+		{in: "https://www.youtube.com/user/GoogleDevelopers", want: alaitube.URLInfo{Username: "GoogleDevelopers"}},
+		{in: "https://www.youtube.com/c/GoogleDevelopers", want: alaitube.URLInfo{CustomName: "GoogleDevelopers"}},
+		{in: "https://www.youtube.com/playlist?list=" + playlistId, want: alaitube.URLInfo{PlaylistId: playlistId}},
+		{in: "https://music.youtube.com/playlist?list=" + playlistId, want: alaitube.URLInfo{PlaylistId: playlistId}},
+	}
+	for _, tt := range tests {
+		got, err := alaitube.ParseURL(tt.in)
+		if err != nil {
+			t.Errorf("ParseURL(%q): %v", tt.in, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("ParseURL(%q) = %+v, want %+v", tt.in, *got, tt.want)
+		}
+	}
+}
+
+func TestParseURLInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"dQw4w9WgXc",
+		"https://vimeo.com/123456",
+		"https://www.youtube.com/",
+		"https://www.youtube.com/watch",
+		"https://www.youtube.com/watch?v=short",
+		"https://youtu.be/",
+		"https://youtu.be/dQw4w9WgXcQextra",
+		"https://www.youtube.com/shorts/",
+		"https://www.youtube.com/embed/not-an-id",
+		"https://www.youtube.com/channel/UCtooshort",
+		"https://www.youtube.com/channel/",
+		"https://www.youtube.com/@",
+		"https://www.youtube.com/feed/trending",
+		"https://www.youtube.com/results?search_query=cats",
+		"http://[::1",
+	} {
+		if info, err := alaitube.ParseURL(in); !errors.Is(err, alaitube.ErrInvalidURL) {
+			t.Errorf("ParseURL(%q) = %+v, %v, want ErrInvalidURL", in, info, err)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Package vcr provides an http.RoundTripper that records YouTube API responses to fixture files
+// and replays them, so code using alaitube can be tested offline without spending quota.
+//
+//	rec, _ := vcr.New("testdata/fixtures", vcr.ModeReplay)
+//	client := alaitube.NewClient(alaitube.WithApiKey(key), alaitube.WithHttpClient(rec.Client()))
+//
+// Fixtures are matched on the method, URL, and request body. Credentials (the key and
+// access_token query parameters, and the Authorization header) are never written to disk
+// and are ignored when matching, so fixtures recorded with one key replay with any other.
+// Compressed responses are recorded decompressed, keeping fixtures readable and editable.
+package vcr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Mode selects whether a Recorder talks to the real API.
+type Mode int
+
+const (
+	// ModeReplay serves every request from fixtures and fails when one is missing.
+	ModeReplay Mode = iota
+	// ModeRecord sends every request to the real API and overwrites its fixture.
+	ModeRecord
+	// ModeReplayOrRecord serves existing fixtures and records the missing ones.
+	ModeReplayOrRecord
+)
+
+// ErrFixtureNotFound is returned in ModeReplay when a request has no recorded fixture.
+var ErrFixtureNotFound = errors.New("vcr fixture not found")
+
+// credentialParams are the query parameters stripped from recorded and matched URLs.
+var credentialParams = []string{"key", "access_token"}
+
+// Recorder is an http.RoundTripper recording to and replaying from a fixture directory.
+type Recorder struct {
+	dir       string
+	mode      Mode
+	transport http.RoundTripper
+}
+
+// Option configures a Recorder created with New.
+type Option func(*Recorder)
+
+// WithTransport sets the transport used to reach the real API when recording.
+// A nil transport is ignored and http.DefaultTransport is kept.
+func WithTransport(t http.RoundTripper) Option {
+	return func(r *Recorder) {
+		if t != nil {
+			r.transport = t
+		}
+	}
+}
+
+// New creates a Recorder storing fixtures in dir, which is created when recording.
+func New(dir string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{dir: dir, mode: mode, transport: http.DefaultTransport}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if mode != ModeReplay {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed creating fixture directory: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// Client returns an http.Client using the Recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// fixture is the on-disk form of a recorded exchange.
+type fixture struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Body   string `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status  int         `json:"status"`
+		Headers http.Header `json:"headers,omitempty"`
+		Body    string      `json:"body"`
+	} `json:"response"`
+}
+
+// RoundTrip serves req from its fixture or the real API, depending on the mode.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("failed reading request body: %w", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	cleanUrl := stripCredentials(req.URL)
+	path := filepath.Join(r.dir, fixtureName(req.Method, cleanUrl, reqBody))
+
+	if r.mode != ModeRecord {
+		f, err := readFixture(path)
+		if err == nil {
+			return f.response(req), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if r.mode == ModeReplay {
+			return nil, fmt.Errorf("%w: %s %s", ErrFixtureNotFound, req.Method, cleanUrl)
+		}
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading response body: %w", err)
+	}
+
+	header := resp.Header.Clone()
+	if strings.EqualFold(header.Get("Content-Encoding"), "gzip") {
+		if respBody, err = gunzip(respBody); err != nil {
+			return nil, fmt.Errorf("failed decompressing response body: %w", err)
+		}
+		// The replayed body is plain, so it mustn't be announced as compressed.
+		header.Del("Content-Encoding")
+		header.Del("Content-Length")
+	}
+
+	f := &fixture{}
+	f.Request.Method = req.Method
+	f.Request.URL = cleanUrl
+	f.Request.Body = string(reqBody)
+	f.Response.Status = resp.StatusCode
+	f.Response.Headers = header
+	f.Response.Body = string(respBody)
+	if err := writeFixture(path, f); err != nil {
+		return nil, err
+	}
+	return f.response(req), nil
+}
+
+// gunzip returns the decompressed content of a gzip stream.
+func gunzip(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// response rebuilds the recorded http.Response for req.
+func (f *fixture) response(req *http.Request) *http.Response {
+	header := f.Response.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode:    f.Response.Status,
+		Status:        fmt.Sprintf("%d %s", f.Response.Status, http.StatusText(f.Response.Status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(f.Response.Body)),
+		ContentLength: int64(len(f.Response.Body)),
+		Request:       req,
+	}
+}
+
+// stripCredentials returns u without credential query parameters, with the remaining
+// parameters sorted so equivalent URLs match the same fixture.
+func stripCredentials(u *url.URL) string {
+	clean := *u
+	q := clean.Query()
+	for _, p := range credentialParams {
+		q.Del(p)
+	}
+	clean.RawQuery = q.Encode()
+	return clean.String()
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// fixtureName names the fixture of a request after its endpoint plus a hash of the full request,
+// keeping the directory readable while avoiding collisions.
+func fixtureName(method, cleanUrl string, body []byte) string {
+	sum := sha1.New()
+	io.WriteString(sum, method+" "+cleanUrl+"\n")
+	sum.Write(body)
+	hash := hex.EncodeToString(sum.Sum(nil))[:12]
+
+	endpoint := "request"
+	if u, err := url.Parse(cleanUrl); err == nil {
+		if base := filepath.Base(u.Path); base != "." && base != "/" {
+			endpoint = unsafeChars.ReplaceAllString(base, "_")
+		}
+	}
+	return strings.ToLower(method) + "_" + endpoint + "_" + hash + ".json"
+}
+
+func readFixture(path string) (*fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &fixture{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fixture %s: %w", path, err)
+	}
+	return f, nil
+}
+
+func writeFixture(path string, f *fixture) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed writing fixture: %w", err)
+	}
+	return nil
+}
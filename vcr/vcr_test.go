@@ -0,0 +1,144 @@
+package vcr_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/josephalai/alaitube"
+	"github.com/josephalai/alaitube/testutil"
+	"github.com/josephalai/alaitube/vcr"
+)
+
+// gzipTransport compresses the responses of next when the request accepts gzip, like the real API.
+type gzipTransport struct {
+	next http.RoundTripper
+}
+
+func (t gzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !strings.Contains(req.Header.Get("Accept-Encoding"), "gzip") {
+		return resp, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(body)
+	zw.Close()
+	resp.Body = io.NopCloser(&buf)
+	resp.ContentLength = int64(buf.Len())
+	resp.Header.Set("Content-Encoding", "gzip")
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// searchVideo returns a video titled title with enough views to be kept by FindTags.
+func searchVideo(id, title string) *alaitube.Video {
+	var v alaitube.Video
+	data := fmt.Sprintf(`{"id":%q,"snippet":{"title":%q},"statistics":{"viewCount":"5000"}}`, id, title)
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		panic(err)
+	}
+	return &v
+}
+
+func resultIds(results *alaitube.VideoResults) []string {
+	var ids []string
+	for _, v := range results.Items {
+		ids = append(ids, v.Id)
+	}
+	return ids
+}
+
+func TestRecordReplay(t *testing.T) {
+	tests := []struct {
+		name       string
+		compressed bool
+		numPages   int
+		wantIds    []string
+	}{
+		{name: "plain single page", numPages: 1, wantIds: []string{"cat0", "cat1"}},
+		{name: "gzip single page", compressed: true, numPages: 1, wantIds: []string{"cat0", "cat1"}},
+		{name: "gzip pagination", compressed: true, numPages: 3, wantIds: []string{"cat0", "cat1", "cat2", "cat3", "cat4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			srv := testutil.NewServer()
+			srv.SetPageSize(2)
+			for i := 0; i < 5; i++ {
+				srv.AddVideo(searchVideo(fmt.Sprintf("cat%d", i), fmt.Sprintf("cats %d", i)))
+			}
+			transport := srv.Client().Transport
+			if tt.compressed {
+				transport = gzipTransport{next: transport}
+			}
+
+			rec, err := vcr.New(dir, vcr.ModeRecord, vcr.WithTransport(transport))
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			yt := alaitube.NewClient(alaitube.WithApiKey("secret-key"), alaitube.WithHttpClient(rec.Client()))
+			recorded, err := yt.FindTags("cats", tt.numPages)
+			if err != nil {
+				t.Fatalf("recording FindTags: %v", err)
+			}
+			if got := resultIds(recorded); !reflect.DeepEqual(got, tt.wantIds) {
+				t.Errorf("recorded videos = %v, want %v", got, tt.wantIds)
+			}
+			srv.Close()
+
+			fixtures, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+			for _, path := range fixtures {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if bytes.Contains(data, []byte("secret-key")) {
+					t.Errorf("fixture %s contains the API key", filepath.Base(path))
+				}
+				if bytes.Contains(data, []byte("Content-Encoding")) {
+					t.Errorf("fixture %s records a Content-Encoding header", filepath.Base(path))
+				}
+			}
+
+			replay, err := vcr.New(dir, vcr.ModeReplay)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			yt = alaitube.NewClient(alaitube.WithApiKey("other-key"), alaitube.WithHttpClient(replay.Client()))
+			replayed, err := yt.FindTags("cats", tt.numPages)
+			if err != nil {
+				t.Fatalf("replaying FindTags: %v", err)
+			}
+			if got := resultIds(replayed); !reflect.DeepEqual(got, tt.wantIds) {
+				t.Errorf("replayed videos = %v, want %v", got, tt.wantIds)
+			}
+		})
+	}
+}
+
+func TestReplayMissingFixture(t *testing.T) {
+	replay, err := vcr.New(t.TempDir(), vcr.ModeReplay)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	_, err = replay.Client().Get("https://www.googleapis.com/youtube/v3/videos?id=a&key=k")
+	if !errors.Is(err, vcr.ErrFixtureNotFound) {
+		t.Errorf("err = %v, want ErrFixtureNotFound", err)
+	}
+}
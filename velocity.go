@@ -0,0 +1,133 @@
+package alaitube
+
+import "sort"
+
+// DefaultBreakoutRatio is how many times faster than its baseline a video must gain views to be
+// flagged by Breakouts when no ratio is given.
+const DefaultBreakoutRatio = 3
+
+// minBaselineVideos is the number of videos a channel or category needs for its own baseline.
+// Smaller groups are compared with the baseline of the whole results instead.
+const minBaselineVideos = 3
+
+// VelocityBaseline selects the videos a video's velocity is compared with.
+type VelocityBaseline string
+
+const (
+	// BaselineResults compares every video with all the results.
+	BaselineResults VelocityBaseline = "results"
+	// BaselineChannel compares every video with the other results of its channel.
+	BaselineChannel VelocityBaseline = "channel"
+	// BaselineCategory compares every video with the other results of its category.
+	BaselineCategory VelocityBaseline = "category"
+)
+
+// VelocityScore is the velocity of a video relative to its baseline.
+type VelocityScore struct {
+	Video        *Video  `bson:"video" json:"video"`
+	ViewsPerHour float64 `bson:"viewsPerHour" json:"viewsPerHour"`
+	// Group is the channel or category ID the baseline was computed over, or "" for the whole results.
+	Group string `bson:"group,omitempty" json:"group,omitempty"`
+	// Baseline is the median views per hour of the group.
+	Baseline float64 `bson:"baseline" json:"baseline"`
+	// Ratio is ViewsPerHour divided by Baseline, or zero when the baseline is zero.
+	Ratio float64 `bson:"ratio" json:"ratio"`
+}
+
+// ViewsPerHour returns the average hourly views since the video was published. Videos younger
+// than an hour are treated as one hour old so fresh uploads aren't inflated.
+func (v *Video) ViewsPerHour() float64 {
+	if v.Statistics == nil || v.PublishedAt().IsZero() {
+		return 0
+	}
+	hours := v.Age().Hours()
+	if hours < 1 {
+		hours = 1
+	}
+	return float64(v.Statistics.ViewCount) / hours
+}
+
+// VelocityScores scores every video with a known publish time and view count by how much faster it
+// gains views than the median video of its baseline group, highest ratio first. Channels and
+// categories with fewer than three videos in the results fall back to the baseline of the whole
+// results. Videos without a category or channel are compared with the whole results as well.
+func (r *VideoResults) VelocityScores(by VelocityBaseline) []VelocityScore {
+	var scores []VelocityScore
+	groups := make(map[string][]float64)
+	var all []float64
+	for _, v := range r.Items {
+		if v.Statistics == nil || v.PublishedAt().IsZero() {
+			continue
+		}
+		score := VelocityScore{Video: v, ViewsPerHour: v.ViewsPerHour(), Group: velocityGroup(v, by)}
+		scores = append(scores, score)
+		all = append(all, score.ViewsPerHour)
+		if score.Group != "" {
+			groups[score.Group] = append(groups[score.Group], score.ViewsPerHour)
+		}
+	}
+
+	overall := median(all)
+	baselines := make(map[string]float64, len(groups))
+	for group, velocities := range groups {
+		if len(velocities) >= minBaselineVideos {
+			baselines[group] = median(velocities)
+		}
+	}
+	for i := range scores {
+		baseline, ok := baselines[scores[i].Group]
+		if !ok {
+			scores[i].Group, baseline = "", overall
+		}
+		scores[i].Baseline = baseline
+		if baseline > 0 {
+			scores[i].Ratio = scores[i].ViewsPerHour / baseline
+		}
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Ratio > scores[j].Ratio })
+	return scores
+}
+
+// Breakouts returns the videos gaining views at least ratio times faster than their baseline,
+// highest ratio first. A ratio of zero or less uses DefaultBreakoutRatio. Combined with FindTags,
+// it surfaces the breakout videos of a niche without extra requests.
+func (r *VideoResults) Breakouts(by VelocityBaseline, ratio float64) []VelocityScore {
+	if ratio <= 0 {
+		ratio = DefaultBreakoutRatio
+	}
+	var breakouts []VelocityScore
+	for _, score := range r.VelocityScores(by) {
+		if score.Ratio >= ratio {
+			breakouts = append(breakouts, score)
+		}
+	}
+	return breakouts
+}
+
+// velocityGroup returns the channel or category ID of v the baseline is computed over.
+func velocityGroup(v *Video, by VelocityBaseline) string {
+	if v.Snippet == nil {
+		return ""
+	}
+	switch by {
+	case BaselineChannel:
+		return v.Snippet.ChannelId
+	case BaselineCategory:
+		return v.Snippet.CategoryId
+	}
+	return ""
+}
+
+// median returns the median of values, or zero when there are none.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
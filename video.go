@@ -0,0 +1,283 @@
+package alaitube
+
+import (
+	"strings"
+	"time"
+)
+
+// PublishedAt returns the time the video was published, or the zero time when the snippet is missing.
+func (v *Video) PublishedAt() time.Time {
+	if v.Snippet == nil {
+		return time.Time{}
+	}
+	return v.Snippet.PublishedAt
+}
+
+// Age returns how long ago the video was published, or zero when the publish time is unknown.
+func (v *Video) Age() time.Duration {
+	published := v.PublishedAt()
+	if published.IsZero() {
+		return 0
+	}
+	return time.Since(published)
+}
+
+// PublishedBetween reports whether the video was published within [after, before).
+// A zero after or before leaves that side of the range open.
+func (v *Video) PublishedBetween(after, before time.Time) bool {
+	published := v.PublishedAt()
+	if published.IsZero() {
+		return false
+	}
+	if !after.IsZero() && published.Before(after) {
+		return false
+	}
+	if !before.IsZero() && !published.Before(before) {
+		return false
+	}
+	return true
+}
+
+// FilterPublished returns a copy of the results holding only the videos published within [after, before).
+// A zero after or before leaves that side of the range open.
+func (r *VideoResults) FilterPublished(after, before time.Time) *VideoResults {
+	return r.Filter(func(v *Video) bool {
+		return v.PublishedBetween(after, before)
+	})
+}
+
+// shallowCopy returns a copy of the results with their own Items slice, handed to callers so
+// that replacing their videos doesn't change the results held by the cache. The videos are shared.
+func (r *VideoResults) shallowCopy() *VideoResults {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.Items = append([]*Video(nil), r.Items...)
+	return &c
+}
+
+// Filter returns a copy of the results holding only the videos for which keep returns true.
+func (r *VideoResults) Filter(keep func(*Video) bool) *VideoResults {
+	filtered := &VideoResults{NextPageToken: r.NextPageToken}
+	for _, v := range r.Items {
+		if keep(v) {
+			filtered.Items = append(filtered.Items, v)
+		}
+	}
+	return filtered
+}
+
+// Duration returns the length of the video, or zero when contentDetails was not fetched.
+func (v *Video) Duration() time.Duration {
+	if v.ContentDetails == nil {
+		return 0
+	}
+	return v.ContentDetails.Duration.Std()
+}
+
+// DurationSeconds returns the length of the video in whole seconds.
+func (v *Video) DurationSeconds() int64 {
+	return int64(v.Duration() / time.Second)
+}
+
+// IsHD reports whether the video is available in high definition.
+func (v *Video) IsHD() bool {
+	return v.ContentDetails != nil && v.ContentDetails.Definition == "hd"
+}
+
+// HasCaptions reports whether the uploader provided captions for the video.
+func (v *Video) HasCaptions() bool {
+	return v.ContentDetails != nil && v.ContentDetails.Caption == "true"
+}
+
+// IsPublic reports whether the video is public. It is false when the status part was not fetched.
+func (v *Video) IsPublic() bool {
+	return v.Status != nil && v.Status.PrivacyStatus == PrivacyPublic
+}
+
+// IsEmbeddable reports whether the video can be embedded on other websites.
+// It is false when the status part was not fetched.
+func (v *Video) IsEmbeddable() bool {
+	return v.Status != nil && v.Status.Embeddable
+}
+
+// IsMadeForKids reports whether the video is designated as child-directed.
+// It is false when the status part was not fetched.
+func (v *Video) IsMadeForKids() bool {
+	return v.Status != nil && v.Status.MadeForKids
+}
+
+// IsAgeRestricted reports whether YouTube restricts the video to signed-in adults.
+// It is false when contentDetails was not fetched.
+func (v *Video) IsAgeRestricted() bool {
+	return v.ContentDetails != nil && v.ContentDetails.ContentRating != nil && v.ContentDetails.ContentRating.YtRating == "ytAgeRestricted"
+}
+
+// AvailableIn reports whether the video can be watched in region, an ISO 3166-1 alpha-2 code
+// such as "US". Videos without region restrictions, or whose contentDetails was not fetched,
+// are assumed available.
+func (v *Video) AvailableIn(region string) bool {
+	if v.ContentDetails == nil || v.ContentDetails.RegionRestriction == nil {
+		return true
+	}
+	restriction := v.ContentDetails.RegionRestriction
+	if restriction.Allowed != nil {
+		return containsFold(restriction.Allowed, region)
+	}
+	return !containsFold(restriction.Blocked, region)
+}
+
+// containsFold reports whether list holds s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterAvailableIn returns a copy of the results without the videos that are age-restricted or
+// can't be watched in region.
+func (r *VideoResults) FilterAvailableIn(region string) *VideoResults {
+	return r.Filter(func(v *Video) bool {
+		return !v.IsAgeRestricted() && v.AvailableIn(region)
+	})
+}
+
+// FilterUsable returns a copy of the results without the videos that can't be embedded or are made for kids.
+func (r *VideoResults) FilterUsable() *VideoResults {
+	return r.Filter(func(v *Video) bool {
+		return v.IsEmbeddable() && !v.IsMadeForKids()
+	})
+}
+
+// BroadcastKind classifies a video as a live stream, a premiere, an upcoming stream, or a regular upload.
+type BroadcastKind string
+
+const (
+	BroadcastLive     BroadcastKind = "live"
+	BroadcastPremiere BroadcastKind = "premiere"
+	BroadcastUpcoming BroadcastKind = "upcoming"
+	BroadcastVOD      BroadcastKind = "vod"
+)
+
+// IsLive reports whether the video is currently being broadcast live.
+func (v *Video) IsLive() bool {
+	if v.Snippet != nil && v.Snippet.LiveBroadcastContent == "live" {
+		return true
+	}
+	d := v.LiveStreamingDetails
+	return d != nil && !d.ActualStartTime.IsZero() && d.ActualEndTime.IsZero()
+}
+
+// IsUpcoming reports whether the video is a scheduled live stream or premiere that has not started yet.
+func (v *Video) IsUpcoming() bool {
+	return v.Snippet != nil && v.Snippet.LiveBroadcastContent == "upcoming"
+}
+
+// ScheduledStartTime returns when a live stream or premiere is scheduled to start,
+// or the zero time for regular uploads.
+func (v *Video) ScheduledStartTime() time.Time {
+	if v.LiveStreamingDetails == nil {
+		return time.Time{}
+	}
+	return v.LiveStreamingDetails.ScheduledStartTime
+}
+
+// BroadcastKind classifies the video. Premieres are told apart from live streams by
+// already having a duration, since their content was uploaded ahead of time.
+func (v *Video) BroadcastKind() BroadcastKind {
+	switch {
+	case !v.IsLive() && !v.IsUpcoming():
+		return BroadcastVOD
+	case v.Duration() > 0:
+		return BroadcastPremiere
+	case v.IsUpcoming():
+		return BroadcastUpcoming
+	}
+	return BroadcastLive
+}
+
+// PartitionBroadcasts groups the videos by BroadcastKind, keeping their order within each group.
+func (r *VideoResults) PartitionBroadcasts() map[BroadcastKind][]*Video {
+	groups := make(map[BroadcastKind][]*Video)
+	for _, v := range r.Items {
+		kind := v.BroadcastKind()
+		groups[kind] = append(groups[kind], v)
+	}
+	return groups
+}
+
+// VideoMetrics holds the engagement metrics computed from a video's statistics.
+type VideoMetrics struct {
+	EngagementRate float64 `bson:"engagementRate" json:"engagementRate"`
+	LikeRatio      float64 `bson:"likeRatio" json:"likeRatio"`
+	CommentRatio   float64 `bson:"commentRatio" json:"commentRatio"`
+	ViewsPerDay    float64 `bson:"viewsPerDay" json:"viewsPerDay"`
+}
+
+// perView returns n divided by the video's views, or zero when the views are unknown.
+func (v *Video) perView(n Count) float64 {
+	if v.Statistics == nil || v.Statistics.ViewCount <= 0 {
+		return 0
+	}
+	return float64(n) / float64(v.Statistics.ViewCount)
+}
+
+// EngagementRate returns the likes and comments of the video per view.
+func (v *Video) EngagementRate() float64 {
+	if v.Statistics == nil {
+		return 0
+	}
+	return v.perView(v.Statistics.LikeCount + v.Statistics.CommentCount)
+}
+
+// LikeRatio returns the likes of the video per view.
+func (v *Video) LikeRatio() float64 {
+	if v.Statistics == nil {
+		return 0
+	}
+	return v.perView(v.Statistics.LikeCount)
+}
+
+// CommentRatio returns the comments of the video per view.
+func (v *Video) CommentRatio() float64 {
+	if v.Statistics == nil {
+		return 0
+	}
+	return v.perView(v.Statistics.CommentCount)
+}
+
+// ViewsPerDay returns the average daily views since the video was published.
+// Videos younger than a day are treated as one day old so fresh uploads aren't inflated.
+func (v *Video) ViewsPerDay() float64 {
+	if v.Statistics == nil || v.PublishedAt().IsZero() {
+		return 0
+	}
+	days := v.Age().Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+	return float64(v.Statistics.ViewCount) / days
+}
+
+// Metrics computes every engagement metric of the video.
+func (v *Video) Metrics() VideoMetrics {
+	return VideoMetrics{
+		EngagementRate: v.EngagementRate(),
+		LikeRatio:      v.LikeRatio(),
+		CommentRatio:   v.CommentRatio(),
+		ViewsPerDay:    v.ViewsPerDay(),
+	}
+}
+
+// Metrics computes the engagement metrics of every video, keyed by video ID.
+func (r *VideoResults) Metrics() map[string]VideoMetrics {
+	metrics := make(map[string]VideoMetrics, len(r.Items))
+	for _, v := range r.Items {
+		metrics[v.Id] = v.Metrics()
+	}
+	return metrics
+}
@@ -0,0 +1,328 @@
+package alaitube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval is how often a Watcher polls when no interval is configured.
+const DefaultWatchInterval = 15 * time.Minute
+
+// DefaultWatchJitter is the fraction of the interval a Watcher randomly shifts each poll by,
+// so many watchers started together don't hit the API in lockstep.
+const DefaultWatchJitter = 0.1
+
+// DefaultWatchMaxPages bounds how many upload pages a single poll walks looking for the high-water mark.
+const DefaultWatchMaxPages = 2
+
+// WatchMark is the high-water mark of a watched channel: the newest upload already delivered.
+type WatchMark struct {
+	VideoId     string    `json:"videoId,omitempty"`
+	PublishedAt time.Time `json:"publishedAt,omitempty"`
+}
+
+// MarkStore persists watcher high-water marks so restarts don't redeliver videos.
+type MarkStore interface {
+	// LoadMark returns the stored mark of channelId, or the zero mark when none was saved.
+	LoadMark(channelId string) (WatchMark, error)
+	SaveMark(channelId string, mark WatchMark) error
+}
+
+// MemoryMarkStore keeps marks in memory. It is the default store and does not survive restarts.
+type MemoryMarkStore struct {
+	marks map[string]WatchMark
+	sync.Mutex
+}
+
+// NewMemoryMarkStore creates an empty MemoryMarkStore.
+func NewMemoryMarkStore() *MemoryMarkStore {
+	return &MemoryMarkStore{marks: make(map[string]WatchMark)}
+}
+
+func (s *MemoryMarkStore) LoadMark(channelId string) (WatchMark, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.marks[channelId], nil
+}
+
+func (s *MemoryMarkStore) SaveMark(channelId string, mark WatchMark) error {
+	s.Lock()
+	defer s.Unlock()
+	s.marks[channelId] = mark
+	return nil
+}
+
+// FileMarkStore keeps marks of every channel in a single JSON file.
+type FileMarkStore struct {
+	path string
+	sync.Mutex
+}
+
+// NewFileMarkStore creates a FileMarkStore backed by path. The file is created on the first save.
+func NewFileMarkStore(path string) *FileMarkStore {
+	return &FileMarkStore{path: path}
+}
+
+func (s *FileMarkStore) LoadMark(channelId string) (WatchMark, error) {
+	s.Lock()
+	defer s.Unlock()
+	marks, err := s.read()
+	if err != nil {
+		return WatchMark{}, err
+	}
+	return marks[channelId], nil
+}
+
+func (s *FileMarkStore) SaveMark(channelId string, mark WatchMark) error {
+	s.Lock()
+	defer s.Unlock()
+	marks, err := s.read()
+	if err != nil {
+		return err
+	}
+	marks[channelId] = mark
+	data, err := json.MarshalIndent(marks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal watch marks: %w", err)
+	}
+	// Write through a temporary file so a crash never leaves a truncated store behind.
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed writing watch marks: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *FileMarkStore) read() (map[string]WatchMark, error) {
+	marks := make(map[string]WatchMark)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return marks, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading watch marks: %w", err)
+	}
+	if err := json.Unmarshal(data, &marks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal watch marks: %w", err)
+	}
+	return marks, nil
+}
+
+// Watcher polls a channel's uploads playlist and invokes a callback for every new video,
+// as an alternative to WebSubSubscriber where the hub can't reach the process.
+type Watcher struct {
+	yt        *YoutubeApi
+	channelId string
+	callback  func(context.Context, *Video) error
+	interval  time.Duration
+	jitter    float64
+	maxPages  int
+	backfill  bool
+	store     MarkStore
+	onError   func(error)
+	webhook   *WebhookSink
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// WatcherOption configures a Watcher created with NewWatcher.
+type WatcherOption func(*Watcher)
+
+// WithWatchInterval sets the time between polls.
+func WithWatchInterval(interval time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+// WithWatchJitter sets the fraction of the interval each poll is randomly shifted by. Zero disables jitter.
+func WithWatchJitter(fraction float64) WatcherOption {
+	return func(w *Watcher) {
+		w.jitter = fraction
+	}
+}
+
+// WithWatchMaxPages bounds how many upload pages a single poll walks.
+func WithWatchMaxPages(pages int) WatcherOption {
+	return func(w *Watcher) {
+		w.maxPages = pages
+	}
+}
+
+// WithMarkStore sets the store the high-water mark is persisted in.
+// A nil store is ignored and the default MemoryMarkStore is kept.
+func WithMarkStore(store MarkStore) WatcherOption {
+	return func(w *Watcher) {
+		if store != nil {
+			w.store = store
+		}
+	}
+}
+
+// WithWatchBackfill delivers the videos found by the first poll of a channel without a stored mark.
+// By default that poll only records the mark, so a new watcher doesn't replay the channel's history.
+func WithWatchBackfill(backfill bool) WatcherOption {
+	return func(w *Watcher) {
+		w.backfill = backfill
+	}
+}
+
+// WithWatchErrorHandler sets the function poll and callback errors are reported to.
+// By default errors are reported to the client's Logger and polling continues.
+func WithWatchErrorHandler(fn func(error)) WatcherOption {
+	return func(w *Watcher) {
+		w.onError = fn
+	}
+}
+
+// WithWatchWebhook posts a WebhookNewUpload event to sink for each new upload, after the callback
+// handled it. When the delivery fails, the mark isn't advanced and the video is delivered again,
+// to both the callback and the sink, on the next poll.
+func WithWatchWebhook(sink *WebhookSink) WatcherOption {
+	return func(w *Watcher) {
+		w.webhook = sink
+	}
+}
+
+// NewWatcher creates a Watcher that calls callback for each new upload of channelId, oldest first.
+// When callback returns an error, the mark stays on the previous video and the video is retried on the next poll.
+// callback may be nil when the uploads are only posted to a webhook set with WithWatchWebhook.
+func (yt *YoutubeApi) NewWatcher(channelId string, callback func(context.Context, *Video) error, opts ...WatcherOption) *Watcher {
+	w := &Watcher{
+		yt:        yt,
+		channelId: channelId,
+		callback:  callback,
+		interval:  DefaultWatchInterval,
+		jitter:    DefaultWatchJitter,
+		maxPages:  DefaultWatchMaxPages,
+		store:     NewMemoryMarkStore(),
+		onError: func(err error) {
+			yt.logger.Error("channel watcher poll failed", Field{"channelId", channelId}, Field{"error", err})
+		},
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run polls until ctx is cancelled or Stop is called. A poll in progress is allowed to finish,
+// after which Run returns nil.
+func (w *Watcher) Run(ctx context.Context) error {
+	w.mu.Lock()
+	if w.done != nil {
+		w.mu.Unlock()
+		return errors.New("watcher is already running")
+	}
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.done = make(chan struct{})
+	done := w.done
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		w.cancel()
+		w.done = nil
+		w.mu.Unlock()
+		close(done)
+	}()
+
+	for {
+		if err := w.Poll(ctx); err != nil && ctx.Err() == nil {
+			w.onError(err)
+		}
+		timer := time.NewTimer(w.nextDelay())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// Stop ends a running Run call and waits for it to return.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	w.mu.Unlock()
+	if done == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// nextDelay returns the interval shifted by a random amount within the jitter fraction.
+func (w *Watcher) nextDelay() time.Duration {
+	if w.jitter <= 0 {
+		return w.interval
+	}
+	spread := float64(w.interval) * w.jitter
+	return w.interval + time.Duration((rand.Float64()*2-1)*spread)
+}
+
+// Poll checks the channel once and delivers every video newer than the stored mark.
+// Cancelling ctx aborts the API requests, but callbacks run under a context that outlives
+// cancellation so a video is never interrupted halfway through being handled.
+func (w *Watcher) Poll(ctx context.Context) error {
+	mark, err := w.store.LoadMark(w.channelId)
+	if err != nil {
+		return err
+	}
+
+	var fresh []*Video
+	pager := w.yt.ChannelUploads(w.channelId)
+	reached := false
+	for i := 0; i < w.maxPages && pager.HasNext() && !reached; i++ {
+		page, err := pager.NextContext(ctx)
+		if err != nil {
+			return err
+		}
+		for _, v := range page.Items {
+			if v.Id == mark.VideoId || (!mark.PublishedAt.IsZero() && !v.PublishedAt().After(mark.PublishedAt)) {
+				reached = true
+				break
+			}
+			fresh = append(fresh, v)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	if mark == (WatchMark{}) && !w.backfill {
+		newest := fresh[0]
+		return w.store.SaveMark(w.channelId, WatchMark{VideoId: newest.Id, PublishedAt: newest.PublishedAt()})
+	}
+
+	callbackCtx := context.WithoutCancel(ctx)
+	for i := len(fresh) - 1; i >= 0; i-- {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		v := fresh[i]
+		if w.callback != nil {
+			if err := w.callback(callbackCtx, v); err != nil {
+				return fmt.Errorf("watcher callback failed for video %s: %w", v.Id, err)
+			}
+		}
+		if w.webhook != nil {
+			if err := w.webhook.Send(callbackCtx, WebhookNewUpload, UploadEvent{ChannelId: w.channelId, Video: v}); err != nil {
+				return fmt.Errorf("watcher webhook failed for video %s: %w", v.Id, err)
+			}
+		}
+		if err := w.store.SaveMark(w.channelId, WatchMark{VideoId: v.Id, PublishedAt: v.PublishedAt()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
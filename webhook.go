@@ -0,0 +1,232 @@
+package alaitube
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types of the events posted by a WebhookSink.
+const (
+	// WebhookCrawlJobCompleted is posted by a Crawler when a job is done. Its data is the CrawlProgress of the job.
+	WebhookCrawlJobCompleted = "crawl.job.completed"
+	// WebhookNewUpload is posted by a Watcher for every new upload. Its data is an UploadEvent.
+	WebhookNewUpload = "watch.upload"
+)
+
+// Headers of the requests posted by a WebhookSink.
+const (
+	WebhookEventHeader     = "X-Alaitube-Event"
+	WebhookDeliveryHeader  = "X-Alaitube-Delivery"
+	WebhookTimestampHeader = "X-Alaitube-Timestamp"
+	WebhookSignatureHeader = "X-Alaitube-Signature"
+)
+
+// DefaultWebhookRetryPolicy is used by webhook sinks that were not given a RetryPolicy. Receivers
+// such as Zapier are slower to recover than the YouTube API, so it waits longer between attempts.
+var DefaultWebhookRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   time.Second,
+	MaxDelay:    time.Minute,
+}
+
+// DefaultWebhookTolerance is the age beyond which VerifyWebhookSignature rejects a request, so a
+// captured request can't be replayed later.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// ErrInvalidWebhookSignature is returned by VerifyWebhookSignature for a request that wasn't
+// signed with the secret, or was signed too long ago.
+var ErrInvalidWebhookSignature = errors.New("webhook signature mismatch")
+
+// WebhookEvent is the JSON payload posted by a WebhookSink.
+type WebhookEvent struct {
+	// Id identifies the event. It is also sent in the X-Alaitube-Delivery header and is the same
+	// across the retries of a delivery, so receivers can discard duplicates.
+	Id        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// UploadEvent is the data of a WebhookNewUpload event.
+type UploadEvent struct {
+	ChannelId string `json:"channelId"`
+	Video     *Video `json:"video"`
+}
+
+// WebhookSink posts events to a webhook URL as signed JSON, retrying failed deliveries, so
+// downstream systems can react to crawls and uploads without polling the process. Set it on a
+// Crawler with WithCrawlWebhook and on a Watcher with WithWatchWebhook.
+//
+// With a secret, each request carries an X-Alaitube-Timestamp header with the Unix time it was
+// sent, and an X-Alaitube-Signature header of the form "sha256=<hex>" holding the HMAC-SHA256 of
+// the timestamp, a dot, and the body. Receivers check it with VerifyWebhookSignature.
+type WebhookSink struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+	retry      RetryPolicy
+}
+
+// WebhookOption configures a WebhookSink created with NewWebhookSink.
+type WebhookOption func(*WebhookSink)
+
+// WithWebhookSecret sets the secret requests are signed with. Without one, requests are unsigned.
+func WithWebhookSecret(secret string) WebhookOption {
+	return func(s *WebhookSink) {
+		s.secret = secret
+	}
+}
+
+// WithWebhookHttpClient sets the http.Client requests are sent with.
+// A nil client is ignored and the default client is kept.
+func WithWebhookHttpClient(client *http.Client) WebhookOption {
+	return func(s *WebhookSink) {
+		if client != nil {
+			s.httpClient = client
+		}
+	}
+}
+
+// WithWebhookRetryPolicy sets the policy used to retry deliveries failing with a network error,
+// a 408 or 429 status, or a 5xx status. Other statuses aren't retried.
+func WithWebhookRetryPolicy(policy RetryPolicy) WebhookOption {
+	return func(s *WebhookSink) {
+		s.retry = policy
+	}
+}
+
+// NewWebhookSink creates a sink posting events to url.
+func NewWebhookSink(url string, opts ...WebhookOption) *WebhookSink {
+	s := &WebhookSink{
+		url:        url,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		retry:      DefaultWebhookRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Send posts an event of eventType with data marshaled as JSON, and returns once the receiver
+// answered with a 2xx status or the retries are exhausted.
+func (s *WebhookSink) Send(ctx context.Context, eventType string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed encoding webhook data: %w", err)
+	}
+	id, err := newEventId()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(WebhookEvent{Id: id, Type: eventType, CreatedAt: time.Now().UTC(), Data: raw})
+	if err != nil {
+		return fmt.Errorf("failed encoding webhook event: %w", err)
+	}
+
+	attempts := s.retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := s.post(ctx, eventType, id, body)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			resp.Body.Close()
+			return nil
+		}
+		var delay time.Duration
+		retryable := shouldRetry(ctx, resp, err)
+		if resp != nil {
+			retryable = retryable || resp.StatusCode == http.StatusRequestTimeout
+			// Drained so the connection can be reused by the retry.
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+			err = fmt.Errorf("receiver answered %s", resp.Status)
+			if d, ok := retryAfter(resp.Header); ok {
+				delay = d
+			}
+		}
+		if !retryable || attempt+1 >= attempts {
+			return fmt.Errorf("webhook delivery of %s event %s failed after %d attempts: %w", eventType, id, attempt+1, err)
+		}
+		if delay == 0 {
+			delay = s.retry.backoff(attempt)
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// post sends a single delivery attempt, signed at the time it is sent.
+func (s *WebhookSink) post(ctx context.Context, eventType, id string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, eventType)
+	req.Header.Set(WebhookDeliveryHeader, id)
+	if s.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(webhookMAC(s.secret, timestamp, body)))
+	}
+	return s.httpClient.Do(req)
+}
+
+// VerifyWebhookSignature checks the signature headers of a request posted by a WebhookSink with
+// secret against its body, rejecting requests signed more than tolerance ago. A tolerance of zero
+// uses DefaultWebhookTolerance.
+func VerifyWebhookSignature(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	if tolerance == 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	timestamp := header.Get(WebhookTimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing or invalid timestamp", ErrInvalidWebhookSignature)
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidWebhookSignature)
+	}
+	algo, sig, ok := strings.Cut(header.Get(WebhookSignatureHeader), "=")
+	if !ok || algo != "sha256" {
+		return fmt.Errorf("%w: missing or invalid signature", ErrInvalidWebhookSignature)
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, webhookMAC(secret, timestamp, body)) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// webhookMAC returns the HMAC-SHA256 of a timestamp and body.
+func webhookMAC(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// newEventId returns a random event ID.
+func newEventId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed generating event ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
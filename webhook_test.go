@@ -0,0 +1,119 @@
+package alaitube_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/josephalai/alaitube"
+)
+
+// webhookReceiver is a receiver verifying deliveries with its secret, answering 401 to those
+// failing verification, and keeping the headers and body of the last one.
+type webhookReceiver struct {
+	*httptest.Server
+	mu     sync.Mutex
+	header http.Header
+	body   []byte
+}
+
+func newWebhookReceiver(secret string) *webhookReceiver {
+	r := &webhookReceiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.mu.Lock()
+		r.header, r.body = req.Header.Clone(), body
+		r.mu.Unlock()
+		if err := alaitube.VerifyWebhookSignature(secret, req.Header, body, 0); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	return r
+}
+
+// last returns the headers and body of the last delivery.
+func (r *webhookReceiver) last() (http.Header, []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.header.Clone(), append([]byte(nil), r.body...)
+}
+
+func TestWebhookSinkSignature(t *testing.T) {
+	const secret = "s3cret"
+	receiver := newWebhookReceiver(secret)
+	defer receiver.Close()
+	noRetry := alaitube.WithWebhookRetryPolicy(alaitube.RetryPolicy{MaxAttempts: 1})
+	ctx := context.Background()
+
+	sink := alaitube.NewWebhookSink(receiver.URL, alaitube.WithWebhookSecret(secret), noRetry)
+	data := alaitube.UploadEvent{ChannelId: "UCcats"}
+	if err := sink.Send(ctx, alaitube.WebhookNewUpload, data); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	header, body := receiver.last()
+	var event alaitube.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("decoding event: %v", err)
+	}
+	if event.Type != alaitube.WebhookNewUpload || event.Id == "" || event.Id != header.Get(alaitube.WebhookDeliveryHeader) {
+		t.Errorf("event = %+v with delivery %q, want a %s event identified by its delivery", event, header.Get(alaitube.WebhookDeliveryHeader), alaitube.WebhookNewUpload)
+	}
+	if string(event.Data) != `{"channelId":"UCcats","video":null}` {
+		t.Errorf("data = %s", event.Data)
+	}
+
+	t.Run("tampered body", func(t *testing.T) {
+		tampered := append(body[:len(body):len(body)], ' ')
+		if err := alaitube.VerifyWebhookSignature(secret, header, tampered, 0); !errors.Is(err, alaitube.ErrInvalidWebhookSignature) {
+			t.Errorf("VerifyWebhookSignature = %v, want ErrInvalidWebhookSignature", err)
+		}
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		if err := alaitube.VerifyWebhookSignature("other", header, body, 0); !errors.Is(err, alaitube.ErrInvalidWebhookSignature) {
+			t.Errorf("VerifyWebhookSignature = %v, want ErrInvalidWebhookSignature", err)
+		}
+		wrong := alaitube.NewWebhookSink(receiver.URL, alaitube.WithWebhookSecret("other"), noRetry)
+		if err := wrong.Send(ctx, alaitube.WebhookNewUpload, data); err == nil {
+			t.Error("Send signed with the wrong secret succeeded, want the receiver to reject it")
+		}
+		unsigned := alaitube.NewWebhookSink(receiver.URL, noRetry)
+		if err := unsigned.Send(ctx, alaitube.WebhookNewUpload, data); err == nil {
+			t.Error("unsigned Send succeeded, want the receiver to reject it")
+		}
+	})
+
+	t.Run("expired timestamp", func(t *testing.T) {
+		// Signed correctly, but an hour ago.
+		expired := header.Clone()
+		timestamp := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		expired.Set(alaitube.WebhookTimestampHeader, timestamp)
+		expired.Set(alaitube.WebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+		if err := alaitube.VerifyWebhookSignature(secret, expired, body, 0); !errors.Is(err, alaitube.ErrInvalidWebhookSignature) {
+			t.Errorf("VerifyWebhookSignature = %v, want ErrInvalidWebhookSignature", err)
+		}
+		if err := alaitube.VerifyWebhookSignature(secret, expired, body, 2*time.Hour); err != nil {
+			t.Errorf("VerifyWebhookSignature with a 2h tolerance = %v, want nil", err)
+		}
+	})
+}
@@ -0,0 +1,333 @@
+package alaitube
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWebSubHub is the hub YouTube publishes channel upload feeds to.
+const DefaultWebSubHub = "https://pubsubhubbub.appspot.com/subscribe"
+
+// ChannelFeedTopic is the Atom feed topic announcing a channel's uploads.
+const ChannelFeedTopic = "https://www.youtube.com/xml/feeds/videos.xml?channel_id=%s"
+
+// DefaultWebSubLease is the subscription lifetime requested from the hub. Subscriptions must
+// be renewed before the lease expires.
+const DefaultWebSubLease = 5 * 24 * time.Hour
+
+// ErrInvalidSignature is returned when a notification's X-Hub-Signature does not match the subscriber secret.
+var ErrInvalidSignature = errors.New("websub notification signature mismatch")
+
+// UploadNotification is a single entry pushed by the hub when a channel publishes, updates,
+// or deletes a video.
+type UploadNotification struct {
+	VideoId   string
+	ChannelId string
+	Title     string
+	Link      string
+	Author    string
+	Published time.Time
+	Updated   time.Time
+	// Deleted is set when the notification announces a removed video; only VideoId and Updated are filled in.
+	Deleted bool
+}
+
+// WebSubSubscriber subscribes to channel upload feeds through a WebSub (PubSubHubbub) hub and
+// receives the pushed notifications. It is an http.Handler that must be served at the callback URL.
+type WebSubSubscriber struct {
+	callback   string
+	hub        string
+	secret     string
+	lease      time.Duration
+	httpClient *http.Client
+	handler    func(*UploadNotification)
+	notify     chan *UploadNotification
+
+	topics map[string]bool
+	// unsubscribing holds the topics an unsubscribe was requested for and not yet verified.
+	unsubscribing map[string]bool
+	sync.Mutex
+}
+
+// WebSubOption configures a WebSubSubscriber created with NewWebSubSubscriber.
+type WebSubOption func(*WebSubSubscriber)
+
+// WithWebSubSecret sets the secret the hub signs notifications with. Notifications with a
+// missing or invalid signature are rejected when a secret is set.
+func WithWebSubSecret(secret string) WebSubOption {
+	return func(s *WebSubSubscriber) {
+		s.secret = secret
+	}
+}
+
+// WithWebSubHub sets the hub subscription requests are sent to.
+func WithWebSubHub(hub string) WebSubOption {
+	return func(s *WebSubSubscriber) {
+		s.hub = hub
+	}
+}
+
+// WithWebSubLease sets the subscription lifetime requested from the hub.
+func WithWebSubLease(lease time.Duration) WebSubOption {
+	return func(s *WebSubSubscriber) {
+		s.lease = lease
+	}
+}
+
+// WithWebSubHttpClient sets the http.Client used for subscription requests.
+// A nil client is ignored and the default client is kept.
+func WithWebSubHttpClient(client *http.Client) WebSubOption {
+	return func(s *WebSubSubscriber) {
+		if client != nil {
+			s.httpClient = client
+		}
+	}
+}
+
+// WithWebSubHandler delivers every notification to fn instead of the Notifications channel.
+// fn is called from the HTTP handler goroutine and should return quickly.
+func WithWebSubHandler(fn func(*UploadNotification)) WebSubOption {
+	return func(s *WebSubSubscriber) {
+		s.handler = fn
+	}
+}
+
+// NewWebSubSubscriber creates a subscriber whose hub callbacks are sent to callbackURL.
+// Without WithWebSubHandler, notifications are delivered on the Notifications channel.
+func NewWebSubSubscriber(callbackURL string, opts ...WebSubOption) *WebSubSubscriber {
+	s := &WebSubSubscriber{
+		callback:      callbackURL,
+		hub:           DefaultWebSubHub,
+		lease:         DefaultWebSubLease,
+		httpClient:    &http.Client{Timeout: DefaultTimeout},
+		notify:        make(chan *UploadNotification, 64),
+		topics:        make(map[string]bool),
+		unsubscribing: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Notifications returns the channel notifications are delivered on when no handler is set.
+func (s *WebSubSubscriber) Notifications() <-chan *UploadNotification {
+	return s.notify
+}
+
+// Subscribe asks the hub to push the upload feed of channelId to the callback URL.
+// The hub confirms asynchronously by calling the handler, so the subscriber must already be served.
+func (s *WebSubSubscriber) Subscribe(ctx context.Context, channelId string) error {
+	topic := fmt.Sprintf(ChannelFeedTopic, channelId)
+	s.Lock()
+	s.topics[topic] = true
+	delete(s.unsubscribing, topic)
+	s.Unlock()
+	return s.hubRequest(ctx, "subscribe", topic)
+}
+
+// Unsubscribe asks the hub to stop pushing the upload feed of channelId. Like Subscribe, the hub
+// confirms asynchronously by calling the handler.
+func (s *WebSubSubscriber) Unsubscribe(ctx context.Context, channelId string) error {
+	topic := fmt.Sprintf(ChannelFeedTopic, channelId)
+	// Recorded before the request, since the hub may verify before answering it.
+	s.Lock()
+	s.unsubscribing[topic] = true
+	s.Unlock()
+	if err := s.hubRequest(ctx, "unsubscribe", topic); err != nil {
+		s.Lock()
+		delete(s.unsubscribing, topic)
+		s.Unlock()
+		return err
+	}
+	s.Lock()
+	delete(s.topics, topic)
+	s.Unlock()
+	return nil
+}
+
+// hubRequest sends a subscription request for topic to the hub.
+func (s *WebSubSubscriber) hubRequest(ctx context.Context, mode, topic string) error {
+	form := url.Values{}
+	form.Set("hub.callback", s.callback)
+	form.Set("hub.topic", topic)
+	form.Set("hub.mode", mode)
+	form.Set("hub.verify", "async")
+	if s.secret != "" {
+		form.Set("hub.secret", s.secret)
+	}
+	if mode == "subscribe" && s.lease > 0 {
+		form.Set("hub.lease_seconds", strconv.Itoa(int(s.lease/time.Second)))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed creating hub request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed sending hub request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("hub rejected %s of %s: %w", mode, topic, statusError(resp.StatusCode, body))
+	}
+	return nil
+}
+
+// ServeHTTP answers hub verification challenges and accepts pushed notifications.
+func (s *WebSubSubscriber) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.verify(w, r)
+	case http.MethodPost:
+		s.receive(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verify confirms subscription intent for topics this subscriber asked for: subscriptions to its
+// topics and unsubscriptions it requested. Anyone can ask a hub to unsubscribe a callback, so
+// other unsubscriptions are refused.
+func (s *WebSubSubscriber) verify(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	mode, topic := q.Get("hub.mode"), q.Get("hub.topic")
+	switch mode {
+	case "subscribe", "unsubscribe":
+		s.Lock()
+		var requested bool
+		if mode == "subscribe" {
+			requested = s.topics[topic]
+		} else {
+			requested = s.unsubscribing[topic]
+			delete(s.unsubscribing, topic)
+		}
+		s.Unlock()
+		if !requested {
+			http.Error(w, "unknown topic", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, q.Get("hub.challenge"))
+	case "denied":
+		s.Lock()
+		delete(s.topics, topic)
+		s.Unlock()
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, "unsupported hub.mode", http.StatusBadRequest)
+	}
+}
+
+// receive validates and parses a pushed Atom feed and delivers its entries.
+func (s *WebSubSubscriber) receive(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed reading body", http.StatusBadRequest)
+		return
+	}
+	if s.secret != "" && !validSignature(s.secret, r.Header.Get("X-Hub-Signature"), body) {
+		// Hubs expect a 2xx even for forged messages, so they are acknowledged and dropped.
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	notifications, err := ParseUploadFeed(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, n := range notifications {
+		if s.handler != nil {
+			s.handler(n)
+			continue
+		}
+		select {
+		case s.notify <- n:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validSignature checks an X-Hub-Signature header of the form "sha1=<hex>" against body.
+func validSignature(secret, header string, body []byte) bool {
+	algo, sig, ok := strings.Cut(header, "=")
+	if !ok || algo != "sha1" {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// uploadFeed is the Atom document pushed by the hub.
+type uploadFeed struct {
+	Entries []struct {
+		VideoId   string `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
+		ChannelId string `xml:"http://www.youtube.com/xml/schemas/2015 channelId"`
+		Title     string `xml:"title"`
+		Link      struct {
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Author struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Published time.Time `xml:"published"`
+		Updated   time.Time `xml:"updated"`
+	} `xml:"entry"`
+	Deleted []struct {
+		Ref  string    `xml:"ref,attr"`
+		When time.Time `xml:"when,attr"`
+	} `xml:"http://purl.org/atompub/tombstones/1.0 deleted-entry"`
+}
+
+// ParseUploadFeed parses an Atom document pushed by the YouTube hub into notifications.
+func ParseUploadFeed(body []byte) ([]*UploadNotification, error) {
+	feed := &uploadFeed{}
+	if err := xml.Unmarshal(body, feed); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal upload feed: %w", err)
+	}
+
+	var notifications []*UploadNotification
+	for _, e := range feed.Entries {
+		notifications = append(notifications, &UploadNotification{
+			VideoId:   e.VideoId,
+			ChannelId: e.ChannelId,
+			Title:     e.Title,
+			Link:      e.Link.Href,
+			Author:    e.Author.Name,
+			Published: e.Published,
+			Updated:   e.Updated,
+		})
+	}
+	for _, d := range feed.Deleted {
+		notifications = append(notifications, &UploadNotification{
+			VideoId: strings.TrimPrefix(d.Ref, "yt:video:"),
+			Updated: d.When,
+			Deleted: true,
+		})
+	}
+	return notifications, nil
+}
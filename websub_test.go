@@ -0,0 +1,62 @@
+package alaitube_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/josephalai/alaitube"
+)
+
+func TestWebSubVerify(t *testing.T) {
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer hub.Close()
+
+	sub := alaitube.NewWebSubSubscriber("https://example.com/websub", alaitube.WithWebSubHub(hub.URL))
+	ctx := context.Background()
+	if err := sub.Subscribe(ctx, "UCsubscribed"); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := sub.Subscribe(ctx, "UCleaving"); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := sub.Unsubscribe(ctx, "UCleaving"); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		mode       string
+		channelId  string
+		wantStatus int
+	}{
+		{name: "subscribe to subscribed topic", mode: "subscribe", channelId: "UCsubscribed", wantStatus: http.StatusOK},
+		{name: "subscribe to unknown topic", mode: "subscribe", channelId: "UCother", wantStatus: http.StatusNotFound},
+		{name: "requested unsubscribe", mode: "unsubscribe", channelId: "UCleaving", wantStatus: http.StatusOK},
+		{name: "unsubscribe verified once", mode: "unsubscribe", channelId: "UCleaving", wantStatus: http.StatusNotFound},
+		{name: "unrequested unsubscribe", mode: "unsubscribe", channelId: "UCsubscribed", wantStatus: http.StatusNotFound},
+		{name: "unsubscribe of unknown topic", mode: "unsubscribe", channelId: "UCother", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := url.Values{
+				"hub.mode":      {tt.mode},
+				"hub.topic":     {fmt.Sprintf(alaitube.ChannelFeedTopic, tt.channelId)},
+				"hub.challenge": {"challenge-1"},
+			}
+			rec := httptest.NewRecorder()
+			sub.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/websub?"+q.Encode(), nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && rec.Body.String() != "challenge-1" {
+				t.Errorf("body = %q, want the challenge", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+// Package xlsxexport writes fetched videos, channels, and their tag statistics as an Excel
+// workbook, with a sheet for each, formatted headers, and titles linking to YouTube:
+//
+//	f, err := os.Create("report.xlsx")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	if err := xlsxexport.Write(f, xlsxexport.Workbook{Videos: results, Channels: channels}); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// Counts are written as numbers and dates as Excel dates, so the sheets can be sorted, filtered,
+// and charted without conversion. Cells of parts that weren't fetched are left empty.
+package xlsxexport
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/josephalai/alaitube"
+	"github.com/xuri/excelize/v2"
+)
+
+// Names of the workbook's sheets.
+const (
+	SheetVideos   = "Videos"
+	SheetChannels = "Channels"
+	SheetTags     = "Tags"
+)
+
+// DefaultTagLimit is the number of tags in the tag statistics sheet when none is set.
+const DefaultTagLimit = 500
+
+// Workbook holds the data of an exported workbook. Sheets without data are left out.
+type Workbook struct {
+	Videos   *alaitube.VideoResults
+	Channels *alaitube.ChannelInfo
+	// Tags are the rows of the tag statistics sheet. When nil, they are computed from Videos,
+	// ranked by view-weighted score.
+	Tags []alaitube.TagStat
+}
+
+// Option configures Write.
+type Option func(*config)
+
+type config struct {
+	analyzer *alaitube.TagAnalyzer
+	tagLimit int
+}
+
+// WithTagAnalyzer sets the analyzer computing the tag statistics of Workbook.Videos. The default
+// is alaitube.NewTagAnalyzer().
+func WithTagAnalyzer(a *alaitube.TagAnalyzer) Option {
+	return func(c *config) {
+		if a != nil {
+			c.analyzer = a
+		}
+	}
+}
+
+// WithTagLimit bounds the rows of the computed tag statistics sheet. Zero or less keeps every tag.
+func WithTagLimit(n int) Option {
+	return func(c *config) {
+		c.tagLimit = n
+	}
+}
+
+// column is a column of a sheet: a header, a width, a style, and the value of each row. Values
+// are written as they are, so numbers stay numbers; nil leaves the cell empty.
+type column[T any] struct {
+	name  string
+	width float64
+	style func(s *styles) int
+	value func(T) interface{}
+}
+
+// styles holds the style IDs shared by the sheets.
+type styles struct {
+	header, link, integer, decimal, percent, date, duration int
+}
+
+var videoColumns = []column[*alaitube.Video]{
+	{"Title", 60, linkStyle, func(v *alaitube.Video) interface{} {
+		if v.Snippet == nil {
+			return nil
+		}
+		return v.Snippet.Title
+	}},
+	{"Channel", 28, nil, func(v *alaitube.Video) interface{} {
+		if v.Snippet == nil {
+			return nil
+		}
+		return v.Snippet.ChannelTitle
+	}},
+	{"Views", 14, integerStyle, videoCount(func(v *alaitube.Video) alaitube.Count { return v.Statistics.ViewCount })},
+	{"Likes", 12, integerStyle, videoCount(func(v *alaitube.Video) alaitube.Count { return v.Statistics.LikeCount })},
+	{"Comments", 12, integerStyle, videoCount(func(v *alaitube.Video) alaitube.Count { return v.Statistics.CommentCount })},
+	{"Engagement", 12, percentStyle, func(v *alaitube.Video) interface{} {
+		if v.Statistics == nil || v.Statistics.ViewCount == 0 {
+			return nil
+		}
+		return v.EngagementRate()
+	}},
+	{"Duration", 10, durationStyle, func(v *alaitube.Video) interface{} {
+		if d := v.Duration(); d > 0 {
+			// Excel durations are fractions of a day.
+			return d.Hours() / 24
+		}
+		return nil
+	}},
+	{"Published", 18, dateStyle, func(v *alaitube.Video) interface{} { return excelTime(v.PublishedAt()) }},
+	{"Tags", 50, nil, func(v *alaitube.Video) interface{} {
+		if v.Snippet == nil {
+			return nil
+		}
+		return strings.Join(v.Snippet.Tags, ", ")
+	}},
+	{"Id", 14, nil, func(v *alaitube.Video) interface{} { return v.Id }},
+}
+
+var channelColumns = []column[*alaitube.Item]{
+	{"Title", 40, linkStyle, func(c *alaitube.Item) interface{} {
+		if c.Snippet == nil {
+			return nil
+		}
+		return c.Snippet.Title
+	}},
+	{"Subscribers", 14, integerStyle, func(c *alaitube.Item) interface{} {
+		if c.Statistics == nil || c.Statistics.HiddenSubscriberCount {
+			return nil
+		}
+		return c.Statistics.SubscriberCount.Int64()
+	}},
+	{"Views", 16, integerStyle, channelCount(func(c *alaitube.Item) alaitube.Count { return c.Statistics.ViewCount })},
+	{"Videos", 10, integerStyle, channelCount(func(c *alaitube.Item) alaitube.Count { return c.Statistics.VideoCount })},
+	{"Country", 10, nil, func(c *alaitube.Item) interface{} {
+		if c.Snippet == nil {
+			return nil
+		}
+		return c.Snippet.Country
+	}},
+	{"Created", 18, dateStyle, func(c *alaitube.Item) interface{} {
+		if c.Snippet == nil {
+			return nil
+		}
+		return excelTime(c.Snippet.PublishedAt)
+	}},
+	{"Id", 26, nil, func(c *alaitube.Item) interface{} { return c.Id }},
+}
+
+var tagColumns = []column[alaitube.TagStat]{
+	{"Tag", 36, nil, func(t alaitube.TagStat) interface{} { return t.Tag }},
+	{"Videos", 10, integerStyle, func(t alaitube.TagStat) interface{} { return t.Count }},
+	{"Views", 16, integerStyle, func(t alaitube.TagStat) interface{} { return t.Views }},
+	{"Score", 10, decimalStyle, func(t alaitube.TagStat) interface{} { return t.Score }},
+}
+
+func linkStyle(s *styles) int     { return s.link }
+func integerStyle(s *styles) int  { return s.integer }
+func decimalStyle(s *styles) int  { return s.decimal }
+func percentStyle(s *styles) int  { return s.percent }
+func dateStyle(s *styles) int     { return s.date }
+func durationStyle(s *styles) int { return s.duration }
+
+// videoCount returns a column value reading a count, or nil when statistics weren't fetched.
+func videoCount(count func(v *alaitube.Video) alaitube.Count) func(v *alaitube.Video) interface{} {
+	return func(v *alaitube.Video) interface{} {
+		if v.Statistics == nil {
+			return nil
+		}
+		return count(v).Int64()
+	}
+}
+
+// channelCount returns a column value reading a count, or nil when statistics weren't fetched.
+func channelCount(count func(c *alaitube.Item) alaitube.Count) func(c *alaitube.Item) interface{} {
+	return func(c *alaitube.Item) interface{} {
+		if c.Statistics == nil {
+			return nil
+		}
+		return count(c).Int64()
+	}
+}
+
+// excelTime returns t in UTC, or nil when it is zero. Excel has no time zones, so UTC keeps the
+// dates of every sheet comparable.
+func excelTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC()
+}
+
+// Write writes wb to w as an xlsx workbook.
+func Write(w io.Writer, wb Workbook, opts ...Option) error {
+	f, err := build(wb, opts)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.WriteTo(w); err != nil {
+		return fmt.Errorf("failed writing workbook: %w", err)
+	}
+	return nil
+}
+
+// WriteFile writes wb to the xlsx file name, replacing it when it exists.
+func WriteFile(name string, wb Workbook, opts ...Option) error {
+	f, err := build(wb, opts)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.SaveAs(name); err != nil {
+		return fmt.Errorf("failed writing workbook: %w", err)
+	}
+	return nil
+}
+
+// build creates the workbook of wb in memory.
+func build(wb Workbook, opts []Option) (*excelize.File, error) {
+	c := config{tagLimit: DefaultTagLimit}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	tags := wb.Tags
+	if tags == nil && wb.Videos != nil {
+		if c.analyzer == nil {
+			c.analyzer = alaitube.NewTagAnalyzer()
+		}
+		tags = c.analyzer.Analyze(wb.Videos).TopByScore(c.tagLimit)
+	}
+
+	f := excelize.NewFile()
+	s, err := newStyles(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	// A new file starts with a "Sheet1" sheet, renamed to the first sheet written.
+	first := true
+	sheet := func(name string) (string, error) {
+		if first {
+			first = false
+			return name, f.SetSheetName("Sheet1", name)
+		}
+		_, err := f.NewSheet(name)
+		return name, err
+	}
+
+	if wb.Videos != nil {
+		name, err := sheet(SheetVideos)
+		if err == nil {
+			err = writeSheet(f, s, name, videoColumns, wb.Videos.Items, func(v *alaitube.Video) string { return v.Url() })
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	if wb.Channels != nil {
+		name, err := sheet(SheetChannels)
+		if err == nil {
+			err = writeSheet(f, s, name, channelColumns, wb.Channels.Items, func(c *alaitube.Item) string { return c.Url() })
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	if len(tags) > 0 {
+		name, err := sheet(SheetTags)
+		if err == nil {
+			err = writeSheet(f, s, name, tagColumns, tags, nil)
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// newStyles registers the styles of the workbook.
+func newStyles(f *excelize.File) (*styles, error) {
+	dateFormat, durationFormat := "yyyy-mm-dd hh:mm", "[h]:mm:ss"
+	s := &styles{}
+	defs := []struct {
+		id    *int
+		style *excelize.Style
+	}{
+		{&s.header, &excelize.Style{
+			Font:   &excelize.Font{Bold: true, Color: "FFFFFF"},
+			Fill:   excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"C4302B"}},
+			Border: []excelize.Border{{Type: "bottom", Color: "7F1F1C", Style: 1}},
+		}},
+		{&s.link, &excelize.Style{Font: &excelize.Font{Color: "1265BE", Underline: "single"}}},
+		{&s.integer, &excelize.Style{NumFmt: 3}},
+		{&s.decimal, &excelize.Style{NumFmt: 4}},
+		{&s.percent, &excelize.Style{NumFmt: 10}},
+		{&s.date, &excelize.Style{CustomNumFmt: &dateFormat}},
+		{&s.duration, &excelize.Style{CustomNumFmt: &durationFormat}},
+	}
+	for _, d := range defs {
+		id, err := f.NewStyle(d.style)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating workbook style: %w", err)
+		}
+		*d.id = id
+	}
+	return s, nil
+}
+
+// writeSheet writes a header row and a row per item to sheet, freezing the header and enabling
+// filters on it. When link is set, the first cell of every row links to link(item).
+func writeSheet[T any](f *excelize.File, s *styles, sheet string, columns []column[T], items []T, link func(T) string) error {
+	header := make([]interface{}, len(columns))
+	for i, col := range columns {
+		header[i] = col.name
+	}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return fmt.Errorf("failed writing %s header: %w", sheet, err)
+	}
+	last, err := excelize.ColumnNumberToName(len(columns))
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, "A1", last+"1", s.header); err != nil {
+		return fmt.Errorf("failed styling %s header: %w", sheet, err)
+	}
+
+	row := make([]interface{}, len(columns))
+	for i, item := range items {
+		for j, col := range columns {
+			row[j] = col.value(item)
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return fmt.Errorf("failed writing %s row: %w", sheet, err)
+		}
+		if link == nil {
+			continue
+		}
+		if url := link(item); url != "" {
+			if err := f.SetCellHyperLink(sheet, cell, url, "External"); err != nil {
+				return fmt.Errorf("failed linking %s row: %w", sheet, err)
+			}
+		}
+	}
+
+	lastRow := len(items) + 1
+	for i, col := range columns {
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		if err := f.SetColWidth(sheet, name, name, col.width); err != nil {
+			return fmt.Errorf("failed sizing %s columns: %w", sheet, err)
+		}
+		if col.style == nil || len(items) == 0 {
+			continue
+		}
+		if err := f.SetCellStyle(sheet, name+"2", fmt.Sprintf("%s%d", name, lastRow), col.style(s)); err != nil {
+			return fmt.Errorf("failed styling %s columns: %w", sheet, err)
+		}
+	}
+
+	if err := f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return fmt.Errorf("failed freezing %s header: %w", sheet, err)
+	}
+	if err := f.AutoFilter(sheet, fmt.Sprintf("A1:%s%d", last, lastRow), nil); err != nil {
+		return fmt.Errorf("failed adding %s filters: %w", sheet, err)
+	}
+	return nil
+}
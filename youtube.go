@@ -1,27 +1,60 @@
 package alaitube
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/josephalai/alailog"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
 	"io"
-	"log"
-	"math"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
+// SearchVideoIds is the historical search URL format.
+//
+// Deprecated: search URLs are now built from SearchOptions against SearchEndpoint.
 const SearchVideoIds = "https://www.googleapis.com/youtube/v3/search?part=snippet&maxResults=100&q=%s&type=video&order=date&relevanceLanguage=en&key=%s%v"
-const GetTags = "https://www.googleapis.com/youtube/v3/videos?key=%s&fields=items(snippet(title,publishedAt,description,tags),id,statistics)&part=snippet,statistics&id=%v&order=date%v"
+
+// GetTags is the historical videos URL format.
+//
+// Deprecated: videos URLs are now built from Parts against VideosEndpoint.
+const GetTags = "https://www.googleapis.com/youtube/v3/videos?key=%s&fields=items(snippet(title,publishedAt,description,tags,liveBroadcastContent,categoryId),id,statistics,contentDetails,liveStreamingDetails,topicDetails)&part=snippet,statistics,contentDetails,liveStreamingDetails,topicDetails&id=%v&order=date%v"
 const GetChannelVideos = "https://www.googleapis.com/youtube/v3/channels/?part=snippet,contentDetails,statistics&id=%v&maxResults=50&key=%v"
 const GetChannelPlaylist = "https://www.googleapis.com/youtube/v3/playlistItems?part=snippet,contentDetails&maxResults=50&playlistId=%s&key=%s%s"
 
 // YoutubeApi represents a service for interacting with the YouTube API.
 type YoutubeApi struct {
-	apiKey string
+	apiKey      string
+	httpClient  *http.Client
+	middlewares []Middleware
+	dryRun      *DryRun
+	retry       RetryPolicy
+	tokens      oauth2.TokenSource
+	logger      Logger
+	observer    Observer
+	tracer      trace.Tracer
+	videoParts  Parts
+	compression bool
+	userAgent   string
+	// limiter caps every request of the client; endpointLimiters additionally cap single endpoints.
+	limiter          *rate.Limiter
+	endpointLimiters map[string]*rate.Limiter
+	// flights coalesces concurrent fetches of the same cache key.
+	flights   singleflight.Group
+	persister Persister
+	languages LanguageDetector
+	// tagNormalizer and tagFormatter rewrite the tags of fetched videos when set.
+	tagNormalizer *TagNormalizer
+	tagFormatter  *TagFormatter
 	Cache
 }
 
@@ -32,103 +65,146 @@ type YoutubeService struct {
 
 var youTubeServiceInstance = &YoutubeService{}
 
+// GetInstance returns the process-wide YoutubeApi singleton, creating it on the first call.
+// Options passed after the first call are ignored.
+//
+// Deprecated: use NewClient, which supports multiple independent clients.
 func GetInstance(optionalParams ...map[string]interface{}) *YoutubeApi {
-	var opt map[string]interface{}
-	var apiKey string
-	var cache Cache = NewMemoryCache()
+	var opts []Option
 	if len(optionalParams) > 0 {
-		opt = optionalParams[0]
-		apiKey = opt["apiKey"].(string)
+		opt := optionalParams[0]
+		if apiKey, ok := opt["apiKey"].(string); ok {
+			opts = append(opts, WithApiKey(apiKey))
+		}
 		if tCache, ok := opt["cache"].(Cache); ok {
-			cache = tCache
+			opts = append(opts, WithCache(tCache))
 		}
-		log.Printf("api key set %s", apiKey)
 	}
 	youTubeServiceInstance.Do(func() {
-		youTubeServiceInstance.Instance = NewYoutubeApi(apiKey, cache)
+		youTubeServiceInstance.Instance = NewClient(opts...)
+		youTubeServiceInstance.Instance.logger.Debug("youtube client created", Field{"cache", youTubeServiceInstance.Instance.GetServiceName()})
 	})
 
 	return youTubeServiceInstance.Instance
 }
 
-// NewYoutubeApi is now modified initialize the videoCache map
+// NewYoutubeApi creates a client with the given API key and cache.
+//
+// Deprecated: use NewClient(WithApiKey(apiKey), WithCache(cache)).
 func NewYoutubeApi(apiKey string, cache Cache) *YoutubeApi {
-	alailog.Printf("cache type: %s\n", cache.GetServiceName())
-	return &YoutubeApi{
-		apiKey: apiKey,
-		Cache:  cache,
-	}
+	yt := NewClient(WithApiKey(apiKey), WithCache(cache))
+	yt.logger.Debug("youtube client created", Field{"cache", yt.GetServiceName()})
+	return yt
 }
 
+// ApiKey returns the API key the client sends with every request.
 func (yt *YoutubeApi) ApiKey() string {
 	return yt.apiKey
 }
 
-// getChannelInfo queries the YouTube API for channel information using the given channel ID.
+// GetChannelInfo queries the YouTube API for channel information using the given channel ID,
+// with the client's own API key and http.Client.
 // It returns the channel information if found, otherwise returns an error.
-// If the channel info is nil or has no items available, it returns an error.
+// If the channel info is nil or has no items available, it returns an error wrapping ErrNotFound.
 func (yt *YoutubeApi) GetChannelInfo(channelId string) (*ChannelInfo, error) {
-	if v := yt.Cache.GetChannel(channelId); v != nil {
-		return v, nil
-	}
+	return yt.GetChannelInfoContext(context.Background(), channelId)
+}
 
-	cInfo, err := getChannelInfo(channelId)
-	if err != nil {
-		return nil, errors.New("channel info not found")
+// GetChannelInfoContext is like GetChannelInfo but uses ctx for the underlying API request.
+func (yt *YoutubeApi) GetChannelInfoContext(ctx context.Context, channelId string) (_ *ChannelInfo, err error) {
+	ctx, span := yt.startOperation(ctx, "GetChannelInfo")
+	defer func() { endSpan(span, err) }()
+
+	cacheKey := channelCacheKey(channelId, "")
+	if v := yt.Cache.GetChannel(cacheKey); v != nil {
+		span.SetAttributes(AttrCacheHit.Bool(true))
+		return v, nil
 	}
-	if cInfo == nil || len(cInfo.Items) == 0 {
-		return nil, errors.New("no item available in cInfo")
+	if err := yt.cachedNotFound(RegionChannels, cacheKey, channelId); err != nil {
+		span.SetAttributes(AttrCacheHit.Bool(true))
+		return nil, err
 	}
+	span.SetAttributes(AttrCacheHit.Bool(false))
+
+	v, err := yt.coalesce(ctx, RegionChannels, cacheKey, func() (interface{}, error) {
+		cInfo, err := yt.getChannelInfo(ctx, channelId)
+		if err != nil {
+			yt.rememberNotFound(RegionChannels, cacheKey, err)
+			return nil, fmt.Errorf("channel info not found: %w", err)
+		}
+		if cInfo == nil || len(cInfo.Items) == 0 {
+			err := fmt.Errorf("no item available in cInfo: %w", ErrNotFound)
+			yt.rememberNotFound(RegionChannels, cacheKey, err)
+			return nil, err
+		}
 
-	yt.Cache.SetChannel(channelId, cInfo)
+		yt.Cache.SetChannel(cacheKey, cInfo)
+		yt.persistChannels(ctx, cInfo)
 
-	return cInfo, nil
+		return cInfo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ChannelInfo), nil
 }
 
-// GetVideoCount converts the video count from string to integer and returns the result
+// GetVideoCount returns the video count of a channel item
 // Parameters:
 // - item: the item containing the video count value
 // Returns:
-// - int: the converted video count
-// - error: an error message if there was an error converting the video count string to integer
+// - int: the video count
+// - error: an error message if the item carries no statistics
 func (yt *YoutubeApi) GetVideoCount(item *Item) (int, error) {
-	vidCount, err := strconv.Atoi(item.Statistics.VideoCount)
-	if err != nil {
-		return 0, errors.New("internal server error")
+	if item.Statistics == nil {
+		return 0, errors.New("item has no statistics")
 	}
 
-	return vidCount, nil
+	return int(item.Statistics.VideoCount), nil
 }
 
-// getChannelPlaylist is a method of the YoutubeApi type that retrieves the playlist of videos for a given channel item.
-// The method accepts an item pointer and a vidCount integer as parameters.
-// If the item has non-nil ContentDetails and RelatedPlaylists, it calls the getChannelPlaylist function recursively with the uploads playlist ID and the vidCount value.
-// If the getChannelPlaylist function returns an error, it returns an error with the message "internal server error".
-// If the getChannelPlaylist function returns nil, it returns an error with the message "no results found".
-// If the item's ContentDetails or RelatedPlaylists are nil, it returns an error with the message "contentDetails or RelatedPlaylists are nil".
+// GetChannelPlaylist retrieves the videos of the pages of a channel's uploads playlist holding
+// vidCount videos, newest first, with their details. The item must have its ContentDetails and
+// RelatedPlaylists set, as returned by GetChannelInfo. Results are cached.
+// The NextPageToken of the results continues the uploads after those pages: save it and pass it
+// to GetPlaylistVideosFrom with the uploads playlist ID to resume a long crawl after a restart.
 func (yt *YoutubeApi) GetChannelPlaylist(item *Item, vidCount int) (*VideoResults, error) {
-	cacheKey := item.Id + "-" + strconv.Itoa(vidCount)
+	return yt.GetChannelPlaylistContext(context.Background(), item, vidCount)
+}
+
+// GetChannelPlaylistContext is like GetChannelPlaylist but uses ctx for every page and video request.
+func (yt *YoutubeApi) GetChannelPlaylistContext(ctx context.Context, item *Item, vidCount int) (_ *VideoResults, err error) {
+	ctx, span := yt.startOperation(ctx, "GetChannelPlaylist")
+	defer func() { endSpan(span, err) }()
+
+	cacheKey := CacheKey("channelUploads", url.Values{"channelId": {item.Id}, "maxResults": {strconv.Itoa(vidCount)}})
 	if v := yt.Cache.GetPlaylist(cacheKey); v != nil {
-		return v, nil
+		span.SetAttributes(AttrCacheHit.Bool(true))
+		return v.shallowCopy(), nil
 	}
+	span.SetAttributes(AttrCacheHit.Bool(false))
 
 	if item.ContentDetails != nil && item.ContentDetails.RelatedPlaylists != nil {
-		results, err := yt.getChannelPlaylist(item.ContentDetails.RelatedPlaylists.Uploads, vidCount)
-		if err != nil {
-			return nil, errors.New("internal server error")
-		}
-		if results == nil {
-			return nil, errors.New("no results found")
-		}
+		v, err := yt.coalesce(ctx, RegionPlaylists, cacheKey, func() (interface{}, error) {
+			results, err := yt.getChannelPlaylist(ctx, item.ContentDetails.RelatedPlaylists.Uploads, "", vidCount)
+			if err != nil {
+				return nil, fmt.Errorf("internal server error: %w", err)
+			}
+			if results == nil {
+				return nil, errors.New("no results found")
+			}
 
-		// If no error and results obtained, add to cache
-		yt.Cache.SetPlaylist(cacheKey, results)
+			// If no error and results obtained, add to cache
+			yt.Cache.SetPlaylist(cacheKey, results)
+			yt.persistPlaylist(ctx, item.ContentDetails.RelatedPlaylists.Uploads, results)
 
-		return results, nil
+			return results, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return v.(*VideoResults).shallowCopy(), nil
 	} else {
-		// If no error and results obtained, add to cache
-		yt.Cache.SetPlaylist(cacheKey, nil)
-
 		return nil, errors.New("contentDetails or RelatedPlaylists are nil")
 	}
 }
@@ -139,7 +215,7 @@ type TagSearchResults struct {
 			VideoId string `bson:"videoId,omitempty" json:"videoId,omitempty"`
 		} `bson:"id,omitempty" json:"id,omitempty"`
 		Snippet *struct {
-			PublishedAt  string     `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+			PublishedAt  time.Time  `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
 			Title        string     `bson:"title,omitempty" json:"title,omitempty"`
 			Description  string     `bson:"description,omitempty" json:"description,omitempty"`
 			ChannelTitle string     `bson:"channelTitle,omitempty" json:"channelTitle,omitempty"`
@@ -150,24 +226,31 @@ type TagSearchResults struct {
 	NextPageToken string `bson:"nextPageToken,omitempty" json:"nextPageToken,omitempty"`
 }
 
-// Thumbnails represents different sizes of image URLs for a video
-// The default thumbnail size
+// Thumbnail is a single thumbnail image.
+type Thumbnail struct {
+	Url    string `bson:"url,omitempty" json:"url,omitempty"`
+	Width  int    `bson:"width,omitempty" json:"width,omitempty"`
+	Height int    `bson:"height,omitempty" json:"height,omitempty"`
+}
+
+// Thumbnails represents different sizes of image URLs for a video.
+// Standard and Maxres are only present when the uploaded video is large enough.
 type Thumbnails struct {
-	Default *struct {
-		Url    string `bson:"url,omitempty" json:"url,omitempty"`
-		Width  int    `bson:"width,omitempty" json:"width,omitempty"`
-		Height int    `bson:"height,omitempty" json:"height,omitempty"`
-	} `bson:"default,omitempty" json:"default,omitempty"`
-	Medium *struct {
-		Url    string `bson:"url,omitempty" json:"url,omitempty"`
-		Width  int    `bson:"width,omitempty" json:"width,omitempty"`
-		Height int    `bson:"height,omitempty" json:"height,omitempty"`
-	} `bson:"medium,omitempty" json:"medium,omitempty"`
-	High *struct {
-		Url    string `bson:"url,omitempty" json:"url,omitempty"`
-		Width  int    `bson:"width,omitempty" json:"width,omitempty"`
-		Height int    `bson:"height,omitempty" json:"height,omitempty"`
-	} `bson:"high,omitempty" json:"high,omitempty"`
+	Default  *Thumbnail `bson:"default,omitempty" json:"default,omitempty"`
+	Medium   *Thumbnail `bson:"medium,omitempty" json:"medium,omitempty"`
+	High     *Thumbnail `bson:"high,omitempty" json:"high,omitempty"`
+	Standard *Thumbnail `bson:"standard,omitempty" json:"standard,omitempty"`
+	Maxres   *Thumbnail `bson:"maxres,omitempty" json:"maxres,omitempty"`
+}
+
+// BestAvailable returns the largest thumbnail present, or nil when there is none.
+func (t Thumbnails) BestAvailable() *Thumbnail {
+	for _, thumb := range []*Thumbnail{t.Maxres, t.Standard, t.High, t.Medium, t.Default} {
+		if thumb != nil && thumb.Url != "" {
+			return thumb
+		}
+	}
+	return nil
 }
 
 // ChannelPlaylistVideoResults represents the results of a channel playlist video search.
@@ -176,15 +259,15 @@ type ChannelPlaylistVideoResults struct {
 	Items []struct {
 		Id      string `bson:"id,omitempty" json:"id,omitempty"`
 		Snippet *struct {
-			PublishedAt  string     `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+			PublishedAt  time.Time  `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
 			Title        string     `bson:"title,omitempty" json:"title,omitempty"`
 			Description  string     `bson:"description,omitempty" json:"description,omitempty"`
 			Thumbnails   Thumbnails `bson:"thumbnails,omitempty" json:"thumbnails,omitempty"`
 			ChannelTitle string     `bson:"channelTitle,omitempty" json:"channelTitle,omitempty"`
 		} `bson:"snippet,omitempty" json:"snippet,omitempty"`
 		ContentDetails *struct {
-			VideoId          string `bson:"videoId,omitempty" json:"videoId,omitempty"`
-			VideoPublishedAt string `bson:"videoPublishedAt,omitempty" json:"videoPublishedAt,omitempty"`
+			VideoId          string    `bson:"videoId,omitempty" json:"videoId,omitempty"`
+			VideoPublishedAt time.Time `bson:"videoPublishedAt,omitempty" json:"videoPublishedAt,omitempty"`
 		} `bson:"contentDetails,omitempty" json:"contentDetails,omitempty"`
 	} `bson:"items,omitempty" json:"items,omitempty"`
 	PageInfo *struct {
@@ -198,8 +281,8 @@ type ChannelPlaylistVideoResults struct {
 // The ID field is a string that uniquely identifies the item.
 // The Snippet field contains additional details about the item such as its published date, title, description,
 // custom URL, channel title, thumbnails, localized title and description, and country.
-// The thumbnails field contains different sizes of thumbnails for the item, including default, medium, and high.
-// The Localized field contains localized title and description for the item.
+// The thumbnails field contains different sizes of thumbnails for the item, from default up to maxres.
+// The Localized field contains localized title and description for the item, and Localizations every translation of them.
 // The Country field specifies the country of the item.
 // The ContentDetails field contains additional details about the item's content,
 // such as related playlists for likes and uploads.
@@ -208,33 +291,14 @@ type ChannelPlaylistVideoResults struct {
 type Item struct {
 	Id      string `bson:"id,omitempty" json:"id,omitempty"`
 	Snippet *struct {
-		PublishedAt  string `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
-		Title        string `bson:"title,omitempty" json:"title,omitempty"`
-		Description  string `bson:"description,omitempty" json:"description,omitempty"`
-		CustomUrl    string `bson:"customUrl,omitempty" json:"customUrl,omitempty"`
-		ChannelTitle string `bson:"channelTitle,omitempty" json:"channelTitle,omitempty"`
-		Thumbnails   struct {
-			Default *struct {
-				Url    string `bson:"url,omitempty" json:"url,omitempty"`
-				Width  int    `bson:"width,omitempty" json:"width,omitempty"`
-				Height int    `bson:"height,omitempty" json:"height,omitempty"`
-			} `bson:"default,omitempty" json:"default,omitempty"`
-			Medium *struct {
-				Url    string `bson:"url,omitempty" json:"url,omitempty"`
-				Width  int    `bson:"width,omitempty" json:"width,omitempty"`
-				Height int    `bson:"height,omitempty" json:"height,omitempty"`
-			} `bson:"medium,omitempty" json:"medium,omitempty"`
-			High *struct {
-				Url    string `bson:"url,omitempty" json:"url,omitempty"`
-				Width  int    `bson:"width,omitempty" json:"width,omitempty"`
-				Height int    `bson:"height,omitempty" json:"height,omitempty"`
-			} `bson:"high,omitempty" json:"high,omitempty"`
-		} `bson:"thumbnails,omitempty" json:"thumbnails,omitempty"`
-		Localized *struct {
-			Title       string `bson:"title,omitempty" json:"title,omitempty"`
-			Description string `bson:"description,omitempty" json:"description,omitempty"`
-		}
-		Country string `bson:"country,omitempty" json:"country,omitempty"`
+		PublishedAt  time.Time     `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+		Title        string        `bson:"title,omitempty" json:"title,omitempty"`
+		Description  string        `bson:"description,omitempty" json:"description,omitempty"`
+		CustomUrl    string        `bson:"customUrl,omitempty" json:"customUrl,omitempty"`
+		ChannelTitle string        `bson:"channelTitle,omitempty" json:"channelTitle,omitempty"`
+		Thumbnails   Thumbnails    `bson:"thumbnails,omitempty" json:"thumbnails,omitempty"`
+		Localized    *Localization `bson:"localized,omitempty" json:"localized,omitempty"`
+		Country      string        `bson:"country,omitempty" json:"country,omitempty"`
 	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
 	ContentDetails *struct {
 		RelatedPlaylists *struct {
@@ -243,11 +307,14 @@ type Item struct {
 		} `bson:"relatedPlaylists,omitempty" json:"relatedPlaylists,omitempty"`
 	} `bson:"contentDetails,omitempty" json:"contentDetails,omitempty"`
 	Statistics *struct {
-		ViewCount             string `bson:"viewCount,omitempty" json:"viewCount,omitempty"`
-		SubscriberCount       string `bson:"subscriberCount,omitempty" json:"subscriberCount,omitempty"`
-		HiddenSubscriberCount bool   `bson:"hiddenSubscriberCount,omitempty" json:"hidden_subscriber_count,omitempty"`
-		VideoCount            string `bson:"videoCount,omitempty" json:"videoCount,omitempty"`
+		ViewCount             Count `bson:"viewCount,omitempty" json:"viewCount,omitempty"`
+		SubscriberCount       Count `bson:"subscriberCount,omitempty" json:"subscriberCount,omitempty"`
+		HiddenSubscriberCount bool  `bson:"hiddenSubscriberCount,omitempty" json:"hiddenSubscriberCount,omitempty"`
+		VideoCount            Count `bson:"videoCount,omitempty" json:"videoCount,omitempty"`
 	} `bson:"statistics,omitempty" json:"statistics,omitempty"`
+	// Localizations holds the translated metadata of the channel, keyed by language. It is only
+	// set when the localizations part is requested, as by GetChannelsLocalized.
+	Localizations map[string]Localization `bson:"localizations,omitempty" json:"localizations,omitempty"`
 }
 
 // ChannelInfo contains information about a YouTube channel and its videos.
@@ -261,6 +328,9 @@ type ChannelInfo struct {
 type VideoResults struct {
 	Items         []*Video `bson:"items,omitempty" json:"items,omitempty"`
 	NextPageToken string   `bson:"nextPageToken,omitempty" json:"nextPageToken,omitempty"`
+	// Unavailable lists the requested videos YouTube returned no details for, such as deleted and
+	// private videos of a playlist. It is only set by GetVideos and the playlist fetches.
+	Unavailable []*UnavailableVideo `bson:"unavailable,omitempty" json:"unavailable,omitempty"`
 }
 
 // Video represents a YouTube video.
@@ -270,21 +340,97 @@ type Video struct {
 	Snippet *struct {
 		ChannelId     string     `bson:"channelId,omitempty" json:"channelId,omitempty"`
 		ChannelTitle  string     `bson:"channelTitle,omitempty" json:"channelTitle,omitempty"`
-		PublishedAt   string     `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+		PublishedAt   time.Time  `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
 		Title         string     `bson:"title,omitempty" json:"title,omitempty"`
 		Description   string     `bson:"description,omitempty" json:"description,omitempty"`
 		Thumbnails    Thumbnails `bson:"thumbnails,omitempty" json:"thumbnails,omitempty"`
 		Tags          []string   `bson:"tags,omitempty" json:"tags,omitempty"`
 		FormattedTags string     `bson:"formatted_tags,omitempty" json:"formatted_tags,omitempty"`
+		// LiveBroadcastContent is "live", "upcoming", or "none".
+		LiveBroadcastContent string `bson:"liveBroadcastContent,omitempty" json:"liveBroadcastContent,omitempty"`
+		CategoryId           string `bson:"categoryId,omitempty" json:"categoryId,omitempty"`
+		// DefaultLanguage and DefaultAudioLanguage are the BCP-47 languages declared by the uploader, if any.
+		DefaultLanguage      string `bson:"defaultLanguage,omitempty" json:"defaultLanguage,omitempty"`
+		DefaultAudioLanguage string `bson:"defaultAudioLanguage,omitempty" json:"defaultAudioLanguage,omitempty"`
+		// Localized is the title and description in the language requested with Parts.Hl.
+		Localized *Localization `bson:"localized,omitempty" json:"localized,omitempty"`
 	} `bson:"snippet,omitempty" json:"snippet,omitempty"`
 
+	// Localizations holds the translated metadata of the video, keyed by language. It is only set
+	// when the localizations part is requested, see Parts.WithLocalizations.
+	Localizations map[string]Localization `bson:"localizations,omitempty" json:"localizations,omitempty"`
+
 	Statistics *struct {
-		ViewCount     string `bson:"viewCount,omitempty" json:"viewCount,omitempty"`
-		LikeCount     string `bson:"likeCount,omitempty" json:"likeCount,omitempty"`
-		DislikeCount  string `bson:"dislikeCount,omitempty" json:"dislikeCount,omitempty"`
-		FavoriteCount string `bson:"favoriteCount,omitempty" json:"favoriteCount,omitempty"`
-		CommentCount  string `bson:"commentCount,omitempty" json:"commentCount,omitempty"`
+		ViewCount     Count `bson:"viewCount,omitempty" json:"viewCount,omitempty"`
+		LikeCount     Count `bson:"likeCount,omitempty" json:"likeCount,omitempty"`
+		DislikeCount  Count `bson:"dislikeCount,omitempty" json:"dislikeCount,omitempty"`
+		FavoriteCount Count `bson:"favoriteCount,omitempty" json:"favoriteCount,omitempty"`
+		CommentCount  Count `bson:"commentCount,omitempty" json:"commentCount,omitempty"`
 	} `bson:"statistics,omitempty" json:"statistics,omitempty"`
+
+	Status *struct {
+		// UploadStatus is "uploaded", "processed", "failed", "rejected", or "deleted".
+		UploadStatus    string `bson:"uploadStatus,omitempty" json:"uploadStatus,omitempty"`
+		FailureReason   string `bson:"failureReason,omitempty" json:"failureReason,omitempty"`
+		RejectionReason string `bson:"rejectionReason,omitempty" json:"rejectionReason,omitempty"`
+		// PrivacyStatus is PrivacyPublic, PrivacyPrivate, or PrivacyUnlisted.
+		PrivacyStatus string    `bson:"privacyStatus,omitempty" json:"privacyStatus,omitempty"`
+		PublishAt     time.Time `bson:"publishAt,omitempty" json:"publishAt,omitempty"`
+		// License is "youtube" or "creativeCommon".
+		License                 string `bson:"license,omitempty" json:"license,omitempty"`
+		Embeddable              bool   `bson:"embeddable,omitempty" json:"embeddable,omitempty"`
+		PublicStatsViewable     bool   `bson:"publicStatsViewable,omitempty" json:"publicStatsViewable,omitempty"`
+		MadeForKids             bool   `bson:"madeForKids,omitempty" json:"madeForKids,omitempty"`
+		SelfDeclaredMadeForKids bool   `bson:"selfDeclaredMadeForKids,omitempty" json:"selfDeclaredMadeForKids,omitempty"`
+	} `bson:"status,omitempty" json:"status,omitempty"`
+
+	ContentDetails *struct {
+		Duration        Duration `bson:"duration,omitempty" json:"duration,omitempty"`
+		Dimension       string   `bson:"dimension,omitempty" json:"dimension,omitempty"`
+		Definition      string   `bson:"definition,omitempty" json:"definition,omitempty"`
+		Caption         string   `bson:"caption,omitempty" json:"caption,omitempty"`
+		LicensedContent bool     `bson:"licensedContent,omitempty" json:"licensedContent,omitempty"`
+		Projection      string   `bson:"projection,omitempty" json:"projection,omitempty"`
+		// ContentRating holds the ratings the video received under various rating schemes.
+		ContentRating *struct {
+			// YtRating is "ytAgeRestricted" for age-restricted videos.
+			YtRating   string `bson:"ytRating,omitempty" json:"ytRating,omitempty"`
+			MpaaRating string `bson:"mpaaRating,omitempty" json:"mpaaRating,omitempty"`
+			TvpgRating string `bson:"tvpgRating,omitempty" json:"tvpgRating,omitempty"`
+			BbfcRating string `bson:"bbfcRating,omitempty" json:"bbfcRating,omitempty"`
+			FskRating  string `bson:"fskRating,omitempty" json:"fskRating,omitempty"`
+		} `bson:"contentRating,omitempty" json:"contentRating,omitempty"`
+		// RegionRestriction lists the ISO 3166-1 alpha-2 codes of the regions the video is allowed or
+		// blocked in. An empty, non-nil Allowed list means the video is blocked everywhere, so it
+		// isn't omitted when encoded.
+		RegionRestriction *struct {
+			Allowed []string `bson:"allowed" json:"allowed"`
+			Blocked []string `bson:"blocked,omitempty" json:"blocked,omitempty"`
+		} `bson:"regionRestriction,omitempty" json:"regionRestriction,omitempty"`
+	} `bson:"contentDetails,omitempty" json:"contentDetails,omitempty"`
+
+	LiveStreamingDetails *struct {
+		ActualStartTime    time.Time `bson:"actualStartTime,omitempty" json:"actualStartTime,omitempty"`
+		ActualEndTime      time.Time `bson:"actualEndTime,omitempty" json:"actualEndTime,omitempty"`
+		ScheduledStartTime time.Time `bson:"scheduledStartTime,omitempty" json:"scheduledStartTime,omitempty"`
+		ScheduledEndTime   time.Time `bson:"scheduledEndTime,omitempty" json:"scheduledEndTime,omitempty"`
+		ConcurrentViewers  Count     `bson:"concurrentViewers,omitempty" json:"concurrentViewers,omitempty"`
+		ActiveLiveChatId   string    `bson:"activeLiveChatId,omitempty" json:"activeLiveChatId,omitempty"`
+	} `bson:"liveStreamingDetails,omitempty" json:"liveStreamingDetails,omitempty"`
+
+	TopicDetails *struct {
+		TopicIds         []string `bson:"topicIds,omitempty" json:"topicIds,omitempty"`
+		RelevantTopicIds []string `bson:"relevantTopicIds,omitempty" json:"relevantTopicIds,omitempty"`
+		// TopicCategories are Wikipedia URLs describing the video's content.
+		TopicCategories []string `bson:"topicCategories,omitempty" json:"topicCategories,omitempty"`
+	} `bson:"topicDetails,omitempty" json:"topicDetails,omitempty"`
+
+	// Channel summarizes the uploading channel. It is not part of the API response and is only
+	// set by EnrichWithChannelStats.
+	Channel *ChannelSummary `bson:"channel,omitempty" json:"channel,omitempty"`
+	// Chapters lists the chapters of the video's description. It is not part of the API response
+	// and is only set by VideoResults.EnrichWithChapters.
+	Chapters []Chapter `bson:"chapters,omitempty" json:"chapters,omitempty"`
 }
 
 // MinViews is the minimum number of views required for a video to be included in the results of the `FindTags` function.
@@ -295,135 +441,158 @@ type Video struct {
 // filteredItems := []*Video{}
 //
 //	for _, item := range vidResults.Items {
-//	    if item.Statistics.ViewCount != "" {
-//	        views, err := strconv.Atoi(item.Statistics.ViewCount)
-//	        if err != nil {
-//	            log.Printf("Failed to convert view count to integer, error: %v\n", err)
-//	            return nil, err
-//	        }
-//	        if views > MinViews {
-//	            // Append `item` to `filteredItems` if view count is greater than `MinViews`
-//	            filteredItems = append(filteredItems, (*Video)(item))
-//	        }
-const MinViews int = 1000
+//	    if item.Statistics != nil && item.Statistics.ViewCount > MinViews {
+//	        // Append `item` to `filteredItems` if view count is greater than `MinViews`
+//	        filteredItems = append(filteredItems, item)
+//	    }
+const MinViews Count = 1000
 
 // FindTags searches for videos on YouTube based on the input string and returns the videos along with their information.
 // It takes the input string and the number of pages to search through as parameters.
-// The function also accepts optional parameters as a map[string]interface{}.
-//
-// The videos are searched by replacing spaces in the input string with proper URL formatting.
-// The nextPage variable is used to keep track of the next page of search results.
-// The pageVar contains the formatting for the pageToken parameter in the API URL.
+// The function also accepts optional SearchOptions refining the search requests.
 //
-// The function defines a struct, VidSnippetInfo, to store information for aggregation with the search results.
-// The information includes the channel title, channel ID, and thumbnails of the video.
-// The vidIds map is used to store the video IDs as keys and the corresponding VidSnippetInfo structs as values.
-//
-// The function performs the search in a loop for the specified number of pages.
-// It constructs the URL for the API request using the fSearch input, the API key, and the nextPageStr (if applicable).
-// The response from the HTTP request
-func (yt *YoutubeApi) FindTags(input string, numPages int, optionalParams ...map[string]interface{}) (*VideoResults, error) {
+// On a cache miss, findTags requests each page with searchPage, following the NextPageToken of
+// the previous page, and stops early when YouTube has no more results. collectSearchResults gathers the video IDs of the
+// pages along with their channel and thumbnails, then the details of the videos are fetched in
+// batches and the videos with more than MinViews views are kept, in search order.
+// The results are cached for the query, the options, and the number of pages.
+func (yt *YoutubeApi) FindTags(input string, numPages int, opts ...SearchOptions) (*VideoResults, error) {
+	return yt.FindTagsContext(context.Background(), input, numPages, opts...)
+}
+
+// FindTagsContext is like FindTags but uses ctx for every search and video request,
+// so long paginated searches can be cancelled or bounded by a deadline.
+// The NextPageToken of the results resumes the search when given as SearchOptions.PageToken.
+func (yt *YoutubeApi) FindTagsContext(ctx context.Context, input string, numPages int, opts ...SearchOptions) (_ *VideoResults, err error) {
+	ctx, span := yt.startOperation(ctx, "FindTags")
+	defer func() { endSpan(span, err) }()
+
+	searchOpts := firstSearchOptions(opts)
+	cacheKey := searchOpts.cacheKey(input, numPages)
 	// check if input already in videoCache and if so, return cached result
-	if v := yt.Cache.GetVideo(input); v != nil {
-		return v, nil
+	if v := yt.Cache.GetVideo(cacheKey); v != nil {
+		span.SetAttributes(AttrCacheHit.Bool(true))
+		return searchOpts.arrange(v, yt.languages), nil
 	}
+	span.SetAttributes(AttrCacheHit.Bool(false))
 
-	var videos = make([]string, 0)
-	fSearch := strings.Replace(input, " ", "%20", -1) // Corrected replacement string
-	nextPage := ""
-	pageVar := "&pageToken=%v"
-
-	type VidSnippetInfo struct {
-		ChannelTitle string
-		ChannelId    string
-		Thumbnails   Thumbnails
+	v, err := yt.coalesce(ctx, RegionVideos, cacheKey, func() (interface{}, error) {
+		return yt.findTags(ctx, input, numPages, searchOpts, cacheKey)
+	})
+	if err != nil {
+		return nil, err
 	}
-	vidIds := make(map[string]VidSnippetInfo)
+	return searchOpts.arrange(v.(*VideoResults), yt.languages), nil
+}
+
+// findTags runs the searches of FindTags, fetches the details of the videos found, and caches the result.
+func (yt *YoutubeApi) findTags(ctx context.Context, input string, numPages int, searchOpts SearchOptions, cacheKey string) (*VideoResults, error) {
+	var videos = make([]string, 0)
+	nextPage := searchOpts.PageToken
+	vidIds := make(map[string]vidSnippetInfo)
 
 	for i := 0; i < numPages; i++ {
-		nextPageStr := ""
-		if nextPage != "" { // Use nextPage value to continue the loop
-			nextPageStr = fmt.Sprintf(pageVar, nextPage)
-		} else if i > 0 { // Break the loop if nextPage is empty and not on the first iteration
+		if nextPage == "" && i > 0 { // Break the loop if nextPage is empty and not on the first iteration
 			break
 		}
 
-		pageUrl := fmt.Sprintf(SearchVideoIds, fSearch, yt.ApiKey(), nextPageStr)
-
-		resp, err := http.Get(pageUrl)
-		if err != nil {
-			log.Printf("Failed HTTP request, error: %v\n", err)
-			return nil, err
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			log.Printf("Failed reading body, error: %v\n", err)
-			return nil, err
-		}
-
-		res := TagSearchResults{}
-		err = json.Unmarshal(body, &res)
+		res, err := yt.searchPage(withSpanAttributes(ctx, AttrPage.Int(i)), input, nextPage, searchOpts)
 		if err != nil {
-			log.Printf("Error unmarshaling response to struct, error: %v\n", err)
 			return nil, err
 		}
-
-		for _, vid := range res.Items {
-			videos = append(videos, vid.Id.VideoId)
-			vidIds[vid.Id.VideoId] = VidSnippetInfo{
-				ChannelTitle: vid.Snippet.ChannelTitle,
-				ChannelId:    vid.Snippet.ChannelId,
-				Thumbnails:   vid.Snippet.Thumbnails,
-			}
-		}
+		videos = collectSearchResults(res, videos, vidIds)
 
 		nextPage = res.NextPageToken
 		if nextPage == "" { // Break the loop if there's no nextPageToken
 			break
 		}
 	}
-	vidResults, err := yt.GetVideos(videos)
+	details, err := yt.GetVideosWithParts(ctx, videos, searchOpts.Parts)
 	if err != nil {
-		log.Printf("Failed to get videos, error: %v\n", err)
+		yt.logger.Error("failed to get videos", Field{"error", err})
 		return nil, err
 	}
-	var filteredItems []*Video
-	for _, item := range vidResults.Items {
-		if item.Statistics.ViewCount != "" {
-			views, err := strconv.Atoi(item.Statistics.ViewCount)
-			if err != nil {
-				log.Printf("Failed to convert view count to integer, error: %v\n", err)
-				return nil, err
-			}
-			if views > MinViews {
-				if snippetInfo, ok := vidIds[item.Id]; ok {
-					item.Snippet.ChannelId = snippetInfo.ChannelId
-					item.Snippet.ChannelTitle = snippetInfo.ChannelTitle
-					item.Snippet.Thumbnails = snippetInfo.Thumbnails
-				}
-				filteredItems = append(filteredItems, (*Video)(item))
-			}
-		}
-	}
-	vidResults.Items = filteredItems
+	// Filter into a new result, since details may be shared with the video details cache. Its
+	// NextPageToken continues the search, given as SearchOptions.PageToken.
+	vidResults := &VideoResults{Items: filterSearchVideos(details.Items, vidIds), NextPageToken: nextPage}
 
 	// update videoCache with new results
-	yt.Cache.SetVideo(input, vidResults)
+	yt.Cache.SetVideo(cacheKey, vidResults)
 
 	return vidResults, nil
 }
 
-// getChannelInfo hits the channel endpoint and returns the channel information
-func getChannelInfo(channelId string) (*ChannelInfo, error) {
-	pageUrl := fmt.Sprintf(GetChannelVideos, channelId, GetInstance().apiKey)
+// vidSnippetInfo holds the search snippet fields aggregated into the video details of a search result.
+type vidSnippetInfo struct {
+	ChannelTitle string
+	ChannelId    string
+	Thumbnails   Thumbnails
+}
 
-	resp, err := http.Get(pageUrl)
+// applyTo copies the search snippet fields into the snippet of video, when it was fetched.
+func (info vidSnippetInfo) applyTo(video *Video) {
+	if video.Snippet == nil {
+		return
+	}
+	video.Snippet.ChannelId = info.ChannelId
+	video.Snippet.ChannelTitle = info.ChannelTitle
+	video.Snippet.Thumbnails = info.Thumbnails
+}
+
+// searchPage fetches a single page of search results for query.
+func (yt *YoutubeApi) searchPage(ctx context.Context, query, pageToken string, opts SearchOptions) (*TagSearchResults, error) {
+	pageUrl := yt.searchUrl(query, pageToken, opts)
+
+	body, err := yt.httpGetRequest(ctx, pageUrl)
 	if err != nil {
+		yt.logger.Error("failed search request", Field{"url", RedactURL(pageUrl)}, Field{"error", err})
 		return nil, err
 	}
-	body, err := io.ReadAll(resp.Body)
+
+	res := &TagSearchResults{}
+	err = json.Unmarshal(body, res)
+	if err != nil {
+		yt.logger.Error("failed to unmarshal search response", Field{"error", err})
+		return nil, err
+	}
+	return res, nil
+}
+
+// collectSearchResults appends the video IDs of a search page to videos and records their snippet info.
+func collectSearchResults(res *TagSearchResults, videos []string, vidIds map[string]vidSnippetInfo) []string {
+	for _, vid := range res.Items {
+		if vid.Id == nil || vid.Snippet == nil {
+			continue
+		}
+		videos = append(videos, vid.Id.VideoId)
+		vidIds[vid.Id.VideoId] = vidSnippetInfo{
+			ChannelTitle: vid.Snippet.ChannelTitle,
+			ChannelId:    vid.Snippet.ChannelId,
+			Thumbnails:   vid.Snippet.Thumbnails,
+		}
+	}
+	return videos
+}
+
+// filterSearchVideos keeps the videos with more than MinViews views and merges in their search snippet info.
+func filterSearchVideos(items []*Video, vidIds map[string]vidSnippetInfo) []*Video {
+	var filteredItems []*Video
+	for _, item := range items {
+		if item.Statistics != nil && item.Statistics.ViewCount > MinViews {
+			if snippetInfo, ok := vidIds[item.Id]; ok {
+				snippetInfo.applyTo(item)
+			}
+			filteredItems = append(filteredItems, item)
+		}
+	}
+	return filteredItems
+}
+
+// getChannelInfo hits the channel endpoint and returns the channel information
+func (yt *YoutubeApi) getChannelInfo(ctx context.Context, channelId string) (*ChannelInfo, error) {
+	pageUrl := fmt.Sprintf(GetChannelVideos, channelId, yt.apiKey)
+
+	body, err := yt.httpGetRequest(ctx, pageUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -438,21 +607,25 @@ func getChannelInfo(channelId string) (*ChannelInfo, error) {
 	return &res, nil
 }
 
-// getChannelPlaylist hits the playlist endpoint, returning playlist information
-func (yt *YoutubeApi) getChannelPlaylist(playlistId string, numItems int) (*VideoResults, error) {
+// getChannelPlaylist returns the videos of the pages of a playlist holding numItems items, starting
+// at pageToken, or at the first page when it is empty. The NextPageToken of the results continues
+// the playlist after those pages.
+func (yt *YoutubeApi) getChannelPlaylist(ctx context.Context, playlistId, pageToken string, numItems int) (*VideoResults, error) {
 	numPages := calculateNumPages(numItems)
 
-	videos, thumbnails, err := fetchPlaylistVideos(playlistId, numPages)
+	videos, thumbnails, titles, nextPage, err := yt.fetchPlaylistVideos(ctx, playlistId, pageToken, numPages)
 	if err != nil {
 		return nil, err
 	}
 
-	getVideos, err := yt.GetVideos(videos)
+	getVideos, err := yt.GetVideosContext(ctx, videos)
 	if err != nil {
 		return nil, err
 	}
 
-	return processVideoItems(getVideos, thumbnails), nil
+	results := processVideoItems(getVideos, thumbnails)
+	// A new VideoResults, since getVideos may be shared with the video details cache.
+	return &VideoResults{Items: results.Items, NextPageToken: nextPage, Unavailable: unavailableVideos(videos, results, titles)}, nil
 }
 
 func calculateNumPages(numItems int) int {
@@ -463,45 +636,50 @@ func calculateNumPages(numItems int) int {
 	return numPages
 }
 
-func fetchPlaylistVideos(playlistId string, numPages int) ([]string, map[string]Thumbnails, error) {
+// fetchPlaylistVideos returns the video IDs of up to numPages pages of a playlist starting at
+// pageToken, along with the thumbnails and titles of their playlist items and the token of the
+// page following them.
+func (yt *YoutubeApi) fetchPlaylistVideos(ctx context.Context, playlistId, pageToken string, numPages int) ([]string, map[string]Thumbnails, map[string]string, string, error) {
 	var videos []string
-	nextPage := ""
+	nextPage := pageToken
 	thumbnails := make(map[string]Thumbnails)
+	titles := make(map[string]string)
 
 	for i := 0; i < numPages; i++ {
-		pageUrl := generatePageUrl(playlistId, nextPage, i)
-		res, err := fetchVideoResultsFromAPI(pageUrl)
+		pageUrl := yt.generatePageUrl(playlistId, nextPage, i)
+		res, err := yt.fetchVideoResultsFromAPI(withSpanAttributes(ctx, AttrPage.Int(i)), pageUrl)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, "", err
 		}
 
 		for _, vid := range res.Items {
+			if vid.ContentDetails == nil {
+				continue
+			}
 			videos = append(videos, vid.ContentDetails.VideoId)
-			thumbnails[vid.ContentDetails.VideoId] = vid.Snippet.Thumbnails
+			if vid.Snippet != nil {
+				thumbnails[vid.ContentDetails.VideoId] = vid.Snippet.Thumbnails
+				titles[vid.ContentDetails.VideoId] = vid.Snippet.Title
+			}
 		}
 		nextPage = res.NextPageToken
 		if nextPage == "" {
 			break
 		}
 	}
-	return videos, thumbnails, nil
+	return videos, thumbnails, titles, nextPage, nil
 }
 
-func generatePageUrl(playlistId, nextPage string, pageNum int) string {
+func (yt *YoutubeApi) generatePageUrl(playlistId, nextPage string, pageNum int) string {
 	nextPageStr := ""
-	if pageNum > 0 {
+	if nextPage != "" {
 		nextPageStr = fmt.Sprintf("&pageToken=%v", nextPage)
 	}
-	return fmt.Sprintf(GetChannelPlaylist, playlistId, GetInstance().apiKey, nextPageStr)
+	return fmt.Sprintf(GetChannelPlaylist, playlistId, yt.apiKey, nextPageStr)
 }
 
-func fetchVideoResultsFromAPI(url string) (*ChannelPlaylistVideoResults, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+func (yt *YoutubeApi) fetchVideoResultsFromAPI(ctx context.Context, url string) (*ChannelPlaylistVideoResults, error) {
+	body, err := yt.httpGetRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -516,14 +694,15 @@ func fetchVideoResultsFromAPI(url string) (*ChannelPlaylistVideoResults, error)
 
 func processVideoItems(videos *VideoResults, thumbnails map[string]Thumbnails) *VideoResults {
 	for _, item := range videos.Items {
-		if thumbs, ok := thumbnails[item.Id]; ok {
+		if thumbs, ok := thumbnails[item.Id]; ok && item.Snippet != nil {
 			item.Snippet.Thumbnails = thumbs
 		}
 	}
 	return videos
 }
 
-// GetVideos hits the YouTube API to retrieve video information for the given input video IDs. It paginates through the input videos and aggregates the results into a single Video
+// batchIteration joins input into comma-separated batches of at most 50 IDs,
+// the most the videos endpoint accepts per request.
 func batchIteration(input []string) []string {
 	var results []string
 	for i := 0; i < len(input); i += 50 {
@@ -536,25 +715,126 @@ func batchIteration(input []string) []string {
 	return results
 }
 
-func httpGetRequest(apiUrl string) ([]byte, error) {
-	resp, err := http.Get(apiUrl)
+// httpGetRequest performs a GET request through the client's http.Client and returns the response body.
+// Transient failures are retried according to the client's RetryPolicy.
+func (yt *YoutubeApi) httpGetRequest(ctx context.Context, apiUrl string) ([]byte, error) {
+	return yt.sendRequest(ctx, http.MethodGet, apiUrl, nil, authAuto)
+}
+
+// userGetRequest is like httpGetRequest but always authenticates with the client's OAuth2 credentials,
+// as required by user-scoped endpoints such as mine=true listings.
+func (yt *YoutubeApi) userGetRequest(ctx context.Context, apiUrl string) ([]byte, error) {
+	return yt.sendRequest(ctx, http.MethodGet, apiUrl, nil, authUser)
+}
+
+// userJSONRequest sends in as a JSON payload to a user-scoped endpoint authenticated with OAuth2
+// and decodes the response into out. Either in or out may be nil.
+func (yt *YoutubeApi) userJSONRequest(ctx context.Context, method, apiUrl string, in, out interface{}) error {
+	var payload []byte
+	if in != nil {
+		var err error
+		payload, err = json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+	body, err := yt.sendRequest(ctx, method, apiUrl, payload, authUser)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	return nil
+}
+
+// sendRequest performs a request with an optional JSON payload, retrying transient failures
+// according to the client's RetryPolicy.
+func (yt *YoutubeApi) sendRequest(ctx context.Context, method, apiUrl string, payload []byte, auth authMode) (_ []byte, err error) {
+	ctx, span := yt.startRequestSpan(ctx, method, apiUrl)
+	defer func() { endSpan(span, err) }()
+
+	for attempt := 0; ; attempt++ {
+		body, resp, err := yt.doRequest(ctx, method, apiUrl, payload, auth)
+		span.SetAttributes(AttrAttempts.Int(attempt + 1))
+		if resp != nil {
+			span.SetAttributes(attrStatus.Int(resp.StatusCode))
+		}
+		if err == nil {
+			return body, nil
+		}
+		// POST requests are not idempotent, so they are only repeated when YouTube rejected them outright.
+		retryable := shouldRetry(ctx, resp, err) && (method != http.MethodPost || (resp != nil && resp.StatusCode == http.StatusTooManyRequests))
+		if attempt+1 >= yt.retry.MaxAttempts || !retryable {
+			return nil, err
+		}
+		delay := yt.retry.backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp.Header); ok {
+				delay = d
+			}
+		}
+		yt.logger.Warn("retrying request", Field{"url", RedactURL(apiUrl)}, Field{"delay", delay}, Field{"attempt", attempt + 1}, Field{"error", err})
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// doRequest performs a single request attempt. The returned response, when not nil,
+// has already been read and closed and is only meant for inspecting the status and headers.
+func (yt *YoutubeApi) doRequest(ctx context.Context, method, apiUrl string, payload []byte, auth authMode) (_ []byte, resp *http.Response, err error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiUrl, reqBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed creating request, error: %w", redactError(err))
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	yt.setEncodingHeaders(req)
+	if err := yt.authorize(req, auth); err != nil {
+		return nil, nil, err
+	}
+	if err := yt.waitRateLimit(ctx, req.URL); err != nil {
+		return nil, nil, err
+	}
+	if yt.observer != nil {
+		start := time.Now()
+		defer func() {
+			info := RequestInfo{Endpoint: endpointName(req.URL), Method: method, Duration: time.Since(start), Err: err}
+			if resp != nil {
+				info.Status = resp.StatusCode
+			}
+			yt.observeRequest(info)
+		}()
+	}
+	resp, err = yt.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed HTTP request, error: %w", err)
+		return nil, nil, fmt.Errorf("failed HTTP request, error: %w", redactError(err))
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
 		if err != nil {
-			if err != nil {
-				log.Printf("error: %v\n", err)
-			}
+			yt.logger.Warn("failed closing response body", Field{"error", err})
 		}
 	}(resp.Body)
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed reading body, error: %w", err)
+		return nil, resp, fmt.Errorf("failed reading body, error: %w", err)
+	}
+	// Error envelopes are checked on 2xx responses too, since some endpoints report failures that way.
+	if !isSuccessStatus(resp.StatusCode) || parseApiError(body) != nil {
+		return nil, resp, statusError(resp.StatusCode, body)
 	}
-	return body, nil
+	return body, resp, nil
 }
 
 func unmarshalResponse(body []byte) (*VideoResults, error) {
@@ -566,51 +846,135 @@ func unmarshalResponse(body []byte) (*VideoResults, error) {
 	return res, nil
 }
 
+// GetVideos retrieves the details of videoIds, requesting them in batches of 50.
+// Videos are returned in the order of videoIds; deleted or private videos are omitted.
 func (yt *YoutubeApi) GetVideos(videoIds []string) (*VideoResults, error) {
-	// Convert slice of videoIds to string to use as cache key
-	videoIdsKey := strings.Join(videoIds, ",")
+	return yt.GetVideosContext(context.Background(), videoIds)
+}
+
+// GetVideosContext is like GetVideos but uses ctx for every batch request.
+func (yt *YoutubeApi) GetVideosContext(ctx context.Context, videoIds []string) (*VideoResults, error) {
+	return yt.getVideos(ctx, videoIds, yt.videoParts)
+}
+
+// getVideos fetches the details of videoIds in batches of 50, requesting the given parts.
+func (yt *YoutubeApi) getVideos(ctx context.Context, videoIds []string, parts Parts) (_ *VideoResults, err error) {
+	ctx, span := yt.startOperation(ctx, "GetVideos", AttrBatchSize.Int(len(videoIds)))
+	defer func() { endSpan(span, err) }()
+
+	videoIdsKey := CacheKey("videos", parts.keyValues(url.Values{"id": {strings.Join(videoIds, ",")}}, ""))
 
 	if v := yt.Cache.GetVideoDetail(videoIdsKey); v != nil {
-		return v, nil
+		span.SetAttributes(AttrCacheHit.Bool(true))
+		return v.shallowCopy(), nil
 	}
+	span.SetAttributes(AttrCacheHit.Bool(false))
 
-	input := batchIteration(videoIds)
-	finalProduct := VideoResults{}
-	pageVar := "&pageToken=%v"
+	v, err := yt.coalesce(ctx, RegionVideoDetails, videoIdsKey, func() (interface{}, error) {
+		results, err := yt.fetchVideos(ctx, videoIds, parts)
+		yt.prepareTags(results)
+		if err != nil {
+			return results, err
+		}
+		yt.Cache.SetVideoDetail(videoIdsKey, results)
+		yt.persistVideos(ctx, results)
+		return results, nil
+	})
+	results, _ := v.(*VideoResults)
+	return results.shallowCopy(), err
+}
 
-	for _, fSearch := range input {
-		nextPage := ""
-		for i := 0; i < int(math.Ceil(float64(len(input))/float64(10))); i++ {
-			nextPageStr := ""
-			if i > 0 {
-				nextPageStr = fmt.Sprintf(pageVar, nextPage)
-			}
-			apiUrl := fmt.Sprintf(GetTags, GetInstance().apiKey, fSearch, nextPageStr)
-			body, err := httpGetRequest(apiUrl)
+// fetchVideos requests the details of videoIds from the API in batches of 50 IDs, following
+// nextPageToken within each batch. Items are returned in the order of videoIds, duplicate IDs
+// once; IDs the API doesn't return, such as deleted or private videos, are listed in Unavailable.
+// On error the videos fetched so far are returned along with it.
+func (yt *YoutubeApi) fetchVideos(ctx context.Context, videoIds []string, parts Parts) (*VideoResults, error) {
+	videoIds = uniqueIds(videoIds)
+	byId := make(map[string]*Video, len(videoIds))
+
+	for _, batch := range batchIteration(videoIds) {
+		batchSize := strings.Count(batch, ",") + 1
+		pageToken := ""
+		for page := 0; ; page++ {
+			apiUrl := yt.videosUrl(batch, parts, pageToken)
+			batchCtx := withSpanAttributes(ctx, AttrBatchSize.Int(batchSize), AttrPage.Int(page))
+			body, err := yt.httpGetRequest(batchCtx, apiUrl)
 			if err != nil {
-				return &finalProduct, err
+				return orderVideos(videoIds, byId), err
 			}
 
 			res, err := unmarshalResponse(body)
 			if err != nil {
-				return &finalProduct, err
+				return orderVideos(videoIds, byId), err
+			}
+			for _, v := range res.Items {
+				if v != nil {
+					byId[v.Id] = v
+				}
 			}
 
-			nextPage = res.NextPageToken
-			if nextPage == "" {
+			// A repeated token would loop forever.
+			if res.NextPageToken == "" || res.NextPageToken == pageToken {
 				break
 			}
-
-			finalProduct.Items = append(finalProduct.Items, res.Items...)
+			pageToken = res.NextPageToken
 		}
 	}
 
-	yt.Cache.SetVideoDetail(videoIdsKey, &finalProduct)
+	results := orderVideos(videoIds, byId)
+	results.Unavailable = unavailableVideos(videoIds, results, nil)
+	return results, nil
+}
 
-	return &finalProduct, nil
+// uniqueIds returns ids without empty and duplicate IDs, keeping the first occurrence of each.
+func uniqueIds(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
 }
 
+// orderVideos returns the videos of byId in the order of ids.
+func orderVideos(ids []string, byId map[string]*Video) *VideoResults {
+	results := &VideoResults{}
+	for _, id := range ids {
+		if v, ok := byId[id]; ok {
+			results.Items = append(results.Items, v)
+		}
+	}
+	return results
+}
+
+// SearchAndRetrieveTags searches like FindTags over up to 5 pages (1 by default).
+// The results can be sorted in place, but their videos are shared with the cache and must not be
+// modified. SearchAndRetrieveTagsWithOptions orders and trims them with SearchOptions.Sort and
+// SearchOptions.Limit.
 func (yt *YoutubeApi) SearchAndRetrieveTags(search string, pages ...int) (*VideoResults, error) {
+	return yt.SearchAndRetrieveTagsContext(context.Background(), search, pages...)
+}
+
+// SearchAndRetrieveTagsContext is like SearchAndRetrieveTags but uses ctx for the underlying requests.
+func (yt *YoutubeApi) SearchAndRetrieveTagsContext(ctx context.Context, search string, pages ...int) (*VideoResults, error) {
+	return yt.FindTagsContext(ctx, search, searchAndRetrievePages(pages))
+}
+
+// SearchAndRetrieveTagsWithOptions is like SearchAndRetrieveTagsContext but refines the search
+// with opts, as FindTags does. The results are ordered by opts.Sort and trimmed to opts.Limit
+// videos on a copy, so they can be changed without affecting the cache:
+//
+//	top, err := yt.SearchAndRetrieveTagsWithOptions(ctx, "golang", alaitube.SearchOptions{Sort: alaitube.SortViews, Limit: 10}, 3)
+func (yt *YoutubeApi) SearchAndRetrieveTagsWithOptions(ctx context.Context, search string, opts SearchOptions, pages ...int) (*VideoResults, error) {
+	return yt.FindTagsContext(ctx, search, searchAndRetrievePages(pages), opts)
+}
+
+// searchAndRetrievePages returns the number of pages searched by SearchAndRetrieveTags.
+func searchAndRetrievePages(pages []int) int {
 	numPages := 1
 	if pages != nil {
 		if pages[0] > numPages {
@@ -621,5 +985,5 @@ func (yt *YoutubeApi) SearchAndRetrieveTags(search string, pages ...int) (*Video
 			}
 		}
 	}
-	return yt.FindTags(search, numPages)
+	return numPages
 }
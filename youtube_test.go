@@ -0,0 +1,178 @@
+package alaitube_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/josephalai/alaitube"
+	"github.com/josephalai/alaitube/testutil"
+)
+
+func videoIds(prefix string, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%s%08d", prefix, i)
+	}
+	return ids
+}
+
+// batchRecorder records the number of IDs of every videos request sent through it.
+type batchRecorder struct {
+	next    http.RoundTripper
+	mu      sync.Mutex
+	batches []int
+}
+
+func (r *batchRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/videos") {
+		r.mu.Lock()
+		r.batches = append(r.batches, len(strings.Split(req.URL.Query().Get("id"), ",")))
+		r.mu.Unlock()
+	}
+	return r.next.RoundTrip(req)
+}
+
+func TestGetVideos(t *testing.T) {
+	tests := []struct {
+		name string
+		// known are the videos the fake server has details for.
+		known []string
+		// pageSize caps the videos per response when set, so that responses carry a nextPageToken.
+		pageSize int
+		// request is the argument of GetVideos.
+		request []string
+		// want are the IDs of the returned videos, in order.
+		want []string
+		// wantBatches are the number of IDs of every videos request.
+		wantBatches     []int
+		wantUnavailable []string
+	}{
+		{
+			name:        "single page without next page token",
+			known:       []string{"a", "b", "c"},
+			request:     []string{"a", "b", "c"},
+			want:        []string{"a", "b", "c"},
+			wantBatches: []int{3},
+		},
+		{
+			name:        "next page token followed within a batch",
+			known:       []string{"a", "b", "c", "d", "e"},
+			pageSize:    2,
+			request:     []string{"a", "b", "c", "d", "e"},
+			want:        []string{"a", "b", "c", "d", "e"},
+			wantBatches: []int{5, 5, 5},
+		},
+		{
+			name:        "batches of 50 IDs",
+			known:       videoIds("v", 120),
+			request:     videoIds("v", 120),
+			want:        videoIds("v", 120),
+			wantBatches: []int{50, 50, 20},
+		},
+		{
+			name:        "duplicate IDs fetched once",
+			known:       []string{"a", "b"},
+			request:     []string{"a", "b", "a", "", "b", "a"},
+			want:        []string{"a", "b"},
+			wantBatches: []int{2},
+		},
+		{
+			name:            "input order with unknown IDs unavailable",
+			known:           []string{"a", "b", "c"},
+			request:         []string{"c", "gone", "a", "b", "missing"},
+			want:            []string{"c", "a", "b"},
+			wantBatches:     []int{5},
+			wantUnavailable: []string{"gone", "missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := testutil.NewServer()
+			defer srv.Close()
+			if tt.pageSize > 0 {
+				srv.SetPageSize(tt.pageSize)
+			}
+			for _, id := range tt.known {
+				srv.AddVideo(&alaitube.Video{Id: id})
+			}
+
+			client := srv.Client()
+			recorder := &batchRecorder{next: client.Transport}
+			client.Transport = recorder
+			yt := alaitube.NewClient(alaitube.WithApiKey("test"), alaitube.WithHttpClient(client))
+
+			results, err := yt.GetVideos(tt.request)
+			if err != nil {
+				t.Fatalf("GetVideos: %v", err)
+			}
+
+			var got []string
+			for _, v := range results.Items {
+				got = append(got, v.Id)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("videos = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(recorder.batches, tt.wantBatches) {
+				t.Errorf("batch sizes = %v, want %v", recorder.batches, tt.wantBatches)
+			}
+			var unavailable []string
+			for _, u := range results.Unavailable {
+				unavailable = append(unavailable, u.VideoId)
+			}
+			if !reflect.DeepEqual(unavailable, tt.wantUnavailable) {
+				t.Errorf("unavailable = %v, want %v", unavailable, tt.wantUnavailable)
+			}
+		})
+	}
+}
+
+// searchVideo returns a video titled title with enough views to be kept by FindTags.
+func searchVideo(t *testing.T, id, title string) *alaitube.Video {
+	t.Helper()
+	var v alaitube.Video
+	data := fmt.Sprintf(`{"id":%q,"snippet":{"title":%q},"statistics":{"viewCount":"5000"}}`, id, title)
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("decoding video %s: %v", id, err)
+	}
+	return &v
+}
+
+func TestFindTagsCachesEachPageCount(t *testing.T) {
+	srv := testutil.NewServer()
+	defer srv.Close()
+	srv.SetPageSize(2)
+	for i := 0; i < 5; i++ {
+		srv.AddVideo(searchVideo(t, fmt.Sprintf("cat%d", i), fmt.Sprintf("cats %d", i)))
+	}
+	yt := alaitube.NewClient(alaitube.WithApiKey("test"), alaitube.WithHttpClient(srv.Client()))
+
+	for _, tt := range []struct {
+		numPages     int
+		wantVideos   int
+		wantSearches int
+	}{
+		{numPages: 1, wantVideos: 2, wantSearches: 1},
+		{numPages: 3, wantVideos: 5, wantSearches: 4},
+		// Served from the cache.
+		{numPages: 1, wantVideos: 2, wantSearches: 4},
+		{numPages: 3, wantVideos: 5, wantSearches: 4},
+	} {
+		results, err := yt.FindTags("cats", tt.numPages)
+		if err != nil {
+			t.Fatalf("FindTags(%d pages): %v", tt.numPages, err)
+		}
+		if len(results.Items) != tt.wantVideos {
+			t.Errorf("FindTags(%d pages) returned %d videos, want %d", tt.numPages, len(results.Items), tt.wantVideos)
+		}
+		if got := srv.Requests("search"); got != tt.wantSearches {
+			t.Errorf("after FindTags(%d pages), %d search requests, want %d", tt.numPages, got, tt.wantSearches)
+		}
+	}
+}